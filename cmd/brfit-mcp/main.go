@@ -88,19 +88,30 @@ func main() {
 
 // SummarizeProjectInput defines the input for the summarize_project tool.
 type SummarizeProjectInput struct {
-	Path          string `json:"path,omitempty" jsonschema:"project directory path (defaults to server root)"`
-	Format        string `json:"format,omitempty" jsonschema:"output format: xml, md, or json (default: xml)"`
-	IncludeBody   bool   `json:"include_body,omitempty" jsonschema:"include function bodies (default: false)"`
-	IncludeImport bool   `json:"include_imports,omitempty" jsonschema:"include import statements (default: false)"`
-	CallGraph     bool   `json:"call_graph,omitempty" jsonschema:"include function call graph (default: false)"`
+	Path              string `json:"path,omitempty" jsonschema:"project directory path (defaults to server root)"`
+	Format            string `json:"format,omitempty" jsonschema:"output format: xml, md, or json (default: xml)"`
+	IncludeBody       bool   `json:"include_body,omitempty" jsonschema:"include function bodies (default: false)"`
+	IncludeImport     bool   `json:"include_imports,omitempty" jsonschema:"include import statements (default: false)"`
+	CallGraph         bool   `json:"call_graph,omitempty" jsonschema:"include function call graph (default: false)"`
+	Locations         bool   `json:"locations,omitempty" jsonschema:"include symbol IDs and source ranges in xml/md output; json always has them (default: false)"`
+	Structured        bool   `json:"structured,omitempty" jsonschema:"include parameters, return types, type parameters and modifiers as xml attributes; json always has them (default: false)"`
+	Profile           string `json:"profile,omitempty" jsonschema:"named profile from the project's .brfit.yaml/.brfit.toml (e.g. review, api-only)"`
+	MaxTokens         int    `json:"max_tokens,omitempty" jsonschema:"token budget; long docs, private symbols, then low-priority files are dropped to fit (default: no limit)"`
+	Mode              string `json:"mode,omitempty" jsonschema:"output mode: sig, outline (symbol names only), docs (doc comments), or full (raw source) (default: sig)"`
+	Visibility        string `json:"visibility,omitempty" jsonschema:"comma-separated visibility levels to include: public, protected, internal, package, file, private or all (default: public,protected, plus package in Java and internal in C# and Swift)"`
+	ExcludeDeprecated bool   `json:"exclude_deprecated,omitempty" jsonschema:"leave out symbols marked deprecated (default: false)"`
+	DocStyle          string `json:"doc_style,omitempty" jsonschema:"doc comment style: full, summary-line (first sentence), or params-only (summary plus param/returns/throws tags) (default: full)"`
 }
 
 // SummarizeProjectOutput defines the output for the summarize_project tool.
 type SummarizeProjectOutput struct {
-	Content         string `json:"content" jsonschema:"the formatted project summary"`
-	TotalFiles      int    `json:"total_files" jsonschema:"number of files processed"`
-	TotalSignatures int    `json:"total_signatures" jsonschema:"number of signatures extracted"`
-	TokenCount      int    `json:"token_count,omitempty" jsonschema:"estimated token count of output"`
+	Content         string   `json:"content" jsonschema:"the formatted project summary"`
+	TotalFiles      int      `json:"total_files" jsonschema:"number of files processed"`
+	TotalSignatures int      `json:"total_signatures" jsonschema:"number of signatures extracted"`
+	TokenCount      int      `json:"token_count,omitempty" jsonschema:"estimated token count of output"`
+	DroppedFiles    []string `json:"dropped_files,omitempty" jsonschema:"files dropped to fit max_tokens"`
+	DroppedDocs     int      `json:"dropped_docs,omitempty" jsonschema:"number of long doc comments dropped to fit max_tokens"`
+	DroppedPrivate  int      `json:"dropped_private,omitempty" jsonschema:"number of private symbols dropped to fit max_tokens"`
 }
 
 func makeSummarizeProject(defaultRoot string) func(context.Context, *mcp.CallToolRequest, SummarizeProjectInput) (*mcp.CallToolResult, SummarizeProjectOutput, error) {
@@ -115,29 +126,54 @@ func makeSummarizeProject(defaultRoot string) func(context.Context, *mcp.CallToo
 			return nil, SummarizeProjectOutput{}, fmt.Errorf("path not found: %s", path)
 		}
 
-		format, err := validateFormat(input.Format)
+		cfg, err := loadConfig(path, input.Profile)
 		if err != nil {
 			return nil, SummarizeProjectOutput{}, err
 		}
-
-		cfg := config.DefaultConfig()
-		cfg.Path = path
-		cfg.Format = format
-		cfg.IncludeBody = input.IncludeBody
-		cfg.IncludeImports = input.IncludeImport
-		cfg.CallGraph = input.CallGraph
+		if input.Format != "" {
+			format, err := validateFormat(input.Format)
+			if err != nil {
+				return nil, SummarizeProjectOutput{}, err
+			}
+			cfg.Format = format
+		}
+		// Tool parameters can only enable options; omitted ones keep the config value.
+		cfg.IncludeBody = cfg.IncludeBody || input.IncludeBody
+		cfg.IncludeImports = cfg.IncludeImports || input.IncludeImport
+		cfg.CallGraph = cfg.CallGraph || input.CallGraph
+		cfg.Locations = cfg.Locations || input.Locations
+		cfg.Structured = cfg.Structured || input.Structured
+		cfg.ExcludeDeprecated = cfg.ExcludeDeprecated || input.ExcludeDeprecated
+		if input.MaxTokens > 0 {
+			cfg.MaxTokens = input.MaxTokens
+		}
+		if input.Mode != "" {
+			cfg.Mode = input.Mode
+		}
+		if input.Visibility != "" {
+			cfg.Visibility = []string{input.Visibility}
+		}
+		if input.DocStyle != "" {
+			cfg.DocStyle = input.DocStyle
+		}
 
 		result, err := runPackager(ctx, cfg)
 		if err != nil {
 			return nil, SummarizeProjectOutput{}, err
 		}
 
-		return nil, SummarizeProjectOutput{
+		output := SummarizeProjectOutput{
 			Content:         string(result.Content),
 			TotalFiles:      result.TotalFiles,
 			TotalSignatures: result.TotalSignatures,
 			TokenCount:      result.TokenCount,
-		}, nil
+		}
+		if result.Trim != nil {
+			output.DroppedFiles = result.Trim.FilesDropped
+			output.DroppedDocs = result.Trim.DocsDropped
+			output.DroppedPrivate = result.Trim.PrivateDropped
+		}
+		return nil, output, nil
 	}
 }
 
@@ -166,14 +202,17 @@ func makeSummarizeFile(defaultRoot string) func(context.Context, *mcp.CallToolRe
 			return nil, SummarizeFileOutput{}, fmt.Errorf("path not found: %s", path)
 		}
 
-		format, err := validateFormat(input.Format)
+		cfg, err := loadConfig(path, "")
 		if err != nil {
 			return nil, SummarizeFileOutput{}, err
 		}
-
-		cfg := config.DefaultConfig()
-		cfg.Path = path
-		cfg.Format = format
+		if input.Format != "" {
+			format, err := validateFormat(input.Format)
+			if err != nil {
+				return nil, SummarizeFileOutput{}, err
+			}
+			cfg.Format = format
+		}
 		if input.Include != "" {
 			cfg.IncludePatterns = []string{input.Include}
 		}
@@ -277,6 +316,24 @@ func validateFormat(format string) (string, error) {
 	return format, nil
 }
 
+// loadConfig returns the default config for path layered with the same
+// user config, project config (.brfit.yaml/.brfit.toml) and BRFIT_*
+// environment variables that the brfit CLI reads. A non-empty profile
+// selects a named profile from the config files.
+func loadConfig(path, profile string) (*config.Config, error) {
+	cfg := config.DefaultConfig()
+	cfg.Path = path
+
+	layers, err := config.LoadLayers(path, &config.LoadOptions{Profile: profile})
+	if err != nil {
+		return nil, fmt.Errorf("configuration error: %w", err)
+	}
+	if _, err := cfg.Apply(layers, nil); err != nil {
+		return nil, fmt.Errorf("configuration error: %w", err)
+	}
+	return cfg, nil
+}
+
 // runPackager creates and runs a Packager with the given config.
 func runPackager(ctx context.Context, cfg *config.Config) (*pkgcontext.Result, error) {
 	if err := cfg.Validate(); err != nil {
@@ -39,6 +39,68 @@ func TestSummarizeProject(t *testing.T) {
 	}
 }
 
+func TestSummarizeProjectHonorsConfigFile(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, ".brfit.yaml"), []byte("format: json\ninclude-private: true\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "main.go"), []byte("package main\n\nfunc helper() {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	handler := makeSummarizeProject(tmpDir)
+	_, output, err := handler(context.Background(), &mcp.CallToolRequest{}, SummarizeProjectInput{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.HasPrefix(output.Content, "{") {
+		t.Errorf("expected JSON output from config file, got: %.40s", output.Content)
+	}
+	if !strings.Contains(output.Content, "func helper()") {
+		t.Error("expected private symbol from include-private in config file")
+	}
+
+	// An explicit tool parameter overrides the config file.
+	_, output, err = handler(context.Background(), &mcp.CallToolRequest{}, SummarizeProjectInput{Format: "xml"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(output.Content, "<brfit>") {
+		t.Error("expected format parameter to override config file")
+	}
+}
+
+func TestSummarizeProjectProfile(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, ".brfit.yaml"), []byte("profiles:\n  review:\n    format: md\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "main.go"), []byte("package main\n\nfunc Hello() {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	handler := makeSummarizeProject(tmpDir)
+	_, output, err := handler(context.Background(), &mcp.CallToolRequest{}, SummarizeProjectInput{Profile: "review"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(output.Content, "# Code Summary") {
+		t.Error("expected Markdown output from profile")
+	}
+
+	_, _, err = handler(context.Background(), &mcp.CallToolRequest{}, SummarizeProjectInput{Profile: "missing"})
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected unknown profile error, got: %v", err)
+	}
+}
+
 func TestSummarizeFile(t *testing.T) {
 	tmpDir := t.TempDir()
 	if err := os.MkdirAll(filepath.Join(tmpDir, "pkg"), 0755); err != nil {
@@ -0,0 +1,160 @@
+package main
+
+import (
+	gocontext "context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/indigo-net/Brf.it/internal/config"
+	"github.com/indigo-net/Brf.it/internal/context"
+	"github.com/spf13/cobra"
+)
+
+// newAPICheckCommand creates the "api-check" command, a CI gate that fails
+// when exported symbols are removed or their signatures change.
+func newAPICheckCommand() *cobra.Command {
+	c := config.DefaultConfig()
+	var allowlistPath string
+	cmd := &cobra.Command{
+		Use:   "api-check <base>[..<new>] [path] [options]",
+		Short: "Fail when exported signatures are removed or changed since a base revision",
+		Long: `Compare exported signatures between a base revision and the working tree
+(or <new>) and exit with status 1 if any exported symbol was removed or had
+its signature changed. Added symbols are never breaking.
+
+Intentional breaks can be listed in an allowlist file (--allowlist), one per
+line as "<path-glob> <name> [kind]"; text after "#" is a comment:
+
+  pkg/api.go       Less      # generic since v2
+  internal/**      *
+  src/client.ts    Client    class`,
+		Args:         cobra.RangeArgs(1, 2),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAPICheck(cmd, args, c, allowlistPath)
+		},
+	}
+
+	cmd.Flags().StringVar(&allowlistPath, "allowlist", "",
+		"file listing intentional breaking changes (\"<path-glob> <name> [kind]\" per line)")
+	cmd.Flags().StringArrayVarP(&c.IgnoreFiles, "ignore", "i", c.IgnoreFiles,
+		"custom ignore file(s), can be specified multiple times (default: .gitignore)")
+	cmd.Flags().StringArrayVar(&c.IncludePatterns, "include", c.IncludePatterns,
+		"glob pattern(s) to include, can be specified multiple times (e.g., \"pkg/**/*.go\")")
+	cmd.Flags().StringArrayVar(&c.ExcludePatterns, "exclude", c.ExcludePatterns,
+		"glob pattern(s) to exclude, can be specified multiple times (e.g., \"**/*_test.go\")")
+	cmd.Flags().BoolVar(&c.IncludeHidden, "include-hidden", c.IncludeHidden,
+		"include hidden files (dotfiles)")
+	cmd.Flags().Int64Var(&c.MaxFileSize, "max-size", c.MaxFileSize,
+		"maximum file size in bytes (default: 512000 = 500KB)")
+	cmd.Flags().DurationVar(&c.ParseTimeout, "parse-timeout", c.ParseTimeout,
+		"maximum time to parse a single file; slower files fail with a timeout error (0 = no limit)")
+	cmd.Flags().StringVar(&c.ConfigFile, "config", c.ConfigFile,
+		"config file path (default: discover .brfit.yaml/.brfit.toml from the target path)")
+	cmd.Flags().StringVar(&c.Profile, "profile", c.Profile,
+		"named profile from the config file(s) to apply (e.g., \"review\")")
+
+	return cmd
+}
+
+// runAPICheck is the main execution function for the api-check command.
+func runAPICheck(cmd *cobra.Command, args []string, c *config.Config, allowlistPath string) error {
+	c.Path = "."
+	if len(args) > 1 {
+		c.Path = args[1]
+	}
+	if _, err := os.Stat(c.Path); os.IsNotExist(err) {
+		return fmt.Errorf("path not found: %s", c.Path)
+	}
+	if absPath, err := filepath.Abs(c.Path); err == nil {
+		c.Path = absPath
+	}
+
+	if _, err := applyConfigFiles(cmd, c, false); err != nil {
+		return fmt.Errorf("configuration error: %w", err)
+	}
+	if err := c.Validate(); err != nil {
+		return fmt.Errorf("configuration error: %w", err)
+	}
+
+	var allow *context.Allowlist
+	if allowlistPath != "" {
+		var err error
+		if allow, err = context.LoadAllowlist(allowlistPath); err != nil {
+			return err
+		}
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = gocontext.Background()
+	}
+
+	oldRef, newRef, err := resolveDiffRange(ctx, c.Path, args[0])
+	if err != nil {
+		return err
+	}
+	oldSnap, err := extractRevision(ctx, c, oldRef)
+	if err != nil {
+		return err
+	}
+	newSnap, err := extractRevision(ctx, c, newRef)
+	if err != nil {
+		return err
+	}
+
+	warnErrorFiles(os.Stderr, append(oldSnap.ErrorFiles, newSnap.ErrorFiles...))
+
+	breaking := context.FindBreakingChanges(context.DiffSnapshots(oldSnap, newSnap), allow)
+	// A file that fails to extract would otherwise hide its removals.
+	unchecked := context.FindUncheckedFiles(oldSnap, newSnap)
+	for _, e := range allow.Unused() {
+		fmt.Fprintf(os.Stderr, "[brfit] WARN: %s:%d: allowlist entry %q matched no change\n", allowlistPath, e.Line, e.String())
+	}
+
+	if newRef == "" {
+		newRef = workingTreeRef
+	}
+	out := cmd.OutOrStdout()
+	failures := writeAPICheckReport(out, breaking)
+	for _, ef := range unchecked {
+		fmt.Fprintf(out, "%-8s  %s: %v\n", "FAILED", ef.Path, ef.Err)
+	}
+	fmt.Fprintf(out, "api-check %s..%s: %d breaking, %d allowed", oldRef, newRef, failures, len(breaking)-failures)
+	if len(unchecked) > 0 {
+		fmt.Fprintf(out, ", %d unchecked", len(unchecked))
+	}
+	fmt.Fprintln(out)
+
+	switch {
+	case failures > 0:
+		return fmt.Errorf("%d breaking API change(s) since %s", failures, oldRef)
+	case len(unchecked) > 0:
+		return fmt.Errorf("%d file(s) with exported symbols since %s could not be checked", len(unchecked), oldRef)
+	}
+	return nil
+}
+
+// writeAPICheckReport writes one entry per breaking change and returns the
+// number of changes that are not allowlisted.
+func writeAPICheckReport(w io.Writer, breaking []context.BreakingChange) int {
+	failures := 0
+	for _, b := range breaking {
+		status := "BREAKING"
+		if b.Allowed {
+			status = "allowed"
+		} else {
+			failures++
+		}
+		ch := b.Change
+		fmt.Fprintf(w, "%-8s  %-7s  %s:%d  %s (%s)\n", status, ch.Type, b.Path, ch.Old.Line, ch.Name, ch.Kind)
+		fmt.Fprintf(w, "    - %s\n", strings.ReplaceAll(ch.Old.Text, "\n", "\n      "))
+		if ch.New != nil {
+			fmt.Fprintf(w, "    + %s\n", strings.ReplaceAll(ch.New.Text, "\n", "\n      "))
+		}
+	}
+	return failures
+}
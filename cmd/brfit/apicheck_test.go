@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/indigo-net/Brf.it/internal/config"
+)
+
+func TestAPICheckCommand(t *testing.T) {
+	dir := setupDiffRepo(t)
+
+	tests := []struct {
+		name      string
+		allowlist string
+		wantErr   string
+		wantOut   []string
+	}{
+		{
+			name:    "no allowlist",
+			wantErr: "3 breaking API change(s) since v1",
+			wantOut: []string{
+				"BREAKING  changed  api/api.go:5  Change (function)",
+				"    - func Change(a int)",
+				"    + func Change(a int, b string) error",
+				"BREAKING  removed  api/api.go:7  Drop (function)",
+				"BREAKING  removed  gone/gone.go:3  Old (function)",
+				"api-check v1..v2: 3 breaking, 0 allowed",
+			},
+		},
+		{
+			name:      "partial allowlist",
+			allowlist: "gone/** *   # package removed on purpose\napi/api.go Drop\n",
+			wantErr:   "1 breaking API change(s)",
+			wantOut:   []string{"allowed   removed  api/api.go:7  Drop (function)", "1 breaking, 2 allowed"},
+		},
+		{
+			name:      "all allowed",
+			allowlist: "gone/** *\napi/api.go Drop\napi/api.go Change function\n",
+			wantOut:   []string{"0 breaking, 3 allowed"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := []string{"api-check", "v1..v2", dir}
+			if tt.allowlist != "" {
+				path := filepath.Join(t.TempDir(), "api-allow.txt")
+				if err := os.WriteFile(path, []byte(tt.allowlist), 0644); err != nil {
+					t.Fatal(err)
+				}
+				args = append(args, "--allowlist", path)
+			}
+
+			var buf bytes.Buffer
+			cmd := newRootCommandWithConfig(config.DefaultConfig())
+			cmd.SetOut(&buf)
+			cmd.SetErr(&bytes.Buffer{})
+			cmd.SetArgs(args)
+			err := cmd.Execute()
+
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("expected error containing %q, got: %v", tt.wantErr, err)
+			}
+			for _, want := range tt.wantOut {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("expected output to contain %q:\n%s", want, buf.String())
+				}
+			}
+		})
+	}
+}
+
+func TestAPICheckIgnoresAdditionsAndPrivateChanges(t *testing.T) {
+	dir := setupDiffRepo(t)
+	if err := os.WriteFile(filepath.Join(dir, "fresh", "fresh.go"),
+		[]byte("package fresh\n\ntype New struct{}\n\nfunc Extra() {}\n\nfunc helper(x int) {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	cmd := newRootCommandWithConfig(config.DefaultConfig())
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"api-check", "v2", filepath.Join(dir, "fresh")})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, buf.String())
+	}
+	if !strings.Contains(buf.String(), "api-check v2..working tree: 0 breaking, 0 allowed") {
+		t.Errorf("unexpected output:\n%s", buf.String())
+	}
+}
+
+func TestAPICheckFailsOnUnextractableFile(t *testing.T) {
+	dir := setupDiffRepo(t)
+	// A NUL byte makes the extractor reject the file as binary.
+	if err := os.WriteFile(filepath.Join(dir, "api", "api.go"), []byte("package api\x00\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	cmd := newRootCommandWithConfig(config.DefaultConfig())
+	cmd.SetOut(&buf)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"api-check", "v2", dir})
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "1 file(s) with exported symbols since v2 could not be checked") {
+		t.Fatalf("expected unchecked file error, got: %v\n%s", err, buf.String())
+	}
+	for _, want := range []string{"FAILED    api/api.go: skipping binary file", "0 breaking, 0 allowed, 1 unchecked"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected output to contain %q:\n%s", want, buf.String())
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/indigo-net/Brf.it/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// flagAliases maps config keys to additional flags that set the same field
+// (e.g., --schema clears no-schema, --no-skip-empty clears skip-empty).
+var flagAliases = map[string]string{
+	"no-schema":  "schema",
+	"skip-empty": "no-skip-empty",
+}
+
+// flagChanged reports whether the config key was set explicitly on the command line.
+func flagChanged(cmd *cobra.Command, key string) bool {
+	if cmd.Flags().Changed(key) {
+		return true
+	}
+	if alias, ok := flagAliases[key]; ok {
+		return cmd.Flags().Changed(alias)
+	}
+	return false
+}
+
+// applyConfigFiles layers the user config, the project config and BRFIT_*
+// environment variables onto c. Flags given explicitly on cmd take precedence.
+// When skipProject is true, project config discovery is skipped.
+func applyConfigFiles(cmd *cobra.Command, c *config.Config, skipProject bool) (config.Sources, error) {
+	layers, err := config.LoadLayers(c.Path, &config.LoadOptions{
+		ConfigFile:  c.ConfigFile,
+		SkipProject: skipProject,
+		Profile:     c.Profile,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c.Apply(layers, func(key string) bool {
+		return flagChanged(cmd, key)
+	})
+}
+
+// newConfigCommand creates the "config" command group.
+func newConfigCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect brfit configuration",
+		Long: `Inspect the configuration brfit resolves from its layered sources.
+
+Precedence (highest first):
+  1. command-line flags
+  2. BRFIT_* environment variables (e.g., BRFIT_FORMAT=md, BRFIT_EXCLUDE="a/**,b/**")
+  3. project config (.brfit.yaml, .brfit.yml or .brfit.toml, found from the target path upward)
+  4. user config (<user config dir>/brfit/config.yaml or config.toml)
+  5. built-in defaults
+
+A named profile (--profile or BRFIT_PROFILE) overrides the values of each
+config file that defines it:
+
+  profiles:
+    review:
+      include-body: true
+      format: md`,
+	}
+
+	cmd.AddCommand(newConfigPrintCommand())
+	return cmd
+}
+
+// newConfigPrintCommand creates the "config print" command, which accepts the
+// same flags as the root command so their effect can be inspected.
+func newConfigPrintCommand() *cobra.Command {
+	c := config.DefaultConfig()
+	cmd := &cobra.Command{
+		Use:   "print [path] [options]",
+		Short: "Print the effective configuration and where each value came from",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c.Path = "."
+			if len(args) > 0 {
+				c.Path = args[0]
+			}
+			if absPath, err := filepath.Abs(c.Path); err == nil {
+				c.Path = absPath
+			}
+
+			sources, err := applyConfigFiles(cmd, c, c.Remote != "")
+			if err != nil {
+				return fmt.Errorf("configuration error: %w", err)
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "KEY\tVALUE\tSOURCE")
+			for _, key := range config.Keys() {
+				fmt.Fprintf(w, "%s\t%s\t%s\n", key, c.Get(key), sources[key])
+			}
+			return w.Flush()
+		},
+	}
+
+	addFlags(cmd, c)
+	return cmd
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/indigo-net/Brf.it/internal/config"
+)
+
+// setupConfigProject creates a git-like project with a .brfit.yaml file and
+// isolates the test from the user's own config directory.
+func setupConfigProject(t *testing.T, configYAML string) string {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".brfit.yaml"), []byte(configYAML), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "lib.go"), []byte("package lib\n\nfunc Exported() {}\n\nfunc hidden() {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestRootCommandUsesProjectConfig(t *testing.T) {
+	dir := setupConfigProject(t, "format: md\ninclude-private: true\n")
+	outPath := filepath.Join(t.TempDir(), "out.md")
+
+	cmd := newRootCommandWithConfig(config.DefaultConfig())
+	cmd.SetArgs([]string{dir, "-o", outPath, "--no-tokens"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+
+	out, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), "# Code Summary") {
+		t.Error("expected Markdown output from project config")
+	}
+	if !strings.Contains(string(out), "func hidden()") {
+		t.Error("expected private symbol from include-private in project config")
+	}
+}
+
+func TestRootCommandFlagOverridesProjectConfig(t *testing.T) {
+	dir := setupConfigProject(t, "format: md\n")
+	outPath := filepath.Join(t.TempDir(), "out.xml")
+
+	cmd := newRootCommandWithConfig(config.DefaultConfig())
+	cmd.SetArgs([]string{dir, "-f", "xml", "-o", outPath, "--no-tokens"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+
+	out, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), "<brfit>") {
+		t.Error("expected --format flag to override project config")
+	}
+}
+
+func TestRootCommandInvalidProjectConfig(t *testing.T) {
+	dir := setupConfigProject(t, "formatt: md\n")
+
+	cmd := newRootCommandWithConfig(config.DefaultConfig())
+	cmd.SetArgs([]string{dir})
+	err := cmd.Execute()
+	if err == nil {
+		t.Fatal("expected error for unknown config key")
+	}
+	if !strings.Contains(err.Error(), `unknown key "formatt"`) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestConfigPrintCommand(t *testing.T) {
+	dir := setupConfigProject(t, "format: md\nexclude:\n  - vendor/**\n")
+	t.Setenv("BRFIT_MAX_DOC_LENGTH", "50")
+
+	var buf bytes.Buffer
+	cmd := newRootCommandWithConfig(config.DefaultConfig())
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"config", "print", dir, "--include-body"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+
+	lines := make(map[string]string)
+	for _, line := range strings.Split(buf.String(), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 {
+			lines[fields[0]] = line
+		}
+	}
+
+	checks := map[string][]string{
+		"format":         {"md", "project config", ".brfit.yaml"},
+		"exclude":        {"vendor/**", "project config"},
+		"max-doc-length": {"50", "env BRFIT_MAX_DOC_LENGTH"},
+		"include-body":   {"true", "flag --include-body"},
+		"mode":           {"sig", "default"},
+	}
+	for key, wants := range checks {
+		line, ok := lines[key]
+		if !ok {
+			t.Errorf("missing key %q in output:\n%s", key, buf.String())
+			continue
+		}
+		for _, want := range wants {
+			if !strings.Contains(line, want) {
+				t.Errorf("line for %q = %q, expected to contain %q", key, line, want)
+			}
+		}
+	}
+}
+
+func TestRootCommandProfileFlag(t *testing.T) {
+	dir := setupConfigProject(t, "format: md\nprofiles:\n  api-only:\n    format: json\n    include-private: false\n  review:\n    include-private: true\n")
+	outPath := filepath.Join(t.TempDir(), "out.json")
+
+	cmd := newRootCommandWithConfig(config.DefaultConfig())
+	cmd.SetArgs([]string{dir, "--profile", "api-only", "-o", outPath, "--no-tokens"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+
+	out, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(out), "{") {
+		t.Errorf("expected JSON output from profile, got: %.40s", out)
+	}
+	if strings.Contains(string(out), "hidden") {
+		t.Error("expected private symbols to be excluded by profile")
+	}
+}
@@ -0,0 +1,400 @@
+package main
+
+import (
+	"archive/tar"
+	gocontext "context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/indigo-net/Brf.it/internal/config"
+	"github.com/indigo-net/Brf.it/internal/context"
+	"github.com/indigo-net/Brf.it/pkg/extractor"
+	"github.com/indigo-net/Brf.it/pkg/parser"
+	"github.com/indigo-net/Brf.it/pkg/scanner"
+	"github.com/spf13/cobra"
+)
+
+// workingTreeRef is the label used when a diff side is the working tree.
+const workingTreeRef = "working tree"
+
+// newDiffCommand creates the "diff" command, which compares extracted
+// signatures between two git revisions.
+func newDiffCommand() *cobra.Command {
+	c := config.DefaultConfig()
+	cmd := &cobra.Command{
+		Use:   "diff <old>[..<new>] [path] [options]",
+		Short: "Show added, removed and changed signatures between two git revisions",
+		Long: `Run the extractor on two git revisions and report signature-level changes
+per file. Signatures are matched by name and kind.
+
+Revision ranges:
+  v1.2.0..HEAD    compare v1.2.0 with HEAD
+  main...feature  compare the merge base of main and feature with feature
+  v1.2.0          compare v1.2.0 with the working tree`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDiff(cmd, args, c)
+		},
+	}
+
+	addDiffFlags(cmd, c)
+	return cmd
+}
+
+// addDiffFlags adds the subset of root flags that affect a signature diff.
+func addDiffFlags(cmd *cobra.Command, c *config.Config) {
+	cmd.Flags().StringVarP(&c.Format, "format", "f", c.Format,
+		"output format: \"xml\" | \"md\" | \"json\"")
+	cmd.Flags().StringVarP(&c.Output, "output", "o", c.Output,
+		"output file path (default: stdout)")
+	cmd.Flags().StringArrayVarP(&c.IgnoreFiles, "ignore", "i", c.IgnoreFiles,
+		"custom ignore file(s), can be specified multiple times (default: .gitignore)")
+	cmd.Flags().StringArrayVar(&c.IncludePatterns, "include", c.IncludePatterns,
+		"glob pattern(s) to include, can be specified multiple times (e.g., \"pkg/**/*.go\")")
+	cmd.Flags().StringArrayVar(&c.ExcludePatterns, "exclude", c.ExcludePatterns,
+		"glob pattern(s) to exclude, can be specified multiple times (e.g., \"**/*_test.go\")")
+	cmd.Flags().BoolVar(&c.IncludeHidden, "include-hidden", c.IncludeHidden,
+		"include hidden files (dotfiles)")
+	cmd.Flags().BoolVar(&c.IncludePrivate, "include-private", c.IncludePrivate,
+		"include symbols of every visibility in the comparison")
+	_ = cmd.Flags().MarkDeprecated("include-private", "use --visibility all instead")
+	cmd.Flags().StringSliceVar(&c.Visibility, "visibility", c.Visibility,
+		"visibility levels to compare (default: public,protected, plus package in Java and internal in C# and Swift)")
+	cmd.Flags().Int64Var(&c.MaxFileSize, "max-size", c.MaxFileSize,
+		"maximum file size in bytes (default: 512000 = 500KB)")
+	cmd.Flags().DurationVar(&c.ParseTimeout, "parse-timeout", c.ParseTimeout,
+		"maximum time to parse a single file; slower files fail with a timeout error (0 = no limit)")
+	cmd.Flags().IntVar(&c.MaxDocLength, "max-doc-length", c.MaxDocLength,
+		"maximum documentation comment length in characters (0 = no limit)")
+	cmd.Flags().BoolVar(&c.SecurityCheck, "security-check", c.SecurityCheck,
+		"enable secret detection and redaction (use --security-check=false to disable)")
+	cmd.Flags().StringVar(&c.ConfigFile, "config", c.ConfigFile,
+		"config file path (default: discover .brfit.yaml/.brfit.toml from the target path)")
+	cmd.Flags().StringVar(&c.Profile, "profile", c.Profile,
+		"named profile from the config file(s) to apply (e.g., \"review\")")
+}
+
+// runDiff is the main execution function for the diff command.
+func runDiff(cmd *cobra.Command, args []string, c *config.Config) error {
+	c.Path = "."
+	if len(args) > 1 {
+		c.Path = args[1]
+	}
+	if _, err := os.Stat(c.Path); os.IsNotExist(err) {
+		return fmt.Errorf("path not found: %s", c.Path)
+	}
+	if absPath, err := filepath.Abs(c.Path); err == nil {
+		c.Path = absPath
+	}
+
+	if _, err := applyConfigFiles(cmd, c, false); err != nil {
+		return fmt.Errorf("configuration error: %w", err)
+	}
+	c.Version = Version
+	if err := c.Validate(); err != nil {
+		return fmt.Errorf("configuration error: %w", err)
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = gocontext.Background()
+	}
+
+	oldRef, newRef, err := resolveDiffRange(ctx, c.Path, args[0])
+	if err != nil {
+		return err
+	}
+
+	oldSnap, err := extractRevision(ctx, c, oldRef)
+	if err != nil {
+		return err
+	}
+	newSnap, err := extractRevision(ctx, c, newRef)
+	if err != nil {
+		return err
+	}
+
+	warnErrorFiles(os.Stderr, append(oldSnap.ErrorFiles, newSnap.ErrorFiles...))
+
+	if newRef == "" {
+		newRef = workingTreeRef
+	}
+	data := context.BuildDiffData(c.Path, c.Version, oldRef, newRef, context.DiffSnapshots(oldSnap, newSnap))
+	data.MaxDocLength = c.MaxDocLength
+
+	content, err := context.FormatDiff(c.Format, data)
+	if err != nil {
+		return fmt.Errorf("output failed: %w", err)
+	}
+	if err := writeOutput(&context.Result{Content: content}, c); err != nil {
+		return fmt.Errorf("output failed: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "Files: %d, Added: %d, Removed: %d, Changed: %d\n",
+		len(data.Files), data.Added, data.Removed, data.Changed)
+	return nil
+}
+
+// resolveDiffRange parses "<old>..<new>", "<old>...<new>" or "<old>" into
+// two revisions. An empty new revision means the working tree. As in git, an
+// omitted side of ".." defaults to HEAD, and "..." compares against the merge base.
+func resolveDiffRange(ctx gocontext.Context, path, spec string) (string, string, error) {
+	var oldRef, newRef string
+	switch {
+	case strings.Contains(spec, "..."):
+		parts := strings.SplitN(spec, "...", 2)
+		left, right := defaultHEAD(parts[0]), defaultHEAD(parts[1])
+		if err := validateRef(left); err != nil {
+			return "", "", err
+		}
+		if err := validateRef(right); err != nil {
+			return "", "", err
+		}
+		base, err := gitOutput(ctx, gitDir(path), "merge-base", left, right)
+		if err != nil {
+			return "", "", fmt.Errorf("cannot find merge base of %s and %s: %w", left, right, err)
+		}
+		oldRef, newRef = strings.TrimSpace(base), right
+	case strings.Contains(spec, ".."):
+		parts := strings.SplitN(spec, "..", 2)
+		oldRef, newRef = defaultHEAD(parts[0]), defaultHEAD(parts[1])
+	default:
+		oldRef = spec
+	}
+
+	for _, ref := range []string{oldRef, newRef} {
+		if ref == "" {
+			continue
+		}
+		if err := validateRef(ref); err != nil {
+			return "", "", err
+		}
+		if _, err := gitOutput(ctx, gitDir(path), "rev-parse", "--verify", "--quiet", ref+"^{commit}"); err != nil {
+			return "", "", fmt.Errorf("unknown revision %q", ref)
+		}
+	}
+	return oldRef, newRef, nil
+}
+
+// defaultHEAD returns "HEAD" for an omitted range endpoint.
+func defaultHEAD(ref string) string {
+	if ref == "" {
+		return "HEAD"
+	}
+	return ref
+}
+
+// validateRef rejects revisions that git would interpret as options.
+func validateRef(ref string) error {
+	if strings.HasPrefix(ref, "-") {
+		return fmt.Errorf("invalid revision %q", ref)
+	}
+	return nil
+}
+
+// extractRevision extracts signatures below c.Path at the given revision.
+// An empty ref extracts from the working tree.
+func extractRevision(ctx gocontext.Context, c *config.Config, ref string) (*context.Snapshot, error) {
+	root := c.Path
+	if ref != "" {
+		snapRoot, cleanup, err := materializeRevision(ctx, c.Path, ref)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check out %s: %w", ref, err)
+		}
+		defer cleanup()
+		root = snapRoot
+	}
+
+	// The target did not exist at this revision: everything is added or removed.
+	if _, err := os.Stat(root); errors.Is(err, os.ErrNotExist) {
+		return &context.Snapshot{Files: map[string]extractor.ExtractedFile{}}, nil
+	}
+
+	queries, err := parser.LoadQueries(c.QueryDirectory())
+	if err != nil {
+		return nil, err
+	}
+
+	snap, err := context.ExtractSnapshot(ctx, &context.SnapshotOptions{
+		Scan: &scanner.ScanOptions{
+			RootPath:            root,
+			SupportedExtensions: c.SupportedExtensions(),
+			IgnoreFiles:         c.IgnoreFiles,
+			IncludePatterns:     c.IncludePatterns,
+			ExcludePatterns:     c.ExcludePatterns,
+			IncludeHidden:       c.IncludeHidden,
+			MaxFileSize:         c.MaxFileSize,
+			PreloadContent:      true,
+		},
+		Extract: &extractor.ExtractOptions{
+			IncludePrivate: c.IncludePrivate,
+			Visibility:     c.VisibilityLevels(),
+			MaxFileSize:    c.MaxFileSize,
+			ParseTimeout:   c.ParseTimeout,
+			Queries:        queries,
+		},
+		SecurityCheck: c.SecurityCheck,
+	})
+	if err != nil {
+		label := ref
+		if label == "" {
+			label = workingTreeRef
+		}
+		return nil, fmt.Errorf("processing %s failed: %w", label, err)
+	}
+	return snap, nil
+}
+
+// warnErrorFiles reports the extraction errors of a snapshot to w. Files
+// with syntax errors are still compared; files that failed are skipped.
+func warnErrorFiles(w io.Writer, files []extractor.ErrorDetail) {
+	for _, ef := range files {
+		if ef.Syntax {
+			fmt.Fprintf(w, "[brfit] WARN: %s has syntax errors: %v\n", ef.Path, ef.Err)
+			continue
+		}
+		fmt.Fprintf(w, "[brfit] WARN: skipping %s: %v\n", ef.Path, ef.Err)
+	}
+}
+
+// materializeRevision writes the files below path at ref into a temporary
+// directory using git archive, without touching the working tree or index.
+// It returns the location corresponding to path inside the temporary
+// directory and a cleanup function that removes it.
+func materializeRevision(ctx gocontext.Context, path, ref string) (string, func(), error) {
+	dir := gitDir(path)
+	top, err := gitOutput(ctx, dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", nil, fmt.Errorf("git not found or not a git repository: %w", err)
+	}
+	repoRoot := strings.TrimSpace(top)
+	if resolved, err := filepath.EvalSymlinks(repoRoot); err == nil {
+		repoRoot = resolved
+	}
+	target := path
+	if resolved, err := filepath.EvalSymlinks(target); err == nil {
+		target = resolved
+	}
+	rel, err := filepath.Rel(repoRoot, target)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return "", nil, fmt.Errorf("%s is outside the repository %s", path, repoRoot)
+	}
+	rel = filepath.ToSlash(rel)
+
+	tmpDir, err := os.MkdirTemp("", "brfit-diff-*")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	cleanup := func() {
+		if err := os.RemoveAll(tmpDir); err != nil {
+			fmt.Fprintf(os.Stderr, "[brfit] WARN: failed to remove temp directory %s: %v\n", tmpDir, err)
+		}
+	}
+	snapRoot := filepath.Join(tmpDir, filepath.FromSlash(rel))
+
+	archiveArgs := []string{"archive", "--format=tar", ref}
+	if rel != "." {
+		// A path that did not exist at ref yields an empty snapshot.
+		if _, err := gitOutput(ctx, repoRoot, "cat-file", "-e", ref+":"+rel); err != nil {
+			return snapRoot, cleanup, nil
+		}
+		archiveArgs = append(archiveArgs, "--", rel)
+	}
+
+	archive := exec.CommandContext(ctx, "git", archiveArgs...)
+	archive.Dir = repoRoot
+	var stderr strings.Builder
+	archive.Stderr = &stderr
+	stdout, err := archive.StdoutPipe()
+	if err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	if err := archive.Start(); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("git archive failed: %w", err)
+	}
+	extractErr := extractTar(stdout, tmpDir)
+	// Drain remaining output so git does not block on a full pipe.
+	_, _ = io.Copy(io.Discard, stdout)
+	if err := archive.Wait(); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("git archive failed: %s", strings.TrimSpace(stderr.String()))
+	}
+	if extractErr != nil {
+		cleanup()
+		return "", nil, extractErr
+	}
+
+	return snapRoot, cleanup, nil
+}
+
+// extractTar unpacks regular files and directories from r into dest.
+// Entries that would escape dest and non-regular files (e.g., symlinks) are skipped.
+func extractTar(r io.Reader, dest string) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read archive: %w", err)
+		}
+
+		name := filepath.FromSlash(hdr.Name)
+		if !filepath.IsLocal(name) {
+			continue
+		}
+		target := filepath.Join(dest, name)
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(f, tr)
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// gitDir returns the directory to run git in for the given target path.
+func gitDir(path string) string {
+	if info, err := os.Stat(path); err == nil && !info.IsDir() {
+		return filepath.Dir(path)
+	}
+	return path
+}
+
+// gitOutput runs git with args in dir and returns its standard output.
+func gitOutput(ctx gocontext.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("%s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", err
+	}
+	return string(out), nil
+}
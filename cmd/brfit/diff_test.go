@@ -0,0 +1,242 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/indigo-net/Brf.it/internal/config"
+	"github.com/indigo-net/Brf.it/pkg/extractor"
+)
+
+// setupDiffRepo creates a git repository with two commits tagged v1 and v2.
+func setupDiffRepo(t *testing.T) string {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	tmpDir := t.TempDir()
+
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command(args[0], args[1:]...)
+		cmd.Dir = tmpDir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=test",
+			"GIT_AUTHOR_EMAIL=test@test.com",
+			"GIT_COMMITTER_NAME=test",
+			"GIT_COMMITTER_EMAIL=test@test.com",
+		)
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("command %v failed: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run("git", "init")
+	write("api/api.go", "package api\n\nfunc Keep() {}\n\nfunc Change(a int) {}\n\nfunc Drop() {}\n")
+	write("gone/gone.go", "package gone\n\nfunc Old() {}\n")
+	run("git", "add", "-A")
+	run("git", "commit", "-m", "v1")
+	run("git", "tag", "v1")
+
+	write("api/api.go", "package api\n\nfunc Keep() {}\n\nfunc Change(a int, b string) error { return nil }\n\nfunc Add() {}\n")
+	run("git", "rm", "-q", "gone/gone.go")
+	write("fresh/fresh.go", "package fresh\n\ntype New struct{}\n")
+	run("git", "add", "-A")
+	run("git", "commit", "-m", "v2")
+	run("git", "tag", "v2")
+
+	return tmpDir
+}
+
+func TestDiffCommandJSON(t *testing.T) {
+	dir := setupDiffRepo(t)
+	outPath := filepath.Join(t.TempDir(), "diff.json")
+
+	cmd := newRootCommandWithConfig(config.DefaultConfig())
+	cmd.SetArgs([]string{"diff", "v1..v2", dir, "-f", "json", "-o", outPath})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+
+	raw, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out struct {
+		Old     string `json:"old"`
+		New     string `json:"new"`
+		Summary struct {
+			Added, Removed, Changed int
+		} `json:"summary"`
+		Files []struct {
+			Path    string `json:"path"`
+			Changes []struct {
+				Change string `json:"change"`
+				Name   string `json:"name"`
+			} `json:"changes"`
+		} `json:"files"`
+	}
+	if err := json.Unmarshal(raw, &out); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, raw)
+	}
+
+	if out.Old != "v1" || out.New != "v2" {
+		t.Errorf("unexpected refs: old=%q new=%q", out.Old, out.New)
+	}
+	if out.Summary.Added != 2 || out.Summary.Removed != 2 || out.Summary.Changed != 1 {
+		t.Errorf("unexpected summary: %+v", out.Summary)
+	}
+
+	got := make(map[string]string)
+	for _, f := range out.Files {
+		for _, ch := range f.Changes {
+			got[f.Path+":"+ch.Name] = ch.Change
+		}
+	}
+	want := map[string]string{
+		"api/api.go:Change":  "changed",
+		"api/api.go:Add":     "added",
+		"api/api.go:Drop":    "removed",
+		"gone/gone.go:Old":   "removed",
+		"fresh/fresh.go:New": "added",
+	}
+	for key, change := range want {
+		if got[key] != change {
+			t.Errorf("%s: expected %q, got %q", key, change, got[key])
+		}
+	}
+	if _, ok := got["api/api.go:Keep"]; ok {
+		t.Error("unchanged signature should not be reported")
+	}
+}
+
+func TestDiffCommandWorkingTreeSubdir(t *testing.T) {
+	dir := setupDiffRepo(t)
+	if err := os.WriteFile(filepath.Join(dir, "api", "api.go"), []byte("package api\n\nfunc Keep(ctx string) {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	outPath := filepath.Join(t.TempDir(), "diff.md")
+
+	cmd := newRootCommandWithConfig(config.DefaultConfig())
+	cmd.SetArgs([]string{"diff", "v2", filepath.Join(dir, "api"), "-f", "md", "-o", outPath})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+
+	out, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(out)
+	for _, want := range []string{"v2 → working tree", "### api.go", "- func Keep()", "+ func Keep(ctx string)", "- func Add()"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("expected output to contain %q:\n%s", want, content)
+		}
+	}
+	if strings.Contains(content, "fresh.go") {
+		t.Error("files outside the target path should not be compared")
+	}
+}
+
+func TestDiffCommandErrors(t *testing.T) {
+	dir := setupDiffRepo(t)
+
+	tests := []struct {
+		name    string
+		spec    string
+		errPart string
+	}{
+		{"unknown revision", "v1..nope", `unknown revision "nope"`},
+		{"option injection", "--output=x..v2", "invalid revision"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newRootCommandWithConfig(config.DefaultConfig())
+			cmd.SetArgs([]string{"diff", "--", tt.spec, dir})
+			err := cmd.Execute()
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.errPart) {
+				t.Errorf("expected error containing %q, got: %v", tt.errPart, err)
+			}
+		})
+	}
+}
+
+func TestExtractTarSkipsUnsafeEntries(t *testing.T) {
+	dest := t.TempDir()
+	archive := buildTar(t, map[string]string{
+		"ok/file.go":     "package ok\n",
+		"../escape.go":   "package bad\n",
+		"/abs/escape.go": "package bad\n",
+	})
+
+	if err := extractTar(archive, dest); err != nil {
+		t.Fatalf("extractTar failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "ok", "file.go")); err != nil {
+		t.Errorf("expected regular file to be extracted: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(dest), "escape.go")); err == nil {
+		t.Error("entry escaping the destination was extracted")
+	}
+}
+
+func TestWarnErrorFiles(t *testing.T) {
+	var buf bytes.Buffer
+	warnErrorFiles(&buf, []extractor.ErrorDetail{
+		{Path: "broken.go", Err: errors.New("syntax error at 3:1-3:4"), Syntax: true},
+		{Path: "slow.go", Err: errors.New("timed out")},
+	})
+
+	out := buf.String()
+	if !strings.Contains(out, "WARN: broken.go has syntax errors: syntax error at 3:1-3:4") {
+		t.Errorf("expected syntax error warning, got:\n%s", out)
+	}
+	if strings.Contains(out, "skipping broken.go") {
+		t.Errorf("file with syntax errors reported as skipped:\n%s", out)
+	}
+	if !strings.Contains(out, "WARN: skipping slow.go: timed out") {
+		t.Errorf("expected skip warning, got:\n%s", out)
+	}
+}
+
+// buildTar returns a tar stream containing the given files.
+func buildTar(t *testing.T, files map[string]string) io.Reader {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for name, content := range files {
+		hdr := &tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return &buf
+}
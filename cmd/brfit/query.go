@@ -0,0 +1,226 @@
+package main
+
+import (
+	gocontext "context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/indigo-net/Brf.it/internal/config"
+	"github.com/indigo-net/Brf.it/internal/context"
+	"github.com/indigo-net/Brf.it/pkg/extractor"
+	"github.com/indigo-net/Brf.it/pkg/formatter"
+	"github.com/indigo-net/Brf.it/pkg/parser"
+	"github.com/indigo-net/Brf.it/pkg/scanner"
+	"github.com/spf13/cobra"
+)
+
+// queryFlags holds the symbol filters of the query command.
+type queryFlags struct {
+	format     string
+	regex      bool
+	ignoreCase bool
+	kinds      []string
+	languages  []string
+	paths      []string
+	exported   bool
+	private    bool
+}
+
+// newQueryCommand creates the "query" command, which searches extracted
+// symbols across the project.
+func newQueryCommand() *cobra.Command {
+	c := config.DefaultConfig()
+	qf := &queryFlags{}
+	cmd := &cobra.Command{
+		Use:   "query <pattern> [path] [options]",
+		Short: "Find symbols by name, kind, language, visibility and path",
+		Long: `Search extracted signatures and print each match with file:line and its doc.
+
+<pattern> is a glob matched against the whole symbol name ("Parse*", "*Handler"),
+or a regular expression with --regex. Use "*" to match every name.
+
+Examples:
+  brfit query 'Parse*' --kind function --lang go
+  brfit query '^New' --regex --exported
+  brfit query '*' --kind type --path "pkg/**"`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runQuery(cmd, args, c, qf)
+		},
+	}
+
+	cmd.Flags().StringVarP(&qf.format, "format", "f", "text",
+		"output format: \"text\" | \"json\"")
+	cmd.Flags().BoolVarP(&qf.regex, "regex", "E", false,
+		"treat <pattern> as a regular expression (unanchored)")
+	cmd.Flags().BoolVar(&qf.ignoreCase, "ignore-case", false,
+		"match <pattern> case-insensitively")
+	cmd.Flags().StringSliceVarP(&qf.kinds, "kind", "k", nil,
+		"symbol kind(s), raw (\"method\") or normalized (\"function\", \"type\", \"variable\")")
+	cmd.Flags().StringSliceVarP(&qf.languages, "lang", "l", nil,
+		"language(s) (e.g., \"go,typescript\")")
+	cmd.Flags().StringArrayVar(&qf.paths, "path", nil,
+		"glob pattern(s) matched against the relative file path (e.g., \"pkg/**\")")
+	cmd.Flags().BoolVar(&qf.exported, "exported", false,
+		"only exported symbols")
+	cmd.Flags().BoolVar(&qf.private, "private", false,
+		"only non-exported/private symbols")
+	cmd.Flags().StringArrayVarP(&c.IgnoreFiles, "ignore", "i", c.IgnoreFiles,
+		"custom ignore file(s), can be specified multiple times (default: .gitignore)")
+	cmd.Flags().StringArrayVar(&c.IncludePatterns, "include", c.IncludePatterns,
+		"glob pattern(s) to include, can be specified multiple times (e.g., \"pkg/**/*.go\")")
+	cmd.Flags().StringArrayVar(&c.ExcludePatterns, "exclude", c.ExcludePatterns,
+		"glob pattern(s) to exclude, can be specified multiple times (e.g., \"**/*_test.go\")")
+	cmd.Flags().BoolVar(&c.IncludeHidden, "include-hidden", c.IncludeHidden,
+		"include hidden files (dotfiles)")
+	cmd.Flags().Int64Var(&c.MaxFileSize, "max-size", c.MaxFileSize,
+		"maximum file size in bytes (default: 512000 = 500KB)")
+	cmd.Flags().DurationVar(&c.ParseTimeout, "parse-timeout", c.ParseTimeout,
+		"maximum time to parse a single file; slower files fail with a timeout error (0 = no limit)")
+	cmd.Flags().IntVar(&c.MaxDocLength, "max-doc-length", c.MaxDocLength,
+		"maximum documentation comment length in characters (0 = no limit)")
+	cmd.Flags().BoolVar(&c.SecurityCheck, "security-check", c.SecurityCheck,
+		"enable secret detection and redaction (use --security-check=false to disable)")
+	cmd.Flags().StringVar(&c.ConfigFile, "config", c.ConfigFile,
+		"config file path (default: discover .brfit.yaml/.brfit.toml from the target path)")
+	cmd.Flags().StringVar(&c.Profile, "profile", c.Profile,
+		"named profile from the config file(s) to apply (e.g., \"review\")")
+
+	return cmd
+}
+
+// buildSymbolQuery converts the command's pattern and flags into a query.
+func buildSymbolQuery(pattern string, qf *queryFlags) (*context.SymbolQuery, error) {
+	if qf.exported && qf.private {
+		return nil, fmt.Errorf("--exported and --private are mutually exclusive")
+	}
+	q := &context.SymbolQuery{
+		IgnoreCase: qf.ignoreCase,
+		Kinds:      qf.kinds,
+		Languages:  qf.languages,
+		Paths:      qf.paths,
+	}
+	if qf.regex {
+		if qf.ignoreCase {
+			pattern = "(?i)" + pattern
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression: %w", err)
+		}
+		q.Regex = re
+	} else {
+		q.Name = pattern
+	}
+	if qf.exported || qf.private {
+		exported := qf.exported
+		q.Exported = &exported
+	}
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// runQuery is the main execution function for the query command.
+func runQuery(cmd *cobra.Command, args []string, c *config.Config, qf *queryFlags) error {
+	if qf.format != "text" && qf.format != "json" {
+		return fmt.Errorf("invalid format '%s': must be 'text' or 'json'", qf.format)
+	}
+	q, err := buildSymbolQuery(args[0], qf)
+	if err != nil {
+		return err
+	}
+
+	c.Path = "."
+	if len(args) > 1 {
+		c.Path = args[1]
+	}
+	if _, err := os.Stat(c.Path); os.IsNotExist(err) {
+		return fmt.Errorf("path not found: %s", c.Path)
+	}
+	if absPath, err := filepath.Abs(c.Path); err == nil {
+		c.Path = absPath
+	}
+
+	if _, err := applyConfigFiles(cmd, c, false); err != nil {
+		return fmt.Errorf("configuration error: %w", err)
+	}
+	if err := c.Validate(); err != nil {
+		return fmt.Errorf("configuration error: %w", err)
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = gocontext.Background()
+	}
+
+	queries, err := parser.LoadQueries(c.QueryDirectory())
+	if err != nil {
+		return fmt.Errorf("configuration error: %w", err)
+	}
+
+	// Private symbols are always extracted; --exported/--private filter them.
+	snap, err := context.ExtractSnapshot(ctx, &context.SnapshotOptions{
+		Scan: &scanner.ScanOptions{
+			RootPath:            c.Path,
+			SupportedExtensions: c.SupportedExtensions(),
+			IgnoreFiles:         c.IgnoreFiles,
+			IncludePatterns:     c.IncludePatterns,
+			ExcludePatterns:     c.ExcludePatterns,
+			IncludeHidden:       c.IncludeHidden,
+			MaxFileSize:         c.MaxFileSize,
+			PreloadContent:      true,
+		},
+		Extract: &extractor.ExtractOptions{
+			IncludePrivate: true,
+			MaxFileSize:    c.MaxFileSize,
+			ParseTimeout:   c.ParseTimeout,
+			Queries:        queries,
+		},
+		SecurityCheck: c.SecurityCheck,
+	})
+	if err != nil {
+		return fmt.Errorf("processing failed: %w", err)
+	}
+	warnErrorFiles(os.Stderr, snap.ErrorFiles)
+
+	matches := context.FindSymbols(snap, q)
+	for i := range matches {
+		matches[i].Doc = formatter.TruncateDoc(matches[i].Doc, c.MaxDocLength)
+	}
+
+	out := cmd.OutOrStdout()
+	if qf.format == "json" {
+		if matches == nil {
+			matches = []context.SymbolMatch{}
+		}
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(matches); err != nil {
+			return fmt.Errorf("output failed: %w", err)
+		}
+	} else {
+		writeQueryMatches(out, matches)
+	}
+
+	fmt.Fprintf(os.Stderr, "Matches: %d\n", len(matches))
+	return nil
+}
+
+// writeQueryMatches writes one "path:line  kind  signature" entry per match,
+// followed by its doc comment indented.
+func writeQueryMatches(w io.Writer, matches []context.SymbolMatch) {
+	for _, m := range matches {
+		fmt.Fprintf(w, "%s:%d  %s  %s\n", m.Path, m.Line, m.Kind,
+			strings.ReplaceAll(m.Text, "\n", "\n    "))
+		if doc := strings.TrimSpace(m.Doc); doc != "" {
+			fmt.Fprintf(w, "    %s\n", strings.ReplaceAll(doc, "\n", "\n    "))
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/indigo-net/Brf.it/internal/config"
+	"github.com/indigo-net/Brf.it/internal/context"
+)
+
+func TestQueryCommand(t *testing.T) {
+	dir := setupStatsDir(t)
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "glob with kind",
+			args:    []string{"*", "--kind", "function"},
+			want:    []string{"api/client.go:7  function  func helper()", "main.go:3  function  func main()"},
+			notWant: []string{"Client struct"},
+		},
+		{
+			name:    "exported only",
+			args:    []string{"*", "--exported"},
+			want:    []string{"api/client.go:3  type  type Client struct{}"},
+			notWant: []string{"helper", "func main"},
+		},
+		{
+			name:    "regex with path",
+			args:    []string{"^(Do|main)$", "-E", "--path", "api/**"},
+			want:    []string{"api/client.go:5  method  func (c *Client) Do() error"},
+			notWant: []string{"func main"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			cmd := newRootCommandWithConfig(config.DefaultConfig())
+			cmd.SetOut(&buf)
+			cmd.SetArgs(append([]string{"query", tt.args[0], dir}, tt.args[1:]...))
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("query failed: %v", err)
+			}
+			out := buf.String()
+			for _, w := range tt.want {
+				if !strings.Contains(out, w) {
+					t.Errorf("expected %q in output:\n%s", w, out)
+				}
+			}
+			for _, w := range tt.notWant {
+				if strings.Contains(out, w) {
+					t.Errorf("unexpected %q in output:\n%s", w, out)
+				}
+			}
+		})
+	}
+}
+
+func TestQueryCommandJSON(t *testing.T) {
+	var buf bytes.Buffer
+	cmd := newRootCommandWithConfig(config.DefaultConfig())
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"query", "Nothing*", setupStatsDir(t), "-f", "json"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	var matches []context.SymbolMatch
+	if err := json.Unmarshal(buf.Bytes(), &matches); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if matches == nil || len(matches) != 0 {
+		t.Errorf("expected an empty JSON array, got %s", buf.String())
+	}
+}
+
+func TestBuildSymbolQueryErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		flags   queryFlags
+		wantErr string
+	}{
+		{"exported and private", "*", queryFlags{exported: true, private: true}, "mutually exclusive"},
+		{"bad regex", "(", queryFlags{regex: true}, "invalid regular expression"},
+		{"bad glob", "[a-", queryFlags{}, "invalid name pattern"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := buildSymbolQuery(tt.pattern, &tt.flags)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestWriteQueryMatches(t *testing.T) {
+	var buf bytes.Buffer
+	writeQueryMatches(&buf, []context.SymbolMatch{
+		{Path: "a.go", Line: 3, Kind: "type", Text: "type A struct {\n\tX int\n}", Doc: "A is a thing.\nIt has X."},
+	})
+	want := "a.go:3  type  type A struct {\n    \tX int\n    }\n    A is a thing.\n    It has X.\n"
+	if buf.String() != want {
+		t.Errorf("got:\n%q\nwant:\n%q", buf.String(), want)
+	}
+}
@@ -2,10 +2,12 @@ package main
 
 import (
 	gocontext "context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/indigo-net/Brf.it/internal/config"
@@ -77,6 +79,16 @@ preserving the essential information AI needs to understand your project.`,
 	// Add flags bound to the provided config
 	addFlags(cmd, c)
 
+	// Version flag
+	cmd.Flags().BoolP("version", "v", false, "print version information")
+
+	// Subcommands
+	cmd.AddCommand(newConfigCommand())
+	cmd.AddCommand(newDiffCommand())
+	cmd.AddCommand(newAPICheckCommand())
+	cmd.AddCommand(newStatsCommand())
+	cmd.AddCommand(newQueryCommand())
+
 	return cmd
 }
 
@@ -84,7 +96,7 @@ preserving the essential information AI needs to understand your project.`,
 func addFlags(cmd *cobra.Command, c *config.Config) {
 	// Mode flag
 	cmd.Flags().StringVarP(&c.Mode, "mode", "m", c.Mode,
-		"output mode: \"sig\" (signature only)")
+		"output mode: \"sig\" (signatures), \"outline\" (symbol names only), \"docs\" (doc comments), \"full\" (raw source)")
 
 	// Format flag
 	cmd.Flags().StringVarP(&c.Format, "format", "f", c.Format,
@@ -116,7 +128,15 @@ func addFlags(cmd *cobra.Command, c *config.Config) {
 		"include import/export statements in output")
 
 	cmd.Flags().BoolVar(&c.IncludePrivate, "include-private", c.IncludePrivate,
-		"include non-exported/private symbols in output")
+		"include symbols of every visibility in output")
+	_ = cmd.Flags().MarkDeprecated("include-private", "use --visibility all instead")
+
+	cmd.Flags().StringSliceVar(&c.Visibility, "visibility", c.Visibility,
+		"visibility levels to include: public, protected, internal, package, file, private or all (default: public,protected, plus package in Java and internal in C# and Swift)")
+
+	// Deprecated symbols
+	cmd.Flags().BoolVar(&c.ExcludeDeprecated, "exclude-deprecated", c.ExcludeDeprecated,
+		"exclude symbols marked deprecated (@deprecated, @Deprecated, #[deprecated], [Obsolete], Deprecated: docs)")
 
 	cmd.Flags().BoolVar(&c.DedupeImports, "dedupe-imports", c.DedupeImports,
 		"deduplicate imports across files (requires --include-imports)")
@@ -131,10 +151,32 @@ func addFlags(cmd *cobra.Command, c *config.Config) {
 	cmd.Flags().Int64Var(&c.MaxFileSize, "max-size", c.MaxFileSize,
 		"maximum file size in bytes (default: 512000 = 500KB)")
 
+	// Per-file parse timeout
+	cmd.Flags().DurationVar(&c.ParseTimeout, "parse-timeout", c.ParseTimeout,
+		"maximum time to parse a single file; slower files fail with a timeout error (0 = no limit)")
+
+	// Query overrides and extension mappings
+	cmd.Flags().StringVar(&c.QueryDir, "query-dir", c.QueryDir,
+		"directory of Tree-sitter query overrides (<lang>.scm, <lang>.imports.scm, <lang>.calls.scm; default: .brfit/queries)")
+	cmd.Flags().StringToStringVar(&c.Extensions, "extensions", c.Extensions,
+		"map extra file extensions to languages (e.g., .mjs=javascript,.pyi=python)")
+
 	// Max doc length
 	cmd.Flags().IntVar(&c.MaxDocLength, "max-doc-length", c.MaxDocLength,
 		"maximum documentation comment length in characters (0 = no limit)")
 
+	// Doc style
+	cmd.Flags().StringVar(&c.DocStyle, "doc-style", c.DocStyle,
+		"documentation comment style: full, summary-line, or params-only")
+
+	// Token budget
+	cmd.Flags().IntVar(&c.MaxTokens, "max-tokens", c.MaxTokens,
+		"token budget; drops long docs, private symbols, then low-priority files to fit (0 = no limit)")
+
+	// Split output
+	cmd.Flags().IntVar(&c.SplitTokens, "split-tokens", c.SplitTokens,
+		"split output into numbered files of at most N tokens plus a manifest (requires --output)")
+
 	// Token tree flag
 	cmd.Flags().BoolVar(&c.TokenTree, "token-tree", c.TokenTree,
 		"output directory tree with per-file token counts")
@@ -168,6 +210,14 @@ func addFlags(cmd *cobra.Command, c *config.Config) {
 	cmd.Flags().BoolVar(&c.CallGraph, "call-graph", c.CallGraph,
 		"include function call graph in output")
 
+	// Locations flag
+	cmd.Flags().BoolVar(&c.Locations, "locations", c.Locations,
+		"include symbol IDs and source ranges in XML/Markdown output (always in JSON)")
+
+	// Structured signature flag
+	cmd.Flags().BoolVar(&c.Structured, "structured", c.Structured,
+		"include parameters, return types, type parameters and modifiers as XML attributes (always in JSON)")
+
 	// Security check flag (enabled by default; --no-security-check disables)
 	cmd.Flags().BoolVar(&c.SecurityCheck, "security-check", c.SecurityCheck,
 		"enable secret detection and redaction (use --no-security-check to disable)")
@@ -183,12 +233,18 @@ func addFlags(cmd *cobra.Command, c *config.Config) {
 	cmd.Flags().StringVar(&c.Since, "since", c.Since,
 		"only scan files changed since the specified commit/tag (e.g., \"v1.0.0\", \"HEAD~5\")")
 
-	// Strict mode flag
-	cmd.Flags().BoolVar(&c.Strict, "strict", c.Strict,
-		"exit with error code 1 if any file has parsing errors")
+	// Strict mode flag ("--strict" alone means "--strict=error")
+	cmd.Flags().StringVar(&c.Strict, "strict", c.Strict,
+		"exit with error code 1 on parsing problems: \"error\" (files that failed to parse, the default when given without a value), \"syntax\" (also files with syntax errors) or \"off\"")
+	cmd.Flags().Lookup("strict").NoOptDefVal = config.StrictError
 
-	// Version flag
-	cmd.Flags().BoolP("version", "v", false, "print version information")
+	// Config file flag
+	cmd.Flags().StringVar(&c.ConfigFile, "config", c.ConfigFile,
+		"config file path (default: discover .brfit.yaml/.brfit.toml from the target path)")
+
+	// Config profile flag
+	cmd.Flags().StringVar(&c.Profile, "profile", c.Profile,
+		"named profile from the config file(s) to apply (e.g., \"review\")")
 }
 
 // runRoot is the main execution function for the root command.
@@ -236,6 +292,12 @@ func runRoot(cmd *cobra.Command, args []string, c *config.Config) error {
 		c.Path = absPath
 	}
 
+	// Layer config files and BRFIT_* env vars under explicit flags.
+	// A remote repository's own config file is never trusted.
+	if _, err := applyConfigFiles(cmd, c, c.Remote != ""); err != nil {
+		return fmt.Errorf("configuration error: %w", err)
+	}
+
 	// Set version
 	c.Version = Version
 
@@ -297,9 +359,17 @@ func runRoot(cmd *cobra.Command, args []string, c *config.Config) error {
 		return fmt.Errorf("output failed: %w", err)
 	}
 
+	// Report what was dropped to fit the token budget
+	if result.Trim != nil {
+		reportTrim(result.Trim, result.TokenCount)
+	}
+
 	// Print summary to stderr (doesn't pollute stdout)
 	fmt.Fprintf(os.Stderr, "Files: %d, Signatures: %d",
 		result.TotalFiles, result.TotalSignatures)
+	if len(result.Chunks) > 0 {
+		fmt.Fprintf(os.Stderr, ", Chunks: %d", len(result.Chunks))
+	}
 	if result.TokenCount > 0 {
 		fmt.Fprintf(os.Stderr, ", Tokens: %d", result.TokenCount)
 	}
@@ -310,20 +380,53 @@ func runRoot(cmd *cobra.Command, args []string, c *config.Config) error {
 
 	// Report parsing errors and optionally fail in strict mode
 	if result.ErrorCount > 0 {
-		fmt.Fprintf(os.Stderr, "[brfit] WARN: %d file(s) had parsing errors\n", result.ErrorCount)
+		fmt.Fprintf(os.Stderr, "[brfit] WARN: %d file(s) had parsing errors", result.ErrorCount)
+		if result.SyntaxErrorCount > 0 {
+			fmt.Fprintf(os.Stderr, " (%d with syntax errors)", result.SyntaxErrorCount)
+		}
+		fmt.Fprintln(os.Stderr)
 		for _, ef := range result.ErrorFiles {
 			fmt.Fprintf(os.Stderr, "[brfit]   %s: %v\n", ef.Path, ef.Err)
 		}
-		if c.Strict {
-			return fmt.Errorf("%d file(s) had parsing errors (--strict mode)", result.ErrorCount)
+		switch c.StrictLevel() {
+		case config.StrictSyntax:
+			return fmt.Errorf("%d file(s) had parsing errors (--strict=syntax mode)", result.ErrorCount)
+		case config.StrictError:
+			if failed := result.ErrorCount - result.SyntaxErrorCount; failed > 0 {
+				return fmt.Errorf("%d file(s) had parsing errors (--strict mode)", failed)
+			}
 		}
 	}
 
 	return nil
 }
 
+// reportTrim prints what was dropped to fit the token budget to stderr.
+func reportTrim(trim *context.TrimReport, tokens int) {
+	fmt.Fprintf(os.Stderr, "[brfit] Token budget %d: trimmed output from %d to %d tokens\n",
+		trim.Budget, trim.OriginalTokens, tokens)
+	if trim.DocsDropped > 0 {
+		fmt.Fprintf(os.Stderr, "[brfit]   dropped %d doc comment(s) longer than %d characters\n",
+			trim.DocsDropped, context.BudgetDocLength)
+	}
+	if trim.PrivateDropped > 0 {
+		fmt.Fprintf(os.Stderr, "[brfit]   dropped %d private symbol(s)\n", trim.PrivateDropped)
+	}
+	if len(trim.FilesDropped) > 0 {
+		fmt.Fprintf(os.Stderr, "[brfit]   dropped %d file(s): %s\n",
+			len(trim.FilesDropped), strings.Join(trim.FilesDropped, ", "))
+	}
+	if !trim.Fits {
+		fmt.Fprintf(os.Stderr, "[brfit] WARN: output still exceeds the token budget of %d\n", trim.Budget)
+	}
+}
+
 // writeOutput writes the result to stdout or a file.
 func writeOutput(result *context.Result, c *config.Config) error {
+	if len(result.Chunks) > 0 {
+		return writeChunks(result, c)
+	}
+
 	if c.Output == "" {
 		// Write to stdout (direct []byte output for efficiency)
 		_, err := os.Stdout.Write(result.Content)
@@ -334,6 +437,53 @@ func writeOutput(result *context.Result, c *config.Config) error {
 	return writeToFile(c.Output, result.Content)
 }
 
+// writeChunks writes each chunk to a numbered file next to c.Output
+// (briefing.xml → briefing.001.xml, briefing.002.xml, ...) and a JSON
+// manifest (briefing.manifest.json) listing the files in each chunk.
+func writeChunks(result *context.Result, c *config.Config) error {
+	paths, manifestPath := chunkPaths(c.Output, c.Format, len(result.Chunks))
+
+	names := make([]string, len(paths))
+	for i, chunk := range result.Chunks {
+		if err := writeToFile(paths[i], chunk.Content); err != nil {
+			return err
+		}
+		names[i] = filepath.Base(paths[i])
+		if chunk.Oversized {
+			fmt.Fprintf(os.Stderr, "[brfit] WARN: %s has %d tokens, over the --split-tokens limit (%s cannot be split)\n",
+				names[i], chunk.TokenCount, strings.Join(chunk.Files, ", "))
+		}
+	}
+
+	manifest, err := json.MarshalIndent(context.NewManifest(c.ToOptions(), result.Chunks, names), "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeToFile(manifestPath, append(manifest, '\n'))
+}
+
+// chunkPaths returns the numbered chunk file paths and the manifest path for
+// output. Numbers are zero-padded to at least three digits.
+func chunkPaths(output, format string, n int) ([]string, string) {
+	ext := filepath.Ext(output)
+	base := strings.TrimSuffix(output, ext)
+	if ext == "" {
+		switch format {
+		case "md", "markdown":
+			ext = ".md"
+		default:
+			ext = "." + format
+		}
+	}
+
+	width := max(3, len(strconv.Itoa(n)))
+	paths := make([]string, n)
+	for i := range paths {
+		paths[i] = fmt.Sprintf("%s.%0*d%s", base, width, i+1, ext)
+	}
+	return paths, base + ".manifest.json"
+}
+
 // runTokenTree scans files, counts tokens per file, and outputs a directory tree
 // with per-file token counts. This is a standalone mode that exits after output.
 func runTokenTree(ctx gocontext.Context, scanOpts *scanner.ScanOptions, rootPath string) error {
@@ -3,6 +3,7 @@ package main
 import (
 	"bytes"
 	gocontext "context"
+	"encoding/json"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -11,6 +12,7 @@ import (
 	"time"
 
 	"github.com/indigo-net/Brf.it/internal/config"
+	"github.com/indigo-net/Brf.it/internal/context"
 
 	// Import treesitter parser to register it
 	_ "github.com/indigo-net/Brf.it/pkg/parser/treesitter"
@@ -92,7 +94,7 @@ func TestNewRootCommand(t *testing.T) {
 	}
 
 	// Check flags exist
-	flags := []string{"mode", "format", "output", "ignore", "include", "exclude", "include-hidden", "include-private", "no-tree", "no-tokens", "max-size", "changed", "since", "token-tree", "security-check", "call-graph", "remote", "skip-empty"}
+	flags := []string{"mode", "format", "output", "ignore", "include", "exclude", "include-hidden", "include-private", "visibility", "exclude-deprecated", "doc-style", "strict", "parse-timeout", "query-dir", "extensions", "no-tree", "no-tokens", "max-size", "changed", "since", "token-tree", "security-check", "call-graph", "locations", "structured", "remote", "skip-empty"}
 	for _, flag := range flags {
 		f := cmd.Flags().Lookup(flag)
 		if f == nil {
@@ -688,3 +690,157 @@ func TestWriteToFile(t *testing.T) {
 		t.Errorf("file content mismatch: got %q, want %q", string(readContent), string(content))
 	}
 }
+
+func TestChunkPaths(t *testing.T) {
+	tests := []struct {
+		output, format string
+		n              int
+		wantFirst      string
+		wantLast       string
+		wantManifest   string
+	}{
+		{"briefing.xml", "xml", 2, "briefing.001.xml", "briefing.002.xml", "briefing.manifest.json"},
+		{filepath.Join("out", "ctx"), "md", 3, filepath.Join("out", "ctx.001.md"), filepath.Join("out", "ctx.003.md"), filepath.Join("out", "ctx.manifest.json")},
+		{"api.json", "json", 1200, "api.0001.json", "api.1200.json", "api.manifest.json"},
+	}
+
+	for _, tt := range tests {
+		paths, manifest := chunkPaths(tt.output, tt.format, tt.n)
+		if len(paths) != tt.n || paths[0] != tt.wantFirst || paths[len(paths)-1] != tt.wantLast {
+			t.Errorf("chunkPaths(%q) = [%s ... %s], want [%s ... %s]", tt.output, paths[0], paths[len(paths)-1], tt.wantFirst, tt.wantLast)
+		}
+		if manifest != tt.wantManifest {
+			t.Errorf("chunkPaths(%q) manifest = %q, want %q", tt.output, manifest, tt.wantManifest)
+		}
+	}
+}
+
+func TestWriteChunks(t *testing.T) {
+	tmpDir := t.TempDir()
+	c := config.DefaultConfig()
+	c.Output = filepath.Join(tmpDir, "briefing.xml")
+	c.SplitTokens = 100
+	result := &context.Result{Chunks: []context.Chunk{
+		{Content: []byte("<brfit>1</brfit>\n"), Files: []string{"a.go"}, TokenCount: 80},
+		{Content: []byte("<brfit>2</brfit>\n"), Files: []string{"b.go", "c.go"}, TokenCount: 95},
+	}}
+
+	if err := writeOutput(result, c); err != nil {
+		t.Fatalf("writeOutput failed: %v", err)
+	}
+
+	second, err := os.ReadFile(filepath.Join(tmpDir, "briefing.002.xml"))
+	if err != nil || string(second) != "<brfit>2</brfit>\n" {
+		t.Errorf("unexpected second chunk %q: %v", second, err)
+	}
+	if _, err := os.Stat(c.Output); err == nil {
+		t.Error("unsplit output file should not be written")
+	}
+
+	raw, err := os.ReadFile(filepath.Join(tmpDir, "briefing.manifest.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var manifest context.Manifest
+	if err := json.Unmarshal(raw, &manifest); err != nil {
+		t.Fatalf("invalid manifest: %v", err)
+	}
+	if manifest.SplitTokens != 100 || len(manifest.Chunks) != 2 {
+		t.Fatalf("unexpected manifest: %+v", manifest)
+	}
+	if manifest.Chunks[1].File != "briefing.002.xml" || strings.Join(manifest.Chunks[1].Files, ",") != "b.go,c.go" {
+		t.Errorf("unexpected second manifest entry: %+v", manifest.Chunks[1])
+	}
+}
+
+func TestSplitTokensRequiresOutput(t *testing.T) {
+	dir := t.TempDir()
+	cmd := newRootCommandWithConfig(config.DefaultConfig())
+	cmd.SetArgs([]string{dir, "--split-tokens", "1000"})
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "requires --output") {
+		t.Errorf("expected --output error, got: %v", err)
+	}
+}
+
+func TestVisibilityFlag(t *testing.T) {
+	tests := []struct {
+		name        string
+		visibility  string
+		wantPublic  bool
+		wantPackage bool
+	}{
+		{name: "default", visibility: "", wantPublic: true, wantPackage: false},
+		{name: "package only", visibility: "package", wantPublic: false, wantPackage: true},
+		{name: "all", visibility: "all", wantPublic: true, wantPackage: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := setupConfigProject(t, "format: md\n")
+			outPath := filepath.Join(t.TempDir(), "out.md")
+
+			args := []string{dir, "-o", outPath, "--no-tokens"}
+			if tt.visibility != "" {
+				args = append(args, "--visibility", tt.visibility)
+			}
+			cmd := newRootCommandWithConfig(config.DefaultConfig())
+			cmd.SetArgs(args)
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("command failed: %v", err)
+			}
+
+			out, err := os.ReadFile(outPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.Contains(string(out), "func Exported()"); got != tt.wantPublic {
+				t.Errorf("public symbol included = %v, want %v", got, tt.wantPublic)
+			}
+			if got := strings.Contains(string(out), "func hidden()"); got != tt.wantPackage {
+				t.Errorf("package symbol included = %v, want %v", got, tt.wantPackage)
+			}
+		})
+	}
+}
+
+func TestVisibilityFlagInvalid(t *testing.T) {
+	cmd := newRootCommandWithConfig(config.DefaultConfig())
+	cmd.SetArgs([]string{t.TempDir(), "--visibility", "secret"})
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "invalid visibility") {
+		t.Errorf("expected invalid visibility error, got: %v", err)
+	}
+}
+
+func TestStrictFlag(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{name: "off", args: nil},
+		{name: "error ignores syntax errors", args: []string{"--strict"}},
+		{name: "syntax", args: []string{"--strict=syntax"}, wantErr: "--strict=syntax"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			src := "package main\n\nfunc Broken() {\n\tx := )\n}\n\nfunc After() {}\n"
+			if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte(src), 0644); err != nil {
+				t.Fatal(err)
+			}
+			args := append([]string{dir, "-o", filepath.Join(t.TempDir(), "out.xml"), "--no-tokens"}, tt.args...)
+			cmd := newRootCommandWithConfig(config.DefaultConfig())
+			cmd.SetArgs(args)
+			err := cmd.Execute()
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("command failed: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("expected error containing %q, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
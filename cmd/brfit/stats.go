@@ -0,0 +1,202 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/indigo-net/Brf.it/internal/config"
+	"github.com/indigo-net/Brf.it/internal/context"
+	"github.com/indigo-net/Brf.it/pkg/scanner"
+	"github.com/spf13/cobra"
+)
+
+// newStatsCommand creates the "stats" command, which reports file and
+// signature counts, doc coverage and token counts per language and directory.
+func newStatsCommand() *cobra.Command {
+	c := config.DefaultConfig()
+	var statsFormat string
+	cmd := &cobra.Command{
+		Use:   "stats [path] [options]",
+		Short: "Report signature counts, doc coverage and token counts",
+		Long: `Report file counts, signature counts by kind (function/type/variable),
+the exported vs private ratio, doc coverage and source vs brief token counts,
+broken down by language and top-level directory.
+
+Private symbols are always counted. Brief tokens estimate each file's share
+of the default output and include only the symbols selected by --visibility.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runStats(cmd, args, c, statsFormat)
+		},
+	}
+
+	cmd.Flags().StringVarP(&statsFormat, "format", "f", "text",
+		"report format: \"text\" | \"json\"")
+	cmd.Flags().StringVarP(&c.Output, "output", "o", c.Output,
+		"output file path (default: stdout)")
+	cmd.Flags().StringArrayVarP(&c.IgnoreFiles, "ignore", "i", c.IgnoreFiles,
+		"custom ignore file(s), can be specified multiple times (default: .gitignore)")
+	cmd.Flags().StringArrayVar(&c.IncludePatterns, "include", c.IncludePatterns,
+		"glob pattern(s) to include, can be specified multiple times (e.g., \"pkg/**/*.go\")")
+	cmd.Flags().StringArrayVar(&c.ExcludePatterns, "exclude", c.ExcludePatterns,
+		"glob pattern(s) to exclude, can be specified multiple times (e.g., \"**/*_test.go\")")
+	cmd.Flags().BoolVar(&c.IncludeHidden, "include-hidden", c.IncludeHidden,
+		"include hidden files (dotfiles)")
+	cmd.Flags().BoolVar(&c.IncludePrivate, "include-private", c.IncludePrivate,
+		"count symbols of every visibility in brief token counts")
+	_ = cmd.Flags().MarkDeprecated("include-private", "use --visibility all instead")
+	cmd.Flags().StringSliceVar(&c.Visibility, "visibility", c.Visibility,
+		"visibility levels counted in brief token counts (default: public,protected, plus package in Java and internal in C# and Swift)")
+	cmd.Flags().Int64Var(&c.MaxFileSize, "max-size", c.MaxFileSize,
+		"maximum file size in bytes (default: 512000 = 500KB)")
+	cmd.Flags().BoolVar(&c.NoTokens, "no-tokens", c.NoTokens,
+		"disable token counting")
+	cmd.Flags().StringVar(&c.ConfigFile, "config", c.ConfigFile,
+		"config file path (default: discover .brfit.yaml/.brfit.toml from the target path)")
+	cmd.Flags().StringVar(&c.Profile, "profile", c.Profile,
+		"named profile from the config file(s) to apply (e.g., \"review\")")
+
+	return cmd
+}
+
+// runStats is the main execution function for the stats command.
+func runStats(cmd *cobra.Command, args []string, c *config.Config, statsFormat string) error {
+	if statsFormat != "text" && statsFormat != "json" {
+		return fmt.Errorf("invalid format '%s': must be 'text' or 'json'", statsFormat)
+	}
+
+	c.Path = "."
+	if len(args) > 0 {
+		c.Path = args[0]
+	}
+	if _, err := os.Stat(c.Path); os.IsNotExist(err) {
+		return fmt.Errorf("path not found: %s", c.Path)
+	}
+	if absPath, err := filepath.Abs(c.Path); err == nil {
+		c.Path = absPath
+	}
+
+	if _, err := applyConfigFiles(cmd, c, false); err != nil {
+		return fmt.Errorf("configuration error: %w", err)
+	}
+	c.Version = Version
+	if err := c.Validate(); err != nil {
+		return fmt.Errorf("configuration error: %w", err)
+	}
+
+	packager, err := context.NewDefaultPackager(&scanner.ScanOptions{
+		RootPath:            c.Path,
+		SupportedExtensions: c.SupportedExtensions(),
+		IgnoreFiles:         c.IgnoreFiles,
+		IncludePatterns:     c.IncludePatterns,
+		ExcludePatterns:     c.ExcludePatterns,
+		IncludeHidden:       c.IncludeHidden,
+		MaxFileSize:         c.MaxFileSize,
+		PreloadContent:      true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to initialize: %w", err)
+	}
+	if c.NoTokens {
+		packager.SetTokenizer(nil)
+	}
+
+	stats, err := packager.Stats(cmd.Context(), c.ToOptions())
+	if err != nil {
+		return fmt.Errorf("processing failed: %w", err)
+	}
+
+	var content []byte
+	if statsFormat == "json" {
+		if content, err = json.MarshalIndent(stats, "", "  "); err != nil {
+			return fmt.Errorf("output failed: %w", err)
+		}
+		content = append(content, '\n')
+	} else {
+		var sb strings.Builder
+		writeStatsText(&sb, stats)
+		content = []byte(sb.String())
+	}
+	if err := writeOutput(&context.Result{Content: content}, c); err != nil {
+		return fmt.Errorf("output failed: %w", err)
+	}
+	return nil
+}
+
+// writeStatsText writes stats as a human-readable report.
+func writeStatsText(w io.Writer, stats *context.Stats) {
+	t := stats.Total
+	fmt.Fprintf(w, "Path: %s\n", stats.Path)
+	fmt.Fprintf(w, "Files: %d", t.Files)
+	if t.Errors > 0 {
+		fmt.Fprintf(w, " (%d with errors)", t.Errors)
+	}
+	fmt.Fprintf(w, ", Signatures: %d (function %d, type %d, variable %d)\n",
+		t.Signatures, t.Kinds["function"], t.Kinds["type"], t.Kinds["variable"])
+	fmt.Fprintf(w, "Exported: %d (%s), Private: %d, Documented: %d (%s)\n",
+		t.Exported, percent(t.ExportedRatio, t.Signatures), t.Private,
+		t.Documented, percent(t.DocCoverage, t.Signatures))
+	if stats.Tokenizer != "" {
+		fmt.Fprintf(w, "Tokens: source %d, brief %d (%s)\n",
+			t.SourceTokens, t.BriefTokens, percent(ratio(t.BriefTokens, t.SourceTokens), t.SourceTokens))
+	}
+
+	writeStatsTable(w, "LANGUAGE", stats.Languages, stats.Tokenizer != "")
+	writeStatsTable(w, "DIRECTORY", stats.Dirs, stats.Tokenizer != "")
+}
+
+// writeStatsTable writes one row per group, largest API surface first.
+func writeStatsTable(w io.Writer, title string, groups map[string]*context.Counts, tokens bool) {
+	keys := make([]string, 0, len(groups))
+	for k := range groups {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := groups[keys[i]], groups[keys[j]]
+		if a.Signatures != b.Signatures {
+			return a.Signatures > b.Signatures
+		}
+		return keys[i] < keys[j]
+	})
+
+	fmt.Fprintln(w)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "%s\tFILES\tSIGS\tFUNC\tTYPE\tVAR\tEXPORTED\tDOCS", title)
+	if tokens {
+		fmt.Fprint(tw, "\tSOURCE\tBRIEF")
+	}
+	fmt.Fprintln(tw)
+	for _, k := range keys {
+		g := groups[k]
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\t%d\t%s\t%s", k, g.Files, g.Signatures,
+			g.Kinds["function"], g.Kinds["type"], g.Kinds["variable"],
+			percent(g.ExportedRatio, g.Signatures), percent(g.DocCoverage, g.Signatures))
+		if tokens {
+			fmt.Fprintf(tw, "\t%d\t%d", g.SourceTokens, g.BriefTokens)
+		}
+		fmt.Fprintln(tw)
+	}
+	tw.Flush()
+}
+
+// ratio returns n/d, or 0 when d is 0.
+func ratio(n, d int) float64 {
+	if d == 0 {
+		return 0
+	}
+	return float64(n) / float64(d)
+}
+
+// percent formats r as a percentage, or "-" when the denominator is 0.
+func percent(r float64, denominator int) string {
+	if denominator == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", r*100)
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/indigo-net/Brf.it/internal/config"
+	"github.com/indigo-net/Brf.it/internal/context"
+)
+
+// setupStatsDir writes a small Go project and returns its path.
+func setupStatsDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"main.go":       "package main\n\nfunc main() {}\n",
+		"api/client.go": "package api\n\ntype Client struct{}\n\nfunc (c *Client) Do() error { return nil }\n\nfunc helper() {}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestStatsCommandJSON(t *testing.T) {
+	dir := setupStatsDir(t)
+	out := filepath.Join(t.TempDir(), "stats.json")
+
+	cmd := newRootCommandWithConfig(config.DefaultConfig())
+	cmd.SetArgs([]string{"stats", dir, "-f", "json", "-o", out, "--no-tokens"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("stats failed: %v", err)
+	}
+
+	content, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stats context.Stats
+	if err := json.Unmarshal(content, &stats); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, content)
+	}
+	if stats.Total.Files != 2 {
+		t.Errorf("expected 2 files, got %d", stats.Total.Files)
+	}
+	if stats.Total.Private == 0 {
+		t.Error("expected private symbols to be counted")
+	}
+	if got := stats.Dirs["api"]; got == nil || got.Files != 1 {
+		t.Errorf("unexpected api dir counts: %+v", got)
+	}
+	if got := stats.Languages["go"]; got == nil || got.Files != 2 {
+		t.Errorf("unexpected go counts: %+v", got)
+	}
+}
+
+func TestStatsCommandInvalidFormat(t *testing.T) {
+	cmd := newRootCommandWithConfig(config.DefaultConfig())
+	cmd.SetArgs([]string{"stats", setupStatsDir(t), "-f", "xml"})
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "must be 'text' or 'json'") {
+		t.Errorf("expected format error, got %v", err)
+	}
+}
+
+func TestWriteStatsText(t *testing.T) {
+	counts := func(files, sigs, exported, documented int) *context.Counts {
+		c := &context.Counts{
+			Files: files, Signatures: sigs, Exported: exported, Private: sigs - exported,
+			Documented: documented, Kinds: map[string]int{"function": sigs},
+			SourceTokens: 1000, BriefTokens: 200,
+		}
+		if sigs > 0 {
+			c.ExportedRatio = float64(exported) / float64(sigs)
+			c.DocCoverage = float64(documented) / float64(sigs)
+		}
+		return c
+	}
+	stats := &context.Stats{
+		Path:      "/repo",
+		Tokenizer: "cl100k_base",
+		Total:     counts(3, 8, 4, 2),
+		Languages: map[string]*context.Counts{"go": counts(3, 8, 4, 2)},
+		Dirs: map[string]*context.Counts{
+			"api":  counts(1, 6, 4, 2),
+			".":    counts(1, 2, 0, 0),
+			"docs": counts(1, 0, 0, 0),
+		},
+	}
+
+	var sb strings.Builder
+	writeStatsText(&sb, stats)
+	out := sb.String()
+
+	for _, want := range []string{
+		"Files: 3, Signatures: 8 (function 8, type 0, variable 0)",
+		"Exported: 4 (50.0%), Private: 4, Documented: 2 (25.0%)",
+		"Tokens: source 1000, brief 200 (20.0%)",
+		"LANGUAGE",
+		"SOURCE",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+
+	// Directories are ordered by signature count; no signatures shows "-".
+	api, root, docs := strings.Index(out, "\napi "), strings.Index(out, "\n. "), strings.Index(out, "\ndocs ")
+	if api < 0 || root < 0 || docs < 0 || !(api < root && root < docs) {
+		t.Errorf("unexpected directory order:\n%s", out)
+	}
+	if !strings.Contains(out[docs:], "-") {
+		t.Errorf("expected \"-\" for a directory without signatures:\n%s", out)
+	}
+}
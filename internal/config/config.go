@@ -5,14 +5,44 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
 
 	pkgcontext "github.com/indigo-net/Brf.it/internal/context"
+	"github.com/indigo-net/Brf.it/pkg/formatter"
+	"github.com/indigo-net/Brf.it/pkg/parser"
 )
 
+// DefaultParseTimeout is the default per-file parse timeout. Source files
+// parse in milliseconds; only pathological ones come near it.
+const DefaultParseTimeout = 10 * time.Second
+
+// QueryDirName is the directory of the user-supplied query overrides,
+// found from the target path upward like the project config.
+var QueryDirName = filepath.Join(".brfit", "queries")
+
 // MaxFileSizeUpperBound is the maximum allowed value for MaxFileSize (10MB).
 // Values above this threshold trigger a warning (not an error).
 const MaxFileSizeUpperBound = 10 * 1024 * 1024
 
+// Strict levels select which parsing problems make brfit exit with an error.
+const (
+	// StrictOff never fails on parsing problems.
+	StrictOff = "off"
+
+	// StrictError fails when a file could not be parsed at all.
+	StrictError = "error"
+
+	// StrictSyntax also fails when a file parsed with syntax errors.
+	StrictSyntax = "syntax"
+)
+
+// StrictLevels lists the supported strict levels.
+var StrictLevels = []string{StrictOff, StrictError, StrictSyntax}
+
 // Config holds all configuration options for the brfit CLI.
 type Config struct {
 	// Path is the root directory or file to process.
@@ -21,7 +51,8 @@ type Config struct {
 	// Version is the brf.it version string.
 	Version string
 
-	// Mode determines what to extract. Currently only "sig" (signature) is supported.
+	// Mode determines what to extract: "sig" (signatures), "outline" (symbol
+	// names and kinds), "docs" (documentation comments) or "full" (raw source).
 	Mode string
 
 	// Format specifies the output format: "xml" or "md".
@@ -31,6 +62,7 @@ type Config struct {
 	Output string
 
 	// IgnoreFiles is the list of ignore file paths (default: [".gitignore"]).
+	// The ".gitignore" entry enables per-directory .gitignore handling.
 	IgnoreFiles []string
 
 	// IncludePatterns is a list of glob patterns. Only matching files are included.
@@ -59,9 +91,18 @@ type Config struct {
 	// NoTokens disables token count calculation.
 	NoTokens bool
 
-	// IncludePrivate determines whether to include non-exported/private symbols.
+	// IncludePrivate determines whether to include symbols of every
+	// visibility. Deprecated in favor of Visibility "all".
 	IncludePrivate bool
 
+	// Visibility lists the visibility levels of the symbols to include
+	// (e.g., "public", "protected", or "all"). Empty means public and protected,
+	// plus the implicit access level of Java, C# and Swift.
+	Visibility []string
+
+	// ExcludeDeprecated leaves deprecated symbols out of the output.
+	ExcludeDeprecated bool
+
 	// Changed restricts scanning to files changed in the git working tree.
 	Changed bool
 
@@ -80,21 +121,60 @@ type Config struct {
 	// CallGraph enables function call graph extraction in output.
 	CallGraph bool
 
+	// Locations adds symbol IDs and source ranges to XML and Markdown output.
+	Locations bool
+
+	// Structured adds parameters, return types, type parameters, receivers
+	// and modifiers to XML output as attributes.
+	Structured bool
+
 	// Remote is a git URL or owner/repo shorthand for remote repository analysis.
 	Remote string
 
 	// MaxFileSize is the maximum file size in bytes to process.
 	MaxFileSize int64
 
+	// ParseTimeout limits the time spent parsing each file. A file that
+	// takes longer fails with a timeout error. 0 means no limit.
+	ParseTimeout time.Duration
+
+	// QueryDir is the directory of the Tree-sitter query overrides (see
+	// parser.LoadQueries). Empty means QueryDirName is discovered from Path.
+	QueryDir string
+
+	// Extensions maps additional file extensions to supported languages
+	// (e.g., ".mjs" to "javascript"), overriding the built-in mapping.
+	Extensions map[string]string
+
 	// MaxDocLength is the maximum length of documentation comments in characters.
 	// 0 means no limit (default).
 	MaxDocLength int
 
-	// Strict enables strict mode where any file parsing error causes a non-zero exit code.
-	Strict bool
+	// DocStyle selects how much of each documentation comment to include:
+	// "full" (default), "summary-line" or "params-only".
+	DocStyle string
+
+	// MaxTokens is the token budget for the output. 0 means no limit.
+	MaxTokens int
+
+	// SplitTokens splits the output into numbered files of at most this many
+	// tokens each, plus a manifest. Requires Output. 0 means no splitting.
+	SplitTokens int
+
+	// Strict is the strict level: StrictOff, StrictError (any file parsing
+	// error causes a non-zero exit code) or StrictSyntax (syntax errors do
+	// too). See StrictLevel for the accepted forms.
+	Strict string
 
 	// SkipEmpty omits files with no signatures/imports from the output entirely.
 	SkipEmpty bool
+
+	// ConfigFile is an explicit config file path. Empty means the project
+	// config (.brfit.yaml, .brfit.yml or .brfit.toml) is discovered from Path.
+	ConfigFile string
+
+	// Profile selects a named profile from the config files (e.g., "review").
+	Profile string
 }
 
 // DefaultConfig returns a Config with all default values set.
@@ -113,7 +193,10 @@ func DefaultConfig() *Config {
 		NoTokens:       false,
 		NoSchema:       true, // skip schema by default to save tokens
 		MaxFileSize:    512000, // 500KB
-		MaxDocLength:   0,      // no limit
+		ParseTimeout:   DefaultParseTimeout,
+		MaxDocLength:   0, // no limit
+		DocStyle:       formatter.DocStyleFull,
+		Strict:         StrictOff,
 		SkipEmpty:      true,
 	}
 }
@@ -122,8 +205,8 @@ func DefaultConfig() *Config {
 // Returns an error describing the first invalid field, or nil if valid.
 func (c *Config) Validate() error {
 	// Validate mode
-	if c.Mode != "sig" {
-		return fmt.Errorf("invalid mode '%s': only 'sig' mode is supported", c.Mode)
+	if !slices.Contains(formatter.Modes, c.Mode) {
+		return fmt.Errorf("invalid mode '%s': must be 'sig', 'outline', 'docs', or 'full'", c.Mode)
 	}
 
 	// Validate format (accept "xml", "md", "markdown", "json")
@@ -132,11 +215,62 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("invalid format '%s': must be 'xml', 'md', 'markdown', or 'json'", c.Format)
 	}
 
+	// Validate visibility levels
+	if _, err := parser.ParseVisibility(c.Visibility); err != nil {
+		return err
+	}
+
+	// Validate doc style (empty means full)
+	if c.DocStyle != "" && !slices.Contains(formatter.DocStyles, c.DocStyle) {
+		return fmt.Errorf("invalid doc style '%s': must be 'full', 'summary-line', or 'params-only'", c.DocStyle)
+	}
+
+	// Validate strict level
+	if !slices.Contains(StrictLevels, c.StrictLevel()) {
+		return fmt.Errorf("invalid strict level '%s': must be 'off', 'error', or 'syntax'", c.Strict)
+	}
+
 	// Validate max file size
 	if c.MaxFileSize <= 0 {
 		return errors.New("max file size must be positive")
 	}
 
+	// Validate parse timeout
+	if c.ParseTimeout < 0 {
+		return errors.New("parse timeout must not be negative")
+	}
+
+	// Validate query directory
+	if c.QueryDir != "" {
+		if info, err := os.Stat(c.QueryDir); err != nil || !info.IsDir() {
+			return fmt.Errorf("query directory not found: %s", c.QueryDir)
+		}
+	}
+
+	// Validate extension mappings
+	for _, ext := range sortedKeys(c.Extensions) {
+		if lang := c.Extensions[ext]; !parser.IsLanguage(lang) {
+			return fmt.Errorf("invalid extension mapping '%s=%s': unknown language '%s'", ext, lang, lang)
+		}
+	}
+
+	// Validate token budget
+	if c.MaxTokens < 0 {
+		return errors.New("max tokens must not be negative")
+	}
+	if c.MaxTokens > 0 && c.NoTokens {
+		return errors.New("--max-tokens requires token counting and cannot be combined with --no-tokens")
+	}
+	if c.SplitTokens < 0 {
+		return errors.New("split tokens must not be negative")
+	}
+	if c.SplitTokens > 0 && c.NoTokens {
+		return errors.New("--split-tokens requires token counting and cannot be combined with --no-tokens")
+	}
+	if c.SplitTokens > 0 && c.Output == "" {
+		return errors.New("--split-tokens requires --output to name the chunk files")
+	}
+
 	// Warn if max file size exceeds upper bound (not an error)
 	if c.MaxFileSize > MaxFileSizeUpperBound {
 		fmt.Fprintf(os.Stderr, "[brfit] WARN: max-size %d bytes exceeds recommended upper bound of %d bytes (10MB)\n",
@@ -146,39 +280,97 @@ func (c *Config) Validate() error {
 	return nil
 }
 
-// SupportedExtensions returns a map of file extensions to language names.
+// StrictLevel returns the strict level of c. An empty Strict is StrictOff,
+// and the boolean values of configs written before strict levels existed
+// ("true", "false") are StrictError and StrictOff.
+func (c *Config) StrictLevel() string {
+	switch c.Strict {
+	case "", "false":
+		return StrictOff
+	case "true":
+		return StrictError
+	}
+	return c.Strict
+}
+
+// SupportedExtensions returns a map of file extensions to language names,
+// with the mappings of Extensions added. Extensions are matched without
+// regard to case and the leading dot is optional.
 func (c *Config) SupportedExtensions() map[string]string {
+	exts := builtinExtensions()
+	for ext, lang := range c.Extensions {
+		exts[normalizeExtension(ext)] = lang
+	}
+	return exts
+}
+
+// QueryDirectory returns the directory of the query overrides: QueryDir,
+// or else the QueryDirName found from Path. A remote repository's own
+// queries are never used.
+func (c *Config) QueryDirectory() string {
+	if c.QueryDir != "" || c.Remote != "" {
+		return c.QueryDir
+	}
+	return FindQueryDir(c.Path)
+}
+
+// normalizeExtension returns ext in lower case with a leading dot.
+func normalizeExtension(ext string) string {
+	ext = strings.ToLower(strings.TrimSpace(ext))
+	if !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	return ext
+}
+
+// sortedKeys returns the keys of m in sorted order.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// builtinExtensions returns the built-in map of file extensions to language names.
+func builtinExtensions() map[string]string {
 	return map[string]string{
-		".go":    "go",
-		".ts":    "typescript",
-		".tsx":   "typescript",
-		".js":    "javascript",
-		".jsx":   "javascript",
-		".py":    "python",
-		".c":     "c",
-		".cpp":   "cpp",
-		".hpp":   "cpp",
-		".h":     "cpp",
-		".java":  "java",
-		".rs":    "rust",
-		".swift": "swift",
-		".kt":    "kotlin",
-		".kts":   "kotlin",
-		".cs":    "csharp",
-		".lua":   "lua",
-		".sh":    "shell",
-		".bash":  "shell",
-		".zsh":   "shell",
-		".php":   "php",
-		".rb":    "ruby",
-		".scala": "scala",
-		".sc":    "scala",
-		".ex":    "elixir",
-		".exs":   "elixir",
-		".sql":   "sql",
-		".yaml":  "yaml",
-		".yml":   "yaml",
-		".toml":  "toml",
+		".go":     "go",
+		".ts":     "typescript",
+		".tsx":    "tsx",
+		".js":     "javascript",
+		".jsx":    "jsx",
+		".py":     "python",
+		".c":      "c",
+		".cpp":    "cpp",
+		".hpp":    "cpp",
+		".h":      "cpp",
+		".java":   "java",
+		".rs":     "rust",
+		".swift":  "swift",
+		".kt":     "kotlin",
+		".kts":    "kotlin",
+		".cs":     "csharp",
+		".lua":    "lua",
+		".sh":     "shell",
+		".bash":   "shell",
+		".zsh":    "shell",
+		".php":    "php",
+		".rb":     "ruby",
+		".scala":  "scala",
+		".sc":     "scala",
+		".ex":     "elixir",
+		".exs":    "elixir",
+		".sql":    "sql",
+		".yaml":   "yaml",
+		".yml":    "yaml",
+		".toml":   "toml",
+		".vue":    "vue",
+		".svelte": "svelte",
+		".astro":  "astro",
+		".html":   "html",
+		".htm":    "html",
 	}
 }
 
@@ -196,11 +388,28 @@ func (c *Config) ToOptions() *pkgcontext.Options {
 		DedupeImports:  c.DedupeImports,
 		IncludeTree:    !c.NoTree,
 		IncludePrivate: c.IncludePrivate,
+		Visibility:     c.VisibilityLevels(),
+		ExcludeDeprecated: c.ExcludeDeprecated,
 		MaxFileSize:    c.MaxFileSize,
+		ParseTimeout:   c.ParseTimeout,
+		QueryDir:       c.QueryDirectory(),
 		MaxDocLength:   c.MaxDocLength,
+		DocStyle:       c.DocStyle,
 		NoSchema:         c.NoSchema,
 		SecurityCheck:    c.SecurityCheck,
 		IncludeCallGraph: c.CallGraph,
+		IncludeLocations: c.Locations,
+		IncludeStructure: c.Structured,
 		SkipEmpty:        c.SkipEmpty,
+		MaxTokens:        c.MaxTokens,
+		SplitTokens:      c.SplitTokens,
+		Mode:             c.Mode,
 	}
 }
+
+// VisibilityLevels returns the parsed Visibility levels. Invalid levels are
+// reported by Validate and ignored here.
+func (c *Config) VisibilityLevels() []parser.Visibility {
+	levels, _ := parser.ParseVisibility(c.Visibility)
+	return levels
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -46,6 +47,10 @@ func TestDefaultConfig(t *testing.T) {
 	if cfg.MaxFileSize != expectedMaxSize {
 		t.Errorf("expected max file size %d, got %d", expectedMaxSize, cfg.MaxFileSize)
 	}
+
+	if cfg.ParseTimeout != DefaultParseTimeout {
+		t.Errorf("expected parse timeout %v, got %v", DefaultParseTimeout, cfg.ParseTimeout)
+	}
 }
 
 func TestConfigValidate(t *testing.T) {
@@ -87,6 +92,33 @@ func TestConfigValidate(t *testing.T) {
 			},
 			wantError: false,
 		},
+		{
+			name: "valid outline mode",
+			config: Config{
+				Mode:        "outline",
+				Format:      "xml",
+				MaxFileSize: 512000,
+			},
+			wantError: false,
+		},
+		{
+			name: "valid docs mode",
+			config: Config{
+				Mode:        "docs",
+				Format:      "md",
+				MaxFileSize: 512000,
+			},
+			wantError: false,
+		},
+		{
+			name: "valid full mode",
+			config: Config{
+				Mode:        "full",
+				Format:      "json",
+				MaxFileSize: 512000,
+			},
+			wantError: false,
+		},
 		{
 			name: "invalid mode",
 			config: Config{
@@ -125,6 +157,135 @@ func TestConfigValidate(t *testing.T) {
 			wantError: true,
 			errorMsg:  "max file size must be positive",
 		},
+		{
+			name: "negative max tokens",
+			config: Config{
+				Mode:        "sig",
+				Format:      "xml",
+				MaxFileSize: 512000,
+				MaxTokens:   -1,
+			},
+			wantError: true,
+			errorMsg:  "max tokens must not be negative",
+		},
+		{
+			name: "max tokens with no tokens",
+			config: Config{
+				Mode:        "sig",
+				Format:      "xml",
+				MaxFileSize: 512000,
+				MaxTokens:   1000,
+				NoTokens:    true,
+			},
+			wantError: true,
+			errorMsg:  "cannot be combined with --no-tokens",
+		},
+		{
+			name: "valid visibility",
+			config: Config{
+				Mode:        "sig",
+				Format:      "xml",
+				MaxFileSize: 512000,
+				Visibility:  []string{"public,internal", "private"},
+			},
+			wantError: false,
+		},
+		{
+			name: "invalid visibility",
+			config: Config{
+				Mode:        "sig",
+				Format:      "xml",
+				MaxFileSize: 512000,
+				Visibility:  []string{"public,secret"},
+			},
+			wantError: true,
+			errorMsg:  "invalid visibility",
+		},
+		{
+			name: "valid doc style",
+			config: Config{
+				Mode:        "sig",
+				Format:      "xml",
+				MaxFileSize: 512000,
+				DocStyle:    "params-only",
+			},
+			wantError: false,
+		},
+		{
+			name: "invalid doc style",
+			config: Config{
+				Mode:        "sig",
+				Format:      "xml",
+				MaxFileSize: 512000,
+				DocStyle:    "brief",
+			},
+			wantError: true,
+			errorMsg:  "invalid doc style",
+		},
+		{
+			name: "negative parse timeout",
+			config: Config{
+				Mode:         "sig",
+				Format:       "xml",
+				MaxFileSize:  512000,
+				ParseTimeout: -time.Second,
+			},
+			wantError: true,
+			errorMsg:  "parse timeout must not be negative",
+		},
+		{
+			name: "valid strict level",
+			config: Config{
+				Mode:        "sig",
+				Format:      "xml",
+				MaxFileSize: 512000,
+				Strict:      "syntax",
+			},
+			wantError: false,
+		},
+		{
+			name: "invalid strict level",
+			config: Config{
+				Mode:        "sig",
+				Format:      "xml",
+				MaxFileSize: 512000,
+				Strict:      "warnings",
+			},
+			wantError: true,
+			errorMsg:  "invalid strict level",
+		},
+		{
+			name: "valid extension mapping",
+			config: Config{
+				Mode:        "sig",
+				Format:      "xml",
+				MaxFileSize: 512000,
+				Extensions:  map[string]string{".mjs": "javascript", "pyi": "python"},
+			},
+			wantError: false,
+		},
+		{
+			name: "extension mapped to unknown language",
+			config: Config{
+				Mode:        "sig",
+				Format:      "xml",
+				MaxFileSize: 512000,
+				Extensions:  map[string]string{".dsl": "cobol"},
+			},
+			wantError: true,
+			errorMsg:  "unknown language 'cobol'",
+		},
+		{
+			name: "missing query directory",
+			config: Config{
+				Mode:        "sig",
+				Format:      "xml",
+				MaxFileSize: 512000,
+				QueryDir:    "/nonexistent/brfit/queries",
+			},
+			wantError: true,
+			errorMsg:  "query directory not found",
+		},
 	}
 
 	for _, tt := range tests {
@@ -148,6 +309,23 @@ func TestConfigValidate(t *testing.T) {
 	}
 }
 
+func TestStrictLevel(t *testing.T) {
+	tests := map[string]string{
+		"":       StrictOff,
+		"false":  StrictOff,
+		"off":    StrictOff,
+		"true":   StrictError,
+		"error":  StrictError,
+		"syntax": StrictSyntax,
+	}
+	for strict, want := range tests {
+		c := Config{Strict: strict}
+		if got := c.StrictLevel(); got != want {
+			t.Errorf("StrictLevel() with Strict %q = %q, want %q", strict, got, want)
+		}
+	}
+}
+
 func TestConfigSupportedLanguages(t *testing.T) {
 	cfg := DefaultConfig()
 	langs := cfg.SupportedExtensions()
@@ -155,10 +333,11 @@ func TestConfigSupportedLanguages(t *testing.T) {
 	expected := map[string]string{
 		".go":  "go",
 		".ts":  "typescript",
-		".tsx": "typescript",
+		".tsx": "tsx",
 		".js":  "javascript",
-		".jsx": "javascript",
+		".jsx": "jsx",
 		".py":  "python",
+		".vue": "vue",
 	}
 
 	for ext, lang := range expected {
@@ -170,6 +349,24 @@ func TestConfigSupportedLanguages(t *testing.T) {
 	}
 }
 
+func TestConfigExtensions(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Extensions = map[string]string{".mjs": "javascript", "PYI": "python", ".h": "c"}
+	langs := cfg.SupportedExtensions()
+
+	expected := map[string]string{
+		".mjs": "javascript",
+		".pyi": "python",
+		".h":   "c",
+		".go":  "go",
+	}
+	for ext, lang := range expected {
+		if got := langs[ext]; got != lang {
+			t.Errorf("expected extension '%s' to map to '%s', got '%s'", ext, lang, got)
+		}
+	}
+}
+
 func TestValidateMaxFileSizeUpperBound(t *testing.T) {
 	tests := []struct {
 		name        string
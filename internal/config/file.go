@@ -0,0 +1,673 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// ProjectFileNames lists the repo-local config file names, in lookup order.
+var ProjectFileNames = []string{".brfit.yaml", ".brfit.yml", ".brfit.toml"}
+
+// userFileNames lists the user-level config file names inside <UserConfigDir>/brfit.
+var userFileNames = []string{"config.yaml", "config.yml", "config.toml"}
+
+// EnvPrefix is the prefix for environment variables that override config keys.
+// A key such as "max-doc-length" maps to BRFIT_MAX_DOC_LENGTH.
+const EnvPrefix = "BRFIT_"
+
+// SourceDefault is the source reported for keys that no layer or flag set.
+const SourceDefault = "default"
+
+// profilesKey is the top-level config file key holding named profiles.
+const profilesKey = "profiles"
+
+// EnvProfile selects a profile when --profile is not given.
+const EnvProfile = EnvPrefix + "PROFILE"
+
+// Layer is one source of configuration values (a file or the environment).
+type Layer struct {
+	// Source describes where the values came from (e.g., "project config /repo/.brfit.yaml").
+	Source string
+
+	// Values maps config keys (CLI flag names) to raw decoded values.
+	Values map[string]any
+
+	// Profiles maps profile names to values that override Values when the
+	// profile is selected. Only config files define profiles.
+	Profiles map[string]map[string]any
+}
+
+// Sources maps each config key to a description of where its effective value came from.
+type Sources map[string]string
+
+// LoadOptions controls which layers LoadLayers reads.
+type LoadOptions struct {
+	// ConfigFile is an explicit config file path. When set, it replaces
+	// project config discovery.
+	ConfigFile string
+
+	// SkipProject disables project config discovery (e.g., for --remote,
+	// where the config file would come from an untrusted repository).
+	SkipProject bool
+
+	// UserConfigDir overrides os.UserConfigDir() for user-level lookup.
+	UserConfigDir string
+
+	// Environ is the environment to read BRFIT_* variables from.
+	// os.Environ() is used when nil.
+	Environ []string
+
+	// Profile selects a named profile from the config files.
+	// BRFIT_PROFILE is used when empty.
+	Profile string
+}
+
+// setting describes a single config key shared by flags, files and env vars.
+type setting struct {
+	key string
+	get func(c *Config) string
+	set func(c *Config, v any) error
+}
+
+// settings lists every configurable key in display order.
+// Keys match the CLI flag names so that files, env vars and flags line up.
+var settings = []setting{
+	stringSetting("mode", func(c *Config) *string { return &c.Mode }),
+	stringSetting("format", func(c *Config) *string { return &c.Format }),
+	stringSetting("output", func(c *Config) *string { return &c.Output }),
+	stringsSetting("ignore", func(c *Config) *[]string { return &c.IgnoreFiles }),
+	stringsSetting("include", func(c *Config) *[]string { return &c.IncludePatterns }),
+	stringsSetting("exclude", func(c *Config) *[]string { return &c.ExcludePatterns }),
+	boolSetting("include-hidden", func(c *Config) *bool { return &c.IncludeHidden }),
+	boolSetting("include-body", func(c *Config) *bool { return &c.IncludeBody }),
+	boolSetting("include-imports", func(c *Config) *bool { return &c.IncludeImports }),
+	boolSetting("include-private", func(c *Config) *bool { return &c.IncludePrivate }),
+	stringsSetting("visibility", func(c *Config) *[]string { return &c.Visibility }),
+	boolSetting("exclude-deprecated", func(c *Config) *bool { return &c.ExcludeDeprecated }),
+	boolSetting("dedupe-imports", func(c *Config) *bool { return &c.DedupeImports }),
+	boolSetting("no-tree", func(c *Config) *bool { return &c.NoTree }),
+	boolSetting("no-tokens", func(c *Config) *bool { return &c.NoTokens }),
+	boolSetting("no-schema", func(c *Config) *bool { return &c.NoSchema }),
+	boolSetting("skip-empty", func(c *Config) *bool { return &c.SkipEmpty }),
+	boolSetting("call-graph", func(c *Config) *bool { return &c.CallGraph }),
+	boolSetting("locations", func(c *Config) *bool { return &c.Locations }),
+	boolSetting("structured", func(c *Config) *bool { return &c.Structured }),
+	boolSetting("security-check", func(c *Config) *bool { return &c.SecurityCheck }),
+	strictSetting("strict", func(c *Config) *string { return &c.Strict }),
+	int64Setting("max-size", func(c *Config) *int64 { return &c.MaxFileSize }),
+	durationSetting("parse-timeout", func(c *Config) *time.Duration { return &c.ParseTimeout }),
+	stringSetting("query-dir", func(c *Config) *string { return &c.QueryDir }),
+	stringMapSetting("extensions", func(c *Config) *map[string]string { return &c.Extensions }),
+	intSetting("max-doc-length", func(c *Config) *int { return &c.MaxDocLength }),
+	stringSetting("doc-style", func(c *Config) *string { return &c.DocStyle }),
+	intSetting("max-tokens", func(c *Config) *int { return &c.MaxTokens }),
+	intSetting("split-tokens", func(c *Config) *int { return &c.SplitTokens }),
+}
+
+// Keys returns all configurable keys in display order.
+func Keys() []string {
+	keys := make([]string, len(settings))
+	for i, s := range settings {
+		keys[i] = s.key
+	}
+	return keys
+}
+
+// lookupSetting returns the setting for key, or nil if the key is unknown.
+func lookupSetting(key string) *setting {
+	for i := range settings {
+		if settings[i].key == key {
+			return &settings[i]
+		}
+	}
+	return nil
+}
+
+// Get returns the effective value of key formatted for display.
+// Returns an empty string for unknown keys.
+func (c *Config) Get(key string) string {
+	if s := lookupSetting(key); s != nil {
+		return s.get(c)
+	}
+	return ""
+}
+
+// Apply applies layers to c in order (lowest precedence first).
+// Keys for which isSet returns true (typically flags given on the command line)
+// are left untouched. isSet may be nil. The returned Sources describes where
+// each key's effective value came from.
+func (c *Config) Apply(layers []*Layer, isSet func(key string) bool) (Sources, error) {
+	sources := make(Sources, len(settings))
+	for _, s := range settings {
+		sources[s.key] = SourceDefault
+	}
+
+	for _, layer := range layers {
+		if layer == nil {
+			continue
+		}
+		// Sort keys so the first reported error is deterministic.
+		keys := make([]string, 0, len(layer.Values))
+		for k := range layer.Values {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			s := lookupSetting(key)
+			if s == nil {
+				return nil, fmt.Errorf("%s: unknown key %q", layer.Source, key)
+			}
+			if isSet != nil && isSet(key) {
+				continue
+			}
+			if err := s.set(c, layer.Values[key]); err != nil {
+				return nil, fmt.Errorf("%s: key %q: %w", layer.Source, key, err)
+			}
+			sources[key] = layer.Source
+		}
+	}
+
+	if isSet != nil {
+		for _, s := range settings {
+			if isSet(s.key) {
+				sources[s.key] = "flag --" + s.key
+			}
+		}
+	}
+
+	return sources, nil
+}
+
+// LoadLayers reads the user config, the project config and BRFIT_* environment
+// variables for the given target path. Layers are returned lowest precedence
+// first: user config, project config (or opts.ConfigFile), environment.
+// When a profile is selected, each file's profile values are layered directly
+// above that file's own values. Missing config files are not an error, but a
+// selected profile that no file defines is.
+func LoadLayers(path string, opts *LoadOptions) ([]*Layer, error) {
+	if opts == nil {
+		opts = &LoadOptions{}
+	}
+
+	environ := opts.Environ
+	if environ == nil {
+		environ = os.Environ()
+	}
+
+	profile := opts.Profile
+	if profile == "" {
+		profile = lookupEnv(environ, EnvProfile)
+	}
+
+	var files []*Layer
+
+	// 1. User-level config
+	userDir := opts.UserConfigDir
+	if userDir == "" {
+		if dir, err := os.UserConfigDir(); err == nil {
+			userDir = dir
+		}
+	}
+	if userDir != "" {
+		if file := findFile(filepath.Join(userDir, "brfit"), userFileNames); file != "" {
+			layer, err := LoadFile(file, "user config")
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, layer)
+		}
+	}
+
+	// 2. Project config (explicit file wins over discovery)
+	projectFile := opts.ConfigFile
+	if projectFile == "" && !opts.SkipProject {
+		projectFile = FindProjectFile(path)
+	}
+	if projectFile != "" {
+		layer, err := LoadFile(projectFile, "project config")
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, layer)
+	}
+
+	layers := make([]*Layer, 0, len(files)*2)
+	found := profile == ""
+	for _, file := range files {
+		layers = append(layers, file)
+		if values, ok := file.Profiles[profile]; ok && profile != "" {
+			layers = append(layers, &Layer{
+				Source: file.Source + " (profile " + profile + ")",
+				Values: values,
+			})
+			found = true
+		}
+	}
+	if !found {
+		available := profileNames(files)
+		if len(available) == 0 {
+			return nil, fmt.Errorf("profile %q not found: no config file defines profiles", profile)
+		}
+		return nil, fmt.Errorf("profile %q not found in config files (available: %s)",
+			profile, strings.Join(available, ", "))
+	}
+
+	// 3. Environment variables
+	layers = append(layers, EnvLayers(environ)...)
+
+	return layers, nil
+}
+
+// profileNames returns the sorted, de-duplicated profile names defined by layers.
+func profileNames(layers []*Layer) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, layer := range layers {
+		for name := range layer.Profiles {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lookupEnv returns the value of name in environ, or "" if it is not set.
+func lookupEnv(environ []string, name string) string {
+	for _, kv := range environ {
+		if k, v, ok := strings.Cut(kv, "="); ok && k == name {
+			return v
+		}
+	}
+	return ""
+}
+
+// FindProjectFile looks for a repo-local config file starting at path and
+// walking up through parent directories. The search stops after the first
+// directory containing a .git entry (the repository root).
+// Returns an empty string if no config file is found.
+func FindProjectFile(path string) string {
+	return findUp(path, func(dir string) string {
+		return findFile(dir, ProjectFileNames)
+	})
+}
+
+// FindQueryDir looks for the QueryDirName directory starting at path and
+// walking up to the repository root, like FindProjectFile.
+// Returns an empty string if no query directory is found.
+func FindQueryDir(path string) string {
+	return findUp(path, func(dir string) string {
+		queries := filepath.Join(dir, QueryDirName)
+		if info, err := os.Stat(queries); err == nil && info.IsDir() {
+			return queries
+		}
+		return ""
+	})
+}
+
+// findUp returns the first non-empty result of find for the directory of
+// path and its parents, up to the first directory containing a .git entry.
+func findUp(path string, find func(dir string) string) string {
+	dir, err := filepath.Abs(path)
+	if err != nil {
+		return ""
+	}
+	if info, err := os.Stat(dir); err == nil && !info.IsDir() {
+		dir = filepath.Dir(dir)
+	}
+
+	for {
+		if found := find(dir); found != "" {
+			return found
+		}
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return ""
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// findFile returns the first of names that exists as a regular file in dir.
+func findFile(dir string, names []string) string {
+	for _, name := range names {
+		file := filepath.Join(dir, name)
+		if info, err := os.Stat(file); err == nil && info.Mode().IsRegular() {
+			return file
+		}
+	}
+	return ""
+}
+
+// LoadFile reads a YAML or TOML config file (chosen by extension).
+// kind is used as a prefix for the layer source (e.g., "project config").
+func LoadFile(path, kind string) (*Layer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file %q: %w", path, err)
+	}
+
+	values := make(map[string]any)
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".toml":
+		if _, err := toml.Decode(string(data), &values); err != nil {
+			return nil, fmt.Errorf("failed to parse config file %q: %w", path, err)
+		}
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, &values); err != nil {
+			return nil, fmt.Errorf("failed to parse config file %q: %w", path, err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported config file %q: must be .yaml, .yml or .toml", path)
+	}
+
+	layer := &Layer{
+		Source: kind + " " + path,
+		Values: values,
+	}
+
+	if raw, ok := values[profilesKey]; ok {
+		delete(values, profilesKey)
+		profiles, err := decodeProfiles(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %q in config file %q: %w", profilesKey, path, err)
+		}
+		layer.Profiles = profiles
+	}
+
+	return layer, nil
+}
+
+// decodeProfiles converts the decoded "profiles" table into name → values maps.
+func decodeProfiles(raw any) (map[string]map[string]any, error) {
+	table, ok := raw.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("expected a mapping of profile names, got %T", raw)
+	}
+	profiles := make(map[string]map[string]any, len(table))
+	for name, v := range table {
+		values, ok := v.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("profile %q: expected a mapping of keys, got %T", name, v)
+		}
+		profiles[name] = values
+	}
+	return profiles, nil
+}
+
+// EnvLayers builds one layer per BRFIT_* variable in environ, so each key
+// reports its own variable as the source. Variables that do not correspond
+// to a known key are ignored. Layers are sorted by variable name.
+func EnvLayers(environ []string) []*Layer {
+	var layers []*Layer
+	for _, kv := range environ {
+		name, value, ok := strings.Cut(kv, "=")
+		if !ok || !strings.HasPrefix(name, EnvPrefix) {
+			continue
+		}
+		key := strings.ToLower(strings.ReplaceAll(strings.TrimPrefix(name, EnvPrefix), "_", "-"))
+		if lookupSetting(key) == nil {
+			continue
+		}
+		layers = append(layers, &Layer{
+			Source: "env " + name,
+			Values: map[string]any{key: value},
+		})
+	}
+	sort.Slice(layers, func(i, j int) bool {
+		return layers[i].Source < layers[j].Source
+	})
+	return layers
+}
+
+func stringSetting(key string, field func(*Config) *string) setting {
+	return setting{
+		key: key,
+		get: func(c *Config) string { return *field(c) },
+		set: func(c *Config, v any) error {
+			s, ok := v.(string)
+			if !ok {
+				return fmt.Errorf("expected string, got %T", v)
+			}
+			*field(c) = s
+			return nil
+		},
+	}
+}
+
+func stringsSetting(key string, field func(*Config) *[]string) setting {
+	return setting{
+		key: key,
+		get: func(c *Config) string { return strings.Join(*field(c), ",") },
+		set: func(c *Config, v any) error {
+			list, err := toStrings(v)
+			if err != nil {
+				return err
+			}
+			*field(c) = list
+			return nil
+		},
+	}
+}
+
+func boolSetting(key string, field func(*Config) *bool) setting {
+	return setting{
+		key: key,
+		get: func(c *Config) string { return strconv.FormatBool(*field(c)) },
+		set: func(c *Config, v any) error {
+			switch b := v.(type) {
+			case bool:
+				*field(c) = b
+			case string:
+				parsed, err := strconv.ParseBool(strings.TrimSpace(b))
+				if err != nil {
+					return fmt.Errorf("expected boolean, got %q", b)
+				}
+				*field(c) = parsed
+			default:
+				return fmt.Errorf("expected boolean, got %T", v)
+			}
+			return nil
+		},
+	}
+}
+
+// strictSetting is a string setting that also accepts the booleans of
+// configs written before strict levels existed (see Config.StrictLevel).
+func strictSetting(key string, field func(*Config) *string) setting {
+	return setting{
+		key: key,
+		get: func(c *Config) string { return *field(c) },
+		set: func(c *Config, v any) error {
+			switch s := v.(type) {
+			case bool:
+				*field(c) = strconv.FormatBool(s)
+			case string:
+				*field(c) = strings.TrimSpace(s)
+			default:
+				return fmt.Errorf("expected string or boolean, got %T", v)
+			}
+			return nil
+		},
+	}
+}
+
+func int64Setting(key string, field func(*Config) *int64) setting {
+	return setting{
+		key: key,
+		get: func(c *Config) string { return strconv.FormatInt(*field(c), 10) },
+		set: func(c *Config, v any) error {
+			n, err := toInt64(v)
+			if err != nil {
+				return err
+			}
+			*field(c) = n
+			return nil
+		},
+	}
+}
+
+func intSetting(key string, field func(*Config) *int) setting {
+	return setting{
+		key: key,
+		get: func(c *Config) string { return strconv.Itoa(*field(c)) },
+		set: func(c *Config, v any) error {
+			n, err := toInt64(v)
+			if err != nil {
+				return err
+			}
+			*field(c) = int(n)
+			return nil
+		},
+	}
+}
+
+// durationSetting is a duration written as a Go duration string (e.g.,
+// "10s", "1m30s"). Plain numbers are seconds.
+func durationSetting(key string, field func(*Config) *time.Duration) setting {
+	return setting{
+		key: key,
+		get: func(c *Config) string { return field(c).String() },
+		set: func(c *Config, v any) error {
+			if s, ok := v.(string); ok {
+				d, err := time.ParseDuration(strings.TrimSpace(s))
+				if err != nil {
+					return fmt.Errorf("expected duration (e.g., \"10s\"), got %q", s)
+				}
+				*field(c) = d
+				return nil
+			}
+			n, err := toInt64(v)
+			if err != nil {
+				return fmt.Errorf("expected duration (e.g., \"10s\"), got %T", v)
+			}
+			*field(c) = time.Duration(n) * time.Second
+			return nil
+		},
+	}
+}
+
+// stringMapSetting is a mapping of strings, written as a table or as
+// comma-separated key=value pairs (e.g., ".mjs=javascript,.pyi=python").
+func stringMapSetting(key string, field func(*Config) *map[string]string) setting {
+	return setting{
+		key: key,
+		get: func(c *Config) string {
+			m := *field(c)
+			pairs := make([]string, 0, len(m))
+			for _, k := range sortedKeys(m) {
+				pairs = append(pairs, k+"="+m[k])
+			}
+			return strings.Join(pairs, ",")
+		},
+		set: func(c *Config, v any) error {
+			m, err := toStringMap(v)
+			if err != nil {
+				return err
+			}
+			*field(c) = m
+			return nil
+		},
+	}
+}
+
+// toStringMap converts a decoded table (or comma-separated key=value
+// pairs) to map[string]string.
+func toStringMap(v any) (map[string]string, error) {
+	switch table := v.(type) {
+	case map[string]string:
+		return table, nil
+	case map[string]any:
+		out := make(map[string]string, len(table))
+		for k, item := range table {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("expected mapping of strings, got %T for %q", item, k)
+			}
+			out[k] = s
+		}
+		return out, nil
+	case string:
+		out := make(map[string]string)
+		for _, pair := range strings.Split(table, ",") {
+			if pair = strings.TrimSpace(pair); pair == "" {
+				continue
+			}
+			k, s, ok := strings.Cut(pair, "=")
+			if !ok {
+				return nil, fmt.Errorf("expected key=value, got %q", pair)
+			}
+			out[strings.TrimSpace(k)] = strings.TrimSpace(s)
+		}
+		return out, nil
+	default:
+		return nil, fmt.Errorf("expected mapping of strings, got %T", v)
+	}
+}
+
+// toStrings converts a decoded list (or comma-separated string) to []string.
+func toStrings(v any) ([]string, error) {
+	switch list := v.(type) {
+	case []string:
+		return list, nil
+	case []any:
+		out := make([]string, 0, len(list))
+		for _, item := range list {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("expected list of strings, got element %T", item)
+			}
+			out = append(out, s)
+		}
+		return out, nil
+	case string:
+		var out []string
+		for _, part := range strings.Split(list, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				out = append(out, part)
+			}
+		}
+		return out, nil
+	default:
+		return nil, fmt.Errorf("expected list of strings, got %T", v)
+	}
+}
+
+// toInt64 converts a decoded number (or numeric string) to int64.
+func toInt64(v any) (int64, error) {
+	switch n := v.(type) {
+	case int:
+		return int64(n), nil
+	case int64:
+		return n, nil
+	case uint64:
+		return int64(n), nil
+	case float64:
+		if n != float64(int64(n)) {
+			return 0, fmt.Errorf("expected integer, got %v", n)
+		}
+		return int64(n), nil
+	case string:
+		parsed, err := strconv.ParseInt(strings.TrimSpace(n), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("expected integer, got %q", n)
+		}
+		return parsed, nil
+	default:
+		return 0, fmt.Errorf("expected integer, got %T", v)
+	}
+}
@@ -0,0 +1,480 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadFileYAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".brfit.yaml")
+	writeFile(t, path, `
+format: md
+include-private: true
+max-doc-length: 120
+exclude:
+  - "**/*_test.go"
+  - "vendor/**"
+`)
+
+	layer, err := LoadFile(path, "project config")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cfg := DefaultConfig()
+	if _, err := cfg.Apply([]*Layer{layer}, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cfg.Format != "md" {
+		t.Errorf("expected format 'md', got %q", cfg.Format)
+	}
+	if !cfg.IncludePrivate {
+		t.Error("expected IncludePrivate to be true")
+	}
+	if cfg.MaxDocLength != 120 {
+		t.Errorf("expected MaxDocLength 120, got %d", cfg.MaxDocLength)
+	}
+	want := []string{"**/*_test.go", "vendor/**"}
+	if !reflect.DeepEqual(cfg.ExcludePatterns, want) {
+		t.Errorf("expected ExcludePatterns %v, got %v", want, cfg.ExcludePatterns)
+	}
+}
+
+func TestLoadFileStrict(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"true", StrictError},
+		{"false", StrictOff},
+		{"syntax", StrictSyntax},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), ".brfit.yaml")
+		writeFile(t, path, "strict: "+tt.value+"\n")
+		layer, err := LoadFile(path, "project config")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		cfg := DefaultConfig()
+		if _, err := cfg.Apply([]*Layer{layer}, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := cfg.StrictLevel(); got != tt.want {
+			t.Errorf("strict: %s: expected level %q, got %q", tt.value, tt.want, got)
+		}
+	}
+}
+
+func TestLoadFileParseTimeout(t *testing.T) {
+	tests := []struct {
+		content string
+		want    time.Duration
+		wantErr bool
+	}{
+		{content: "parse-timeout: 2m30s\n", want: 150 * time.Second},
+		{content: "parse-timeout: 5\n", want: 5 * time.Second},
+		{content: "parse-timeout: soon\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), ".brfit.yaml")
+		writeFile(t, path, tt.content)
+		layer, err := LoadFile(path, "project config")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		cfg := DefaultConfig()
+		_, err = cfg.Apply([]*Layer{layer}, nil)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%q: expected error", tt.content)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.ParseTimeout != tt.want {
+			t.Errorf("%q: expected parse timeout %v, got %v", tt.content, tt.want, cfg.ParseTimeout)
+		}
+	}
+}
+
+func TestLoadFileExtensions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".brfit.yaml")
+	writeFile(t, path, "extensions:\n  .mjs: javascript\n  .cc: cpp\n")
+	layer, err := LoadFile(path, "project config")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cfg := DefaultConfig()
+	if _, err := cfg.Apply([]*Layer{layer}, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]string{".mjs": "javascript", ".cc": "cpp"}
+	if !reflect.DeepEqual(cfg.Extensions, want) {
+		t.Errorf("expected extensions %v, got %v", want, cfg.Extensions)
+	}
+	if got := cfg.Get("extensions"); got != ".cc=cpp,.mjs=javascript" {
+		t.Errorf("expected extensions displayed as %q, got %q", ".cc=cpp,.mjs=javascript", got)
+	}
+
+	// Environment variables use comma-separated key=value pairs
+	cfg = DefaultConfig()
+	if _, err := cfg.Apply(EnvLayers([]string{"BRFIT_EXTENSIONS=.pyi=python, .hh=cpp"}), nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want = map[string]string{".pyi": "python", ".hh": "cpp"}
+	if !reflect.DeepEqual(cfg.Extensions, want) {
+		t.Errorf("expected extensions %v, got %v", want, cfg.Extensions)
+	}
+
+	cfg = DefaultConfig()
+	if _, err := cfg.Apply(EnvLayers([]string{"BRFIT_EXTENSIONS=.pyi"}), nil); err == nil {
+		t.Error("expected error for a pair without '='")
+	}
+}
+
+func TestLoadFileTOML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".brfit.toml")
+	writeFile(t, path, `
+format = "json"
+call-graph = true
+max-size = 1048576
+include = ["pkg/**/*.go"]
+`)
+
+	layer, err := LoadFile(path, "project config")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cfg := DefaultConfig()
+	if _, err := cfg.Apply([]*Layer{layer}, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cfg.Format != "json" {
+		t.Errorf("expected format 'json', got %q", cfg.Format)
+	}
+	if !cfg.CallGraph {
+		t.Error("expected CallGraph to be true")
+	}
+	if cfg.MaxFileSize != 1048576 {
+		t.Errorf("expected MaxFileSize 1048576, got %d", cfg.MaxFileSize)
+	}
+	if !reflect.DeepEqual(cfg.IncludePatterns, []string{"pkg/**/*.go"}) {
+		t.Errorf("unexpected IncludePatterns: %v", cfg.IncludePatterns)
+	}
+}
+
+func TestLoadFileErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		errPart string
+	}{
+		{"unsupported extension", "brfit.json", `{}`, "unsupported config file"},
+		{"invalid yaml", ".brfit.yaml", "format: [unclosed", "failed to parse"},
+		{"unknown key", ".brfit.yml", "colour: blue\n", `unknown key "colour"`},
+		{"wrong type", "wrong.yaml", "include-body: sometimes\n", "expected boolean"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.file)
+			writeFile(t, path, tt.content)
+
+			layer, err := LoadFile(path, "project config")
+			if err == nil {
+				_, err = DefaultConfig().Apply([]*Layer{layer}, nil)
+			}
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.errPart) {
+				t.Errorf("expected error containing %q, got: %v", tt.errPart, err)
+			}
+		})
+	}
+}
+
+func TestEnvLayers(t *testing.T) {
+	layers := EnvLayers([]string{
+		"HOME=/root",
+		"BRFIT_FORMAT=md",
+		"BRFIT_EXCLUDE=vendor/**, dist/**",
+		"BRFIT_NO_TOKENS=1",
+		"BRFIT_UNKNOWN=ignored",
+	})
+	if len(layers) != 3 {
+		t.Fatalf("expected 3 env layers, got %d", len(layers))
+	}
+
+	cfg := DefaultConfig()
+	sources, err := cfg.Apply(layers, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cfg.Format != "md" {
+		t.Errorf("expected format 'md', got %q", cfg.Format)
+	}
+	if !cfg.NoTokens {
+		t.Error("expected NoTokens to be true")
+	}
+	if !reflect.DeepEqual(cfg.ExcludePatterns, []string{"vendor/**", "dist/**"}) {
+		t.Errorf("unexpected ExcludePatterns: %v", cfg.ExcludePatterns)
+	}
+	if sources["format"] != "env BRFIT_FORMAT" {
+		t.Errorf("expected source 'env BRFIT_FORMAT', got %q", sources["format"])
+	}
+}
+
+func TestLoadLayersPrecedence(t *testing.T) {
+	root := t.TempDir()
+	userDir := filepath.Join(root, "home")
+	repo := filepath.Join(root, "repo")
+	sub := filepath.Join(repo, "pkg", "sub")
+	if err := os.MkdirAll(filepath.Join(repo, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	writeFile(t, filepath.Join(userDir, "brfit", "config.yaml"), "format: json\ninclude-body: true\nmax-doc-length: 80\n")
+	writeFile(t, filepath.Join(repo, ".brfit.yaml"), "format: md\ncall-graph: true\n")
+
+	layers, err := LoadLayers(sub, &LoadOptions{
+		UserConfigDir: userDir,
+		Environ:       []string{"BRFIT_MAX_DOC_LENGTH=40"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(layers) != 3 {
+		t.Fatalf("expected 3 layers (user, project, env), got %d", len(layers))
+	}
+
+	cfg := DefaultConfig()
+	// Simulate --include-body=false given on the command line.
+	cfg.IncludeBody = false
+	sources, err := cfg.Apply(layers, func(key string) bool { return key == "include-body" })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cfg.Format != "md" {
+		t.Errorf("project config should override user config: got format %q", cfg.Format)
+	}
+	if !cfg.CallGraph {
+		t.Error("expected CallGraph from project config")
+	}
+	if cfg.MaxDocLength != 40 {
+		t.Errorf("env should override user config: got MaxDocLength %d", cfg.MaxDocLength)
+	}
+	if cfg.IncludeBody {
+		t.Error("explicit flag should override user config")
+	}
+
+	if !strings.HasPrefix(sources["format"], "project config ") {
+		t.Errorf("unexpected source for format: %q", sources["format"])
+	}
+	if sources["include-body"] != "flag --include-body" {
+		t.Errorf("unexpected source for include-body: %q", sources["include-body"])
+	}
+	if sources["mode"] != SourceDefault {
+		t.Errorf("unexpected source for mode: %q", sources["mode"])
+	}
+}
+
+func TestLoadLayersSkipProject(t *testing.T) {
+	repo := t.TempDir()
+	writeFile(t, filepath.Join(repo, ".brfit.yaml"), "output: /tmp/owned.xml\n")
+
+	layers, err := LoadLayers(repo, &LoadOptions{
+		SkipProject:   true,
+		UserConfigDir: filepath.Join(repo, "no-such-dir"),
+		Environ:       []string{},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(layers) != 0 {
+		t.Errorf("expected no layers with SkipProject, got %d", len(layers))
+	}
+}
+
+func TestFindProjectFileStopsAtRepoRoot(t *testing.T) {
+	outer := t.TempDir()
+	repo := filepath.Join(outer, "repo")
+	if err := os.MkdirAll(filepath.Join(repo, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	// A config file above the repository root must not be picked up.
+	writeFile(t, filepath.Join(outer, ".brfit.yaml"), "format: md\n")
+
+	if got := FindProjectFile(repo); got != "" {
+		t.Errorf("expected no project file, got %q", got)
+	}
+
+	writeFile(t, filepath.Join(repo, ".brfit.toml"), "format = \"md\"\n")
+	if got := FindProjectFile(filepath.Join(repo, ".git")); got != filepath.Join(repo, ".brfit.toml") {
+		t.Errorf("expected repo config file, got %q", got)
+	}
+}
+
+func TestFindQueryDir(t *testing.T) {
+	repo := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(repo, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	sub := filepath.Join(repo, "pkg", "api")
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if got := FindQueryDir(sub); got != "" {
+		t.Errorf("expected no query directory, got %q", got)
+	}
+
+	queries := filepath.Join(repo, QueryDirName)
+	if err := os.MkdirAll(queries, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if got := FindQueryDir(sub); got != queries {
+		t.Errorf("expected %q, got %q", queries, got)
+	}
+
+	// An explicit directory wins, and a remote repository's own is ignored
+	cfg := &Config{Path: sub, QueryDir: "custom"}
+	if got := cfg.QueryDirectory(); got != "custom" {
+		t.Errorf("expected explicit query directory, got %q", got)
+	}
+	cfg = &Config{Path: sub, Remote: "owner/repo"}
+	if got := cfg.QueryDirectory(); got != "" {
+		t.Errorf("expected no query directory for a remote repository, got %q", got)
+	}
+}
+
+func TestLoadLayersProfile(t *testing.T) {
+	root := t.TempDir()
+	userDir := filepath.Join(root, "home")
+	repo := filepath.Join(root, "repo")
+
+	writeFile(t, filepath.Join(userDir, "brfit", "config.yaml"), `
+format: json
+profiles:
+  review:
+    include-private: true
+`)
+	writeFile(t, filepath.Join(repo, ".brfit.yaml"), `
+format: md
+exclude: ["vendor/**"]
+profiles:
+  review:
+    include-body: true
+    exclude: ["vendor/**", "**/*_test.go"]
+  api-only:
+    include-private: false
+`)
+
+	layers, err := LoadLayers(repo, &LoadOptions{
+		UserConfigDir: userDir,
+		Environ:       []string{},
+		Profile:       "review",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cfg := DefaultConfig()
+	sources, err := cfg.Apply(layers, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cfg.Format != "md" {
+		t.Errorf("expected format 'md', got %q", cfg.Format)
+	}
+	if !cfg.IncludeBody {
+		t.Error("expected IncludeBody from project profile")
+	}
+	if !cfg.IncludePrivate {
+		t.Error("expected IncludePrivate from user profile")
+	}
+	if !reflect.DeepEqual(cfg.ExcludePatterns, []string{"vendor/**", "**/*_test.go"}) {
+		t.Errorf("expected profile to override ExcludePatterns, got %v", cfg.ExcludePatterns)
+	}
+	if !strings.HasSuffix(sources["include-body"], "(profile review)") {
+		t.Errorf("unexpected source for include-body: %q", sources["include-body"])
+	}
+}
+
+func TestLoadLayersProfileFromEnv(t *testing.T) {
+	repo := t.TempDir()
+	writeFile(t, filepath.Join(repo, ".brfit.toml"), `
+[profiles.onboarding]
+include-imports = true
+`)
+
+	layers, err := LoadLayers(repo, &LoadOptions{
+		UserConfigDir: filepath.Join(repo, "no-such-dir"),
+		Environ:       []string{"BRFIT_PROFILE=onboarding"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cfg := DefaultConfig()
+	if _, err := cfg.Apply(layers, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.IncludeImports {
+		t.Error("expected IncludeImports from BRFIT_PROFILE profile")
+	}
+}
+
+func TestLoadLayersUnknownProfile(t *testing.T) {
+	repo := t.TempDir()
+	writeFile(t, filepath.Join(repo, ".brfit.yaml"), "profiles:\n  review:\n    include-body: true\n")
+
+	_, err := LoadLayers(repo, &LoadOptions{
+		UserConfigDir: filepath.Join(repo, "no-such-dir"),
+		Environ:       []string{},
+		Profile:       "nope",
+	})
+	if err == nil {
+		t.Fatal("expected error for unknown profile")
+	}
+	if !strings.Contains(err.Error(), `profile "nope" not found`) || !strings.Contains(err.Error(), "review") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestLoadFileInvalidProfiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".brfit.yaml")
+	writeFile(t, path, "profiles:\n  review: true\n")
+
+	if _, err := LoadFile(path, "project config"); err == nil || !strings.Contains(err.Error(), `profile "review"`) {
+		t.Errorf("expected invalid profile error, got: %v", err)
+	}
+}
@@ -0,0 +1,197 @@
+package context
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/indigo-net/Brf.it/pkg/extractor"
+	"github.com/indigo-net/Brf.it/pkg/formatter"
+	"github.com/indigo-net/Brf.it/pkg/parser"
+)
+
+// AllowEntry is a single allowlist line permitting an intentional API break.
+type AllowEntry struct {
+	// Path is a doublestar glob matched against the file path.
+	Path string
+
+	// Name is the symbol name, or "*" for any symbol.
+	Name string
+
+	// Kind is the signature kind, or "*" (default) for any kind.
+	Kind string
+
+	// Line is the line number in the allowlist file.
+	Line int
+}
+
+// String returns the entry in allowlist syntax.
+func (e AllowEntry) String() string {
+	if e.Kind == "*" {
+		return e.Path + " " + e.Name
+	}
+	return e.Path + " " + e.Name + " " + e.Kind
+}
+
+// Allowlist records intentional breaking changes that api-check should accept.
+//
+// Each non-empty line has the form "<path-glob> <name> [kind]"; text after
+// "#" is a comment. For example:
+//
+//	pkg/api.go       Less             # generic since v2
+//	internal/**      *
+//	src/client.ts    Client  class
+type Allowlist struct {
+	entries []AllowEntry
+	used    []bool
+}
+
+// LoadAllowlist reads an allowlist file.
+func LoadAllowlist(path string) (*Allowlist, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open allowlist: %w", err)
+	}
+	defer f.Close()
+
+	a, err := ParseAllowlist(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return a, nil
+}
+
+// ParseAllowlist parses allowlist entries from r.
+func ParseAllowlist(r io.Reader) (*Allowlist, error) {
+	a := &Allowlist{}
+	sc := bufio.NewScanner(r)
+	lineNo := 0
+	for sc.Scan() {
+		lineNo++
+		line := sc.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) < 2 || len(fields) > 3 {
+			return nil, fmt.Errorf("line %d: expected \"<path-glob> <name> [kind]\", got %q", lineNo, strings.TrimSpace(line))
+		}
+		if !doublestar.ValidatePattern(fields[0]) {
+			return nil, fmt.Errorf("line %d: invalid path pattern %q", lineNo, fields[0])
+		}
+		entry := AllowEntry{Path: fields[0], Name: fields[1], Kind: "*", Line: lineNo}
+		if len(fields) == 3 {
+			entry.Kind = fields[2]
+		}
+		a.entries = append(a.entries, entry)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	a.used = make([]bool, len(a.entries))
+	return a, nil
+}
+
+// Allows reports whether the change in the file at path is allowlisted,
+// marking every matching entry as used. A nil Allowlist allows nothing.
+func (a *Allowlist) Allows(path string, ch formatter.SignatureChange) bool {
+	if a == nil {
+		return false
+	}
+	allowed := false
+	for i, e := range a.entries {
+		if e.Name != "*" && e.Name != ch.Name {
+			continue
+		}
+		if e.Kind != "*" && e.Kind != ch.Kind {
+			continue
+		}
+		// Patterns are validated in ParseAllowlist; error is unreachable
+		if matched, _ := doublestar.Match(e.Path, path); !matched {
+			continue
+		}
+		a.used[i] = true
+		allowed = true
+	}
+	return allowed
+}
+
+// Unused returns the entries that did not match any change, in file order.
+// Stale entries would otherwise silently permit future breaks.
+func (a *Allowlist) Unused() []AllowEntry {
+	if a == nil {
+		return nil
+	}
+	var unused []AllowEntry
+	for i, e := range a.entries {
+		if !a.used[i] {
+			unused = append(unused, e)
+		}
+	}
+	return unused
+}
+
+// BreakingChange is a change to an exported symbol.
+type BreakingChange struct {
+	// Path is the file path relative to the compared root.
+	Path string
+
+	// Change is the signature change. Its Old signature is always exported.
+	Change formatter.SignatureChange
+
+	// Allowed indicates the change matched an allowlist entry.
+	Allowed bool
+}
+
+// FindBreakingChanges returns removed or changed signatures that were
+// exported in the old revision. Added signatures never break callers.
+func FindBreakingChanges(diffs []formatter.FileDiff, allow *Allowlist) []BreakingChange {
+	var breaking []BreakingChange
+	for _, fd := range diffs {
+		for _, ch := range fd.Changes {
+			if ch.Type == formatter.ChangeAdded || ch.Old == nil || !ch.Old.Exported {
+				continue
+			}
+			breaking = append(breaking, BreakingChange{
+				Path:    fd.Path,
+				Change:  ch,
+				Allowed: allow.Allows(fd.Path, ch),
+			})
+		}
+	}
+	return breaking
+}
+
+// FindUncheckedFiles returns the files that had exported signatures in
+// oldSnap but failed to extract in newSnap. DiffSnapshots skips such files,
+// so their removals and changes cannot be detected. Files with syntax
+// errors are still extracted and are not reported.
+func FindUncheckedFiles(oldSnap, newSnap *Snapshot) []extractor.ErrorDetail {
+	var unchecked []extractor.ErrorDetail
+	for path, oldFile := range oldSnap.Files {
+		newFile, ok := newSnap.Files[path]
+		if !ok || oldFile.Error != nil || newFile.Error == nil || !hasExported(oldFile.Signatures) {
+			continue
+		}
+		unchecked = append(unchecked, extractor.ErrorDetail{Path: path, Err: newFile.Error})
+	}
+	sort.Slice(unchecked, func(i, j int) bool { return unchecked[i].Path < unchecked[j].Path })
+	return unchecked
+}
+
+// hasExported reports whether any of sigs is exported.
+func hasExported(sigs []parser.Signature) bool {
+	for _, sig := range sigs {
+		if sig.Exported {
+			return true
+		}
+	}
+	return false
+}
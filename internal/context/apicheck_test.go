@@ -0,0 +1,137 @@
+package context
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/indigo-net/Brf.it/pkg/extractor"
+	"github.com/indigo-net/Brf.it/pkg/formatter"
+	"github.com/indigo-net/Brf.it/pkg/parser"
+)
+
+func TestParseAllowlist(t *testing.T) {
+	a, err := ParseAllowlist(strings.NewReader(`
+# intentional breaks for v2
+pkg/api.go   Less            # generic now
+internal/**  *
+src/*.ts     Client  class
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		path string
+		name string
+		kind string
+		want bool
+	}{
+		{"pkg/api.go", "Less", "function", true},
+		{"pkg/api.go", "More", "function", false},
+		{"internal/x/y.go", "Anything", "type", true},
+		{"src/client.ts", "Client", "class", true},
+		{"src/client.ts", "Client", "interface", false},
+		{"src/sub/client.ts", "Client", "class", false},
+	}
+	for _, tt := range tests {
+		got := a.Allows(tt.path, formatter.SignatureChange{Name: tt.name, Kind: tt.kind})
+		if got != tt.want {
+			t.Errorf("Allows(%q, %q, %q) = %v, want %v", tt.path, tt.name, tt.kind, got, tt.want)
+		}
+	}
+
+	if unused := a.Unused(); len(unused) != 0 {
+		t.Errorf("expected all entries used, got %v", unused)
+	}
+}
+
+func TestParseAllowlistErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		errPart string
+	}{
+		{"missing name", "pkg/api.go\n", "line 1"},
+		{"too many fields", "\npkg/api.go Less function extra\n", "line 2"},
+		{"invalid glob", "pkg/[api.go Less\n", "invalid path pattern"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseAllowlist(strings.NewReader(tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.errPart) {
+				t.Errorf("expected error containing %q, got: %v", tt.errPart, err)
+			}
+		})
+	}
+}
+
+func TestFindBreakingChanges(t *testing.T) {
+	diffs := []formatter.FileDiff{{
+		Path: "api.go",
+		Changes: []formatter.SignatureChange{
+			{Type: formatter.ChangeAdded, Name: "New", New: &parser.Signature{Exported: true}},
+			{Type: formatter.ChangeRemoved, Name: "Gone", Kind: "function", Old: &parser.Signature{Exported: true}},
+			{Type: formatter.ChangeRemoved, Name: "gone", Kind: "function", Old: &parser.Signature{Exported: false}},
+			{Type: formatter.ChangeChanged, Name: "Hidden", Kind: "function", Old: &parser.Signature{Exported: true}, New: &parser.Signature{Exported: false}},
+		},
+	}}
+	allow, err := ParseAllowlist(strings.NewReader("api.go Hidden\nother.go *\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	breaking := FindBreakingChanges(diffs, allow)
+	if len(breaking) != 2 {
+		t.Fatalf("expected 2 breaking changes, got %d: %+v", len(breaking), breaking)
+	}
+	if breaking[0].Change.Name != "Gone" || breaking[0].Allowed {
+		t.Errorf("unexpected first change: %+v", breaking[0])
+	}
+	if breaking[1].Change.Name != "Hidden" || !breaking[1].Allowed {
+		t.Errorf("unexpected second change: %+v", breaking[1])
+	}
+
+	unused := allow.Unused()
+	if len(unused) != 1 || unused[0].String() != "other.go *" || unused[0].Line != 2 {
+		t.Errorf("unexpected unused entries: %+v", unused)
+	}
+
+	if got := FindBreakingChanges(diffs, nil); len(got) != 2 || got[1].Allowed {
+		t.Errorf("nil allowlist should allow nothing: %+v", got)
+	}
+}
+
+func TestFindUncheckedFiles(t *testing.T) {
+	exported := []parser.Signature{{Name: "A", Kind: "function", Exported: true}}
+	private := []parser.Signature{{Name: "a", Kind: "function"}}
+	failed := errors.New("parse failed")
+
+	oldSnap := &Snapshot{Files: map[string]extractor.ExtractedFile{
+		"b.go":       {Path: "b.go", Signatures: exported},
+		"a.go":       {Path: "a.go", Signatures: exported},
+		"private.go": {Path: "private.go", Signatures: private},
+		"ok.go":      {Path: "ok.go", Signatures: exported},
+		"was.go":     {Path: "was.go", Error: failed},
+		"gone.go":    {Path: "gone.go", Signatures: exported},
+	}}
+	newSnap := &Snapshot{Files: map[string]extractor.ExtractedFile{
+		"b.go":       {Path: "b.go", Error: failed},
+		"a.go":       {Path: "a.go", Error: failed},
+		"private.go": {Path: "private.go", Error: failed},
+		"ok.go":      {Path: "ok.go", Signatures: exported},
+		"was.go":     {Path: "was.go", Error: failed},
+	}}
+
+	unchecked := FindUncheckedFiles(oldSnap, newSnap)
+	var paths []string
+	for _, ef := range unchecked {
+		paths = append(paths, ef.Path)
+		if ef.Err != failed {
+			t.Errorf("expected %s to carry the extraction error, got %v", ef.Path, ef.Err)
+		}
+	}
+	if strings.Join(paths, ",") != "a.go,b.go" {
+		t.Errorf("unexpected unchecked files: %v", paths)
+	}
+}
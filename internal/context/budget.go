@@ -0,0 +1,308 @@
+package context
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/indigo-net/Brf.it/pkg/formatter"
+	"github.com/indigo-net/Brf.it/pkg/parser"
+)
+
+// BudgetDocLength is the documentation length (in characters) above which
+// doc comments are dropped first when output exceeds the token budget.
+const BudgetDocLength = 200
+
+// TrimReport describes what was dropped to fit the output into a token budget.
+type TrimReport struct {
+	// Budget is the requested maximum number of tokens.
+	Budget int
+
+	// OriginalTokens is the token count before trimming.
+	OriginalTokens int
+
+	// DocsDropped is the number of doc comments longer than BudgetDocLength that were dropped.
+	DocsDropped int
+
+	// PrivateDropped is the number of non-exported signatures that were dropped.
+	PrivateDropped int
+
+	// FilesDropped lists the files removed from the output, in drop order.
+	FilesDropped []string
+
+	// Fits reports whether the trimmed output is within the budget.
+	// False means even the output without any files exceeds it.
+	Fits bool
+}
+
+// fitBudget degrades data until its formatted output fits opts.MaxTokens.
+// Each step is applied only if the previous one was not enough:
+//  1. drop doc comments longer than BudgetDocLength
+//  2. drop private (non-exported) signatures
+//  3. drop files, lowest priority first (see filePriority)
+func (p *Packager) fitBudget(data *formatter.PackageData, f formatter.Formatter, opts *Options, tokens int) ([]byte, int, *TrimReport, error) {
+	report := &TrimReport{Budget: opts.MaxTokens, OriginalTokens: tokens}
+
+	// Work on copies so the caller's extraction results stay untouched.
+	files := make([]formatter.FileData, len(data.Files))
+	copy(files, data.Files)
+	for i := range files {
+		files[i].Signatures = append([]parser.Signature(nil), files[i].Signatures...)
+	}
+	data.Files = files
+
+	render := func() ([]byte, int, error) {
+		content, err := f.Format(data)
+		if err != nil {
+			return nil, 0, err
+		}
+		count, err := p.tokenizer.Count(content)
+		if err != nil {
+			return nil, 0, fmt.Errorf("token count failed: %w", err)
+		}
+		return content, count, nil
+	}
+
+	// 1. Drop long doc comments
+	for i := range files {
+		for j := range files[i].Signatures {
+			// Docs already shortened below the threshold by DocStyle or
+			// MaxDocLength are kept.
+			n := utf8.RuneCountInString(formatter.FormatDoc(files[i].Signatures[j].Doc, data.DocStyle))
+			if data.MaxDocLength > 0 && n > data.MaxDocLength {
+				n = data.MaxDocLength
+			}
+			if n > BudgetDocLength {
+				files[i].Signatures[j].Doc = ""
+				report.DocsDropped++
+			}
+		}
+	}
+	if report.DocsDropped > 0 {
+		content, count, err := render()
+		if err != nil {
+			return nil, 0, nil, err
+		}
+		if count <= opts.MaxTokens {
+			report.Fits = true
+			return content, count, report, nil
+		}
+	}
+
+	// 2. Drop private symbols
+	for i := range files {
+		kept := files[i].Signatures[:0]
+		for _, sig := range files[i].Signatures {
+			if sig.Exported {
+				kept = append(kept, sig)
+			} else {
+				report.PrivateDropped++
+			}
+		}
+		files[i].Signatures = kept
+	}
+	data.TotalSignatures -= report.PrivateDropped
+	content, count, err := render()
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	if count <= opts.MaxTokens {
+		report.Fits = true
+		return content, count, report, nil
+	}
+
+	// 3. Drop low-priority files. Per-file costs are estimated by formatting
+	// each file alone, so most files can be dropped without re-rendering;
+	// the full output is then re-checked and trimming continues if needed.
+	costs, err := p.fileCosts(data, f)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	order := dropOrder(files, costs, opts.Path)
+	dropped := make(map[int]bool, len(order))
+	excess := count - opts.MaxTokens
+	for _, idx := range order {
+		dropped[idx] = true
+		report.FilesDropped = append(report.FilesDropped, relativeTo(opts.Path, files[idx].Path))
+		data.TotalSignatures -= len(files[idx].Signatures)
+		excess -= costs[idx]
+		if excess > 0 {
+			continue
+		}
+
+		applyDrops(data, files, dropped, opts)
+		content, count, err = render()
+		if err != nil {
+			return nil, 0, nil, err
+		}
+		if count <= opts.MaxTokens {
+			report.Fits = true
+			return content, count, report, nil
+		}
+		excess = count - opts.MaxTokens
+	}
+
+	applyDrops(data, files, dropped, opts)
+	content, count, err = render()
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	report.Fits = count <= opts.MaxTokens
+	return content, count, report, nil
+}
+
+// applyDrops rebuilds data.Files, the tree and global imports without the dropped files.
+func applyDrops(data *formatter.PackageData, files []formatter.FileData, dropped map[int]bool, opts *Options) {
+	kept := make([]formatter.FileData, 0, len(files)-len(dropped))
+	for i, fd := range files {
+		if !dropped[i] {
+			kept = append(kept, fd)
+		}
+	}
+	data.Files = kept
+
+	if data.Tree != "" {
+		paths := make([]string, len(kept))
+		for i, fd := range kept {
+			paths[i] = fd.Path
+		}
+		data.Tree = BuildTree(opts.Path, paths)
+	}
+	if data.DedupeImports {
+		data.GlobalImports = buildGlobalImports(kept)
+	}
+	if len(data.Packages) > 0 {
+		data.Packages = buildPackageSummaries(kept)
+	}
+}
+
+// fileCosts estimates the number of tokens each file contributes to the output.
+func (p *Packager) fileCosts(data *formatter.PackageData, f formatter.Formatter) ([]int, error) {
+	single := *data
+	single.RootPath, single.Version, single.Tree = "", "", ""
+	single.GlobalImports = nil
+	single.Packages = nil
+	single.NoSchema = true
+
+	single.Files = nil
+	emptyContent, err := f.Format(&single)
+	if err != nil {
+		return nil, err
+	}
+	overhead, err := p.tokenizer.Count(emptyContent)
+	if err != nil {
+		return nil, fmt.Errorf("token count failed: %w", err)
+	}
+
+	costs := make([]int, len(data.Files))
+	for i := range data.Files {
+		single.Files = data.Files[i : i+1]
+		content, err := f.Format(&single)
+		if err != nil {
+			return nil, err
+		}
+		count, err := p.tokenizer.Count(content)
+		if err != nil {
+			return nil, fmt.Errorf("token count failed: %w", err)
+		}
+		// Every file costs at least one token so trimming always progresses.
+		costs[i] = max(count-overhead, 1)
+	}
+	return costs, nil
+}
+
+// dropOrder returns file indexes in the order they should be dropped:
+// lowest priority first, then fewest exported signatures, then largest cost.
+func dropOrder(files []formatter.FileData, costs []int, root string) []int {
+	order := make([]int, len(files))
+	prio := make([]int, len(files))
+	exported := make([]int, len(files))
+	for i, fd := range files {
+		order[i] = i
+		prio[i] = filePriority(relativeTo(root, fd.Path), fd)
+		for _, sig := range fd.Signatures {
+			if sig.Exported {
+				exported[i]++
+			}
+		}
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		i, j := order[a], order[b]
+		if prio[i] != prio[j] {
+			return prio[i] < prio[j]
+		}
+		if exported[i] != exported[j] {
+			return exported[i] < exported[j]
+		}
+		return costs[i] > costs[j]
+	})
+	return order
+}
+
+// lowPriorityDirs are directory names whose files are dropped before
+// regular sources when trimming to a token budget.
+var lowPriorityDirs = map[string]bool{
+	"examples": true, "example": true, "docs": true, "testdata": true,
+	"fixtures": true, "mocks": true, "vendor": true, "third_party": true,
+	"node_modules": true, "generated": true,
+}
+
+// relativeTo returns p relative to root using forward slashes, or p itself
+// when it is not below root.
+func relativeTo(root, p string) string {
+	if root == "" {
+		return filepath.ToSlash(p)
+	}
+	rel, err := filepath.Rel(root, p)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(p)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// filePriority ranks a file for budget trimming; lower values are dropped first.
+// rel is the file path relative to the packaged root.
+//   - 0: files that failed to parse and test files
+//   - 1: examples, fixtures, vendored and generated code
+//   - 2: files without signatures (imports only)
+//   - 3: everything else
+func filePriority(rel string, fd formatter.FileData) int {
+	p := strings.ToLower(rel)
+	base := path.Base(p)
+
+	if fd.Error != nil || isTestFile(p, base) {
+		return 0
+	}
+	for _, dir := range strings.Split(path.Dir(p), "/") {
+		if lowPriorityDirs[dir] {
+			return 1
+		}
+	}
+	if strings.HasSuffix(base, ".pb.go") || strings.Contains(base, "_gen.") ||
+		strings.Contains(base, ".generated.") || strings.HasSuffix(base, ".d.ts") {
+		return 1
+	}
+	if len(fd.Signatures) == 0 {
+		return 2
+	}
+	return 3
+}
+
+// isTestFile reports whether a path looks like a test file in common conventions.
+func isTestFile(p, base string) bool {
+	if strings.HasSuffix(base, "_test.go") || strings.HasPrefix(base, "test_") ||
+		strings.Contains(base, ".test.") || strings.Contains(base, ".spec.") ||
+		strings.HasSuffix(strings.TrimSuffix(base, path.Ext(base)), "_test") ||
+		strings.HasSuffix(strings.TrimSuffix(base, path.Ext(base)), "_spec") {
+		return true
+	}
+	for _, dir := range strings.Split(path.Dir(p), "/") {
+		if dir == "test" || dir == "tests" || dir == "__tests__" || dir == "spec" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,184 @@
+package context
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/indigo-net/Brf.it/pkg/extractor"
+	"github.com/indigo-net/Brf.it/pkg/formatter"
+	"github.com/indigo-net/Brf.it/pkg/parser"
+	"github.com/indigo-net/Brf.it/pkg/scanner"
+)
+
+// wordTokenizer counts whitespace-separated words as tokens.
+type wordTokenizer struct{}
+
+func (wordTokenizer) Count(text []byte) (int, error) { return len(bytes.Fields(text)), nil }
+func (wordTokenizer) Name() string                   { return "words" }
+
+// newBudgetPackager returns a Markdown packager over the given files using wordTokenizer.
+func newBudgetPackager(files []extractor.ExtractedFile) *Packager {
+	entries := make([]scanner.FileEntry, len(files))
+	total := 0
+	for i, f := range files {
+		entries[i] = scanner.FileEntry{Path: f.Path, Language: f.Language}
+		total += len(f.Signatures)
+	}
+	p := NewPackager(
+		&mockScanner{result: &scanner.ScanResult{Files: entries}},
+		&mockExtractor{result: &extractor.ExtractResult{Files: files, TotalSignatures: total}},
+		map[string]formatter.Formatter{"markdown": formatter.NewMarkdownFormatter()},
+	)
+	p.SetTokenizer(wordTokenizer{})
+	return p
+}
+
+func budgetFiles() []extractor.ExtractedFile {
+	longDoc := strings.Repeat("very long documentation ", 20)
+	return []extractor.ExtractedFile{
+		{Path: "/repo/core.go", Language: "go", Signatures: []parser.Signature{
+			{Name: "Run", Kind: "function", Text: "func Run(ctx context.Context) error", Doc: longDoc, Exported: true},
+			{Name: "helper", Kind: "function", Text: "func helper(a, b, c, d, e, f int) int", Exported: false},
+		}},
+		{Path: "/repo/core_test.go", Language: "go", Signatures: []parser.Signature{
+			{Name: "TestRun", Kind: "function", Text: "func TestRun(t *testing.T)", Exported: true},
+		}},
+		{Path: "/repo/examples/demo.go", Language: "go", Signatures: []parser.Signature{
+			{Name: "Demo", Kind: "function", Text: "func Demo()", Exported: true},
+		}},
+	}
+}
+
+func TestPackageMaxTokens(t *testing.T) {
+	// Measure each stage so the budgets below exercise one trimming step each.
+	measure := func(files []extractor.ExtractedFile) int {
+		res, err := newBudgetPackager(files).Package(context.Background(), &Options{Format: "md", Path: "/repo"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res.TokenCount
+	}
+	full := measure(budgetFiles())
+	noDocs := budgetFiles()
+	noDocs[0].Signatures[0].Doc = ""
+	withoutDocs := measure(noDocs)
+	noPrivate := budgetFiles()[1:]
+	noPrivate = append([]extractor.ExtractedFile{{Path: "/repo/core.go", Language: "go", Signatures: noDocs[0].Signatures[:1]}}, noPrivate...)
+	withoutPrivate := measure(noPrivate)
+
+	tests := []struct {
+		name        string
+		budget      int
+		wantDocs    int
+		wantPrivate int
+		wantFiles   []string
+	}{
+		{"within budget", full, 0, 0, nil},
+		{"drop docs", withoutDocs, 1, 0, nil},
+		{"drop private", withoutPrivate, 1, 1, nil},
+		{"drop test file first", withoutPrivate - 1, 1, 1, []string{"core_test.go"}},
+		{"drop examples next", withoutPrivate - 12, 1, 1, []string{"core_test.go", "examples/demo.go"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newBudgetPackager(budgetFiles())
+			res, err := p.Package(context.Background(), &Options{Format: "md", Path: "/repo", MaxTokens: tt.budget})
+			if err != nil {
+				t.Fatalf("Package failed: %v", err)
+			}
+			if res.TokenCount > tt.budget {
+				t.Errorf("TokenCount %d exceeds budget %d", res.TokenCount, tt.budget)
+			}
+			if tt.wantDocs == 0 && tt.wantPrivate == 0 && tt.wantFiles == nil {
+				if res.Trim != nil {
+					t.Errorf("expected no trimming, got %+v", res.Trim)
+				}
+				return
+			}
+			if res.Trim == nil || !res.Trim.Fits {
+				t.Fatalf("expected trimmed output that fits, got %+v", res.Trim)
+			}
+			if res.Trim.OriginalTokens != full {
+				t.Errorf("OriginalTokens = %d, want %d", res.Trim.OriginalTokens, full)
+			}
+			if res.Trim.DocsDropped != tt.wantDocs || res.Trim.PrivateDropped != tt.wantPrivate {
+				t.Errorf("dropped docs=%d private=%d, want %d/%d", res.Trim.DocsDropped, res.Trim.PrivateDropped, tt.wantDocs, tt.wantPrivate)
+			}
+			if fmt.Sprint(res.Trim.FilesDropped) != fmt.Sprint(tt.wantFiles) {
+				t.Errorf("FilesDropped = %v, want %v", res.Trim.FilesDropped, tt.wantFiles)
+			}
+			for _, f := range tt.wantFiles {
+				if strings.Contains(string(res.Content), f) {
+					t.Errorf("dropped file %s still in output", f)
+				}
+			}
+			if res.TotalFiles != 3-len(tt.wantFiles) {
+				t.Errorf("TotalFiles = %d, want %d", res.TotalFiles, 3-len(tt.wantFiles))
+			}
+		})
+	}
+}
+
+func TestPackageMaxTokensDoesNotMutateExtraction(t *testing.T) {
+	files := budgetFiles()
+	p := newBudgetPackager(files)
+	if _, err := p.Package(context.Background(), &Options{Format: "md", Path: "/repo", MaxTokens: 10}); err != nil {
+		t.Fatalf("Package failed: %v", err)
+	}
+	if files[0].Signatures[0].Doc == "" || len(files[0].Signatures) != 2 {
+		t.Error("trimming modified the extractor's results")
+	}
+}
+
+func TestPackageMaxTokensUnreachable(t *testing.T) {
+	p := newBudgetPackager(budgetFiles())
+	res, err := p.Package(context.Background(), &Options{Format: "md", Path: "/repo", MaxTokens: 1})
+	if err != nil {
+		t.Fatalf("Package failed: %v", err)
+	}
+	if res.Trim == nil || res.Trim.Fits {
+		t.Errorf("expected report that output does not fit, got %+v", res.Trim)
+	}
+	if len(res.Trim.FilesDropped) != 3 {
+		t.Errorf("expected all files dropped, got %v", res.Trim.FilesDropped)
+	}
+}
+
+func TestPackageMaxTokensRequiresTokenizer(t *testing.T) {
+	p := newBudgetPackager(budgetFiles())
+	p.SetTokenizer(nil)
+	// NoOpTokenizer always counts 0, which would silently ignore the budget.
+	_, err := p.Package(context.Background(), &Options{Format: "md", MaxTokens: 1})
+	if err == nil || !strings.Contains(err.Error(), "require token counting") {
+		t.Errorf("expected tokenizer error, got: %v", err)
+	}
+}
+
+func TestFilePriority(t *testing.T) {
+	sig := []parser.Signature{{Name: "X"}}
+	tests := []struct {
+		rel  string
+		file formatter.FileData
+		want int
+	}{
+		{"pkg/api.go", formatter.FileData{Signatures: sig}, 3},
+		{"pkg/api_test.go", formatter.FileData{Signatures: sig}, 0},
+		{"src/app.spec.ts", formatter.FileData{Signatures: sig}, 0},
+		{"tests/test_app.py", formatter.FileData{Signatures: sig}, 0},
+		{"pkg/broken.go", formatter.FileData{Error: fmt.Errorf("parse failed")}, 0},
+		{"examples/demo.go", formatter.FileData{Signatures: sig}, 1},
+		{"api/v1/service.pb.go", formatter.FileData{Signatures: sig}, 1},
+		{"types/index.d.ts", formatter.FileData{Signatures: sig}, 1},
+		{"pkg/doc.go", formatter.FileData{}, 2},
+		{"latest/api.go", formatter.FileData{Signatures: sig}, 3},
+	}
+	for _, tt := range tests {
+		if got := filePriority(tt.rel, tt.file); got != tt.want {
+			t.Errorf("filePriority(%q) = %d, want %d", tt.rel, got, tt.want)
+		}
+	}
+}
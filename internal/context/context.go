@@ -3,12 +3,16 @@ package context
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"sort"
+	"time"
 
 	"github.com/indigo-net/Brf.it/pkg/extractor"
 	"github.com/indigo-net/Brf.it/pkg/formatter"
+	"github.com/indigo-net/Brf.it/pkg/parser"
 	"github.com/indigo-net/Brf.it/pkg/scanner"
 	"github.com/indigo-net/Brf.it/pkg/security"
 	"github.com/indigo-net/Brf.it/pkg/tokenizer"
@@ -47,16 +51,36 @@ type Options struct {
 	// IncludeTree determines whether to include directory tree.
 	IncludeTree bool
 
-	// IncludePrivate determines whether to include private symbols.
+	// IncludePrivate determines whether to include symbols of every
+	// visibility, overriding Visibility.
 	IncludePrivate bool
 
+	// Visibility lists the visibility levels of the symbols to include.
+	// parser.LanguageDefaultVisibility is used when empty.
+	Visibility []parser.Visibility
+
+	// ExcludeDeprecated leaves deprecated symbols out of the output.
+	ExcludeDeprecated bool
+
 	// MaxFileSize is the maximum file size in bytes.
 	MaxFileSize int64
 
+	// ParseTimeout limits the time spent parsing each file. A file that
+	// takes longer is reported as an error. 0 means no limit.
+	ParseTimeout time.Duration
+
+	// QueryDir is the directory of the user-supplied query overrides (see
+	// parser.LoadQueries). Empty or missing means the built-in queries.
+	QueryDir string
+
 	// MaxDocLength is the maximum length of documentation comments.
 	// 0 means no limit (default).
 	MaxDocLength int
 
+	// DocStyle selects how much of each documentation comment to render
+	// (see formatter.DocStyles). Empty means the full doc.
+	DocStyle string
+
 	// NoSchema skips the schema section in XML output.
 	NoSchema bool
 
@@ -66,8 +90,27 @@ type Options struct {
 	// IncludeCallGraph enables function call graph extraction.
 	IncludeCallGraph bool
 
+	// IncludeLocations renders symbol IDs and source ranges in XML and Markdown.
+	IncludeLocations bool
+
+	// IncludeStructure renders structured signature parts as XML attributes.
+	IncludeStructure bool
+
 	// SkipEmpty omits files with no signatures/imports from the output entirely.
 	SkipEmpty bool
+
+	// Mode selects what is rendered per file: "sig" (default), "outline",
+	// "docs" or "full". See the formatter.Mode* constants.
+	Mode string
+
+	// MaxTokens is the token budget for the output. When the output would
+	// exceed it, docs, private symbols and low-priority files are dropped
+	// until it fits. 0 means no limit (default).
+	MaxTokens int
+
+	// SplitTokens splits the output into chunks of at most this many tokens,
+	// keeping files whole (see Result.Chunks). 0 means no splitting (default).
+	SplitTokens int
 }
 
 // DefaultOptions returns Options with sensible defaults.
@@ -100,11 +143,24 @@ type Result struct {
 	// Returns 0 if token counting is disabled or tokenizer is not set.
 	TokenCount int
 
-	// ErrorCount is the number of files that had parsing errors.
+	// ErrorCount is the number of files that had parsing errors, including
+	// files that parsed with syntax errors.
 	ErrorCount int
 
+	// SyntaxErrorCount is the number of files that parsed with syntax
+	// errors.
+	SyntaxErrorCount int
+
 	// ErrorFiles lists files that encountered errors during extraction.
 	ErrorFiles []extractor.ErrorDetail
+
+	// Trim describes what was dropped to honor Options.MaxTokens.
+	// nil when no trimming was needed.
+	Trim *TrimReport
+
+	// Chunks holds the split output when Options.SplitTokens is set.
+	// Content still holds the unsplit output.
+	Chunks []Chunk
 }
 
 // Packager orchestrates scanning, extraction, and formatting.
@@ -154,13 +210,26 @@ func (p *Packager) Package(ctx context.Context, opts *Options) (*Result, error)
 		return nil, err
 	}
 
+	queries, err := parser.LoadQueries(opts.QueryDir)
+	if err != nil {
+		return nil, err
+	}
+
+	// Imports are only rendered in sig mode; full mode has them in the source.
+	includeImports := opts.IncludeImports && (opts.Mode == "" || opts.Mode == formatter.ModeSig)
+
 	// 2. Extract signatures
 	extractOpts := &extractor.ExtractOptions{
-		IncludePrivate: opts.IncludePrivate,
-		IncludeBody:    opts.IncludeBody,
-		IncludeImports: opts.IncludeImports,
-		IncludeCalls:   opts.IncludeCallGraph,
-		MaxFileSize:    opts.MaxFileSize,
+		IncludePrivate:    opts.IncludePrivate,
+		Visibility:        opts.Visibility,
+		ExcludeDeprecated: opts.ExcludeDeprecated,
+		IncludeBody:       opts.IncludeBody,
+		IncludeImports:    includeImports,
+		IncludeCalls:      opts.IncludeCallGraph,
+		IncludeContent:    opts.Mode == formatter.ModeFull,
+		MaxFileSize:       opts.MaxFileSize,
+		ParseTimeout:      opts.ParseTimeout,
+		Queries:           queries,
 	}
 	extractResult, err := p.extractor.Extract(ctx, scanResult, extractOpts)
 	if err != nil {
@@ -188,35 +257,49 @@ func (p *Packager) Package(ctx context.Context, opts *Options) (*Result, error)
 	files := make([]formatter.FileData, len(extractResult.Files))
 	for i, ef := range extractResult.Files {
 		files[i] = formatter.FileData{
-			Path:       ef.Path,
-			Language:   ef.Language,
-			Signatures: ef.Signatures,
-			RawImports: ef.RawImports,
-			Calls:      ef.Calls,
-			Error:      ef.Error,
+			Path:        ef.Path,
+			Language:    ef.Language,
+			Signatures:  ef.Signatures,
+			RawImports:  ef.RawImports,
+			Calls:       ef.Calls,
+			Summary:     ef.Summary,
+			Diagnostics: ef.Diagnostics,
+			Content:     ef.Content,
+			Error:       ef.Error,
 		}
 	}
 
 	// 4.5 Build global imports if DedupeImports is enabled
 	var globalImports []formatter.ImportCount
-	if opts.IncludeImports && opts.DedupeImports {
+	if includeImports && opts.DedupeImports {
 		globalImports = buildGlobalImports(files)
 	}
 
+	// 4.6 Collect package summaries (full mode has them in the source)
+	var packages []formatter.PackageSummary
+	if opts.Mode != formatter.ModeFull {
+		packages = buildPackageSummaries(files)
+	}
+
 	// 5. Create PackageData
 	packageData := &formatter.PackageData{
 		RootPath:         opts.Path,
 		Version:          opts.Version,
+		Mode:             opts.Mode,
 		Tree:             treeStr,
 		Files:            files,
 		TotalSignatures:  extractResult.TotalSignatures,
 		TotalSize:        extractResult.TotalSize,
-		IncludeImports:   opts.IncludeImports,
+		IncludeImports:   includeImports,
 		DedupeImports:    opts.DedupeImports,
 		GlobalImports:    globalImports,
+		Packages:         packages,
 		MaxDocLength:     opts.MaxDocLength,
+		DocStyle:         opts.DocStyle,
 		NoSchema:         opts.NoSchema,
 		IncludeCallGraph: opts.IncludeCallGraph,
+		IncludeLocations: opts.IncludeLocations,
+		IncludeStructure: opts.IncludeStructure,
 		SkipEmpty:        opts.SkipEmpty,
 	}
 
@@ -236,14 +319,38 @@ func (p *Packager) Package(ctx context.Context, opts *Options) (*Result, error)
 	// 8. Calculate token count
 	tokenCount, _ := p.tokenizer.Count(content)
 
+	// 9. Trim output to the token budget
+	if (opts.MaxTokens > 0 || opts.SplitTokens > 0) && p.tokenizer.Name() == "noop" {
+		return nil, fmt.Errorf("token limits require token counting, but no tokenizer is available")
+	}
+	var trim *TrimReport
+	if opts.MaxTokens > 0 && tokenCount > opts.MaxTokens {
+		content, tokenCount, trim, err = p.fitBudget(packageData, f, opts, tokenCount)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 10. Split output into token-bounded chunks
+	var chunks []Chunk
+	if opts.SplitTokens > 0 {
+		chunks, err = p.splitChunks(packageData, f, opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &Result{
-		Content:         content,
-		TotalSignatures: extractResult.TotalSignatures,
-		TotalFiles:      len(extractResult.Files),
-		TotalSize:       extractResult.TotalSize,
-		TokenCount:      tokenCount,
-		ErrorCount:      extractResult.ErrorCount,
-		ErrorFiles:      extractResult.ErrorFiles,
+		Content:          content,
+		TotalSignatures:  packageData.TotalSignatures,
+		TotalFiles:       len(packageData.Files),
+		TotalSize:        extractResult.TotalSize,
+		TokenCount:       tokenCount,
+		ErrorCount:       extractResult.ErrorCount,
+		SyntaxErrorCount: extractResult.SyntaxErrorCount,
+		ErrorFiles:       extractResult.ErrorFiles,
+		Trim:             trim,
+		Chunks:           chunks,
 	}, nil
 }
 
@@ -283,6 +390,44 @@ func normalizeFormat(format string) string {
 	}
 }
 
+// buildPackageSummaries picks the summary of each directory's package from
+// its files, preferring dedicated package doc files (see
+// parser.PackageDocRank), then the first file by path. Directories without
+// a package summary are left out. The result is sorted by path.
+func buildPackageSummaries(files []formatter.FileData) []formatter.PackageSummary {
+	type candidate struct {
+		rank int
+		file formatter.FileData
+	}
+	best := make(map[string]candidate)
+	for _, file := range files {
+		if file.Error != nil || file.Summary == "" {
+			continue
+		}
+		rank := parser.PackageDocRank(file.Language, file.Path)
+		if rank < 0 {
+			continue
+		}
+		dir := filepath.Dir(file.Path)
+		if c, ok := best[dir]; !ok || rank < c.rank || (rank == c.rank && file.Path < c.file.Path) {
+			best[dir] = candidate{rank: rank, file: file}
+		}
+	}
+
+	result := make([]formatter.PackageSummary, 0, len(best))
+	for dir, c := range best {
+		result = append(result, formatter.PackageSummary{
+			Path:    filepath.ToSlash(dir),
+			Summary: c.file.Summary,
+			Source:  c.file.Path,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Path < result[j].Path
+	})
+	return result
+}
+
 // buildGlobalImports collects and deduplicates imports from all files.
 // Returns a list of unique imports with their usage counts, sorted by count (descending).
 func buildGlobalImports(files []formatter.FileData) []formatter.ImportCount {
@@ -0,0 +1,270 @@
+package context
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/indigo-net/Brf.it/pkg/extractor"
+	"github.com/indigo-net/Brf.it/pkg/formatter"
+	"github.com/indigo-net/Brf.it/pkg/parser"
+	"github.com/indigo-net/Brf.it/pkg/scanner"
+	"github.com/indigo-net/Brf.it/pkg/security"
+)
+
+// Snapshot holds the extracted signatures of one revision, keyed by file path
+// relative to the snapshot root (forward-slash separated).
+type Snapshot struct {
+	// Files maps relative paths to extracted files.
+	Files map[string]extractor.ExtractedFile
+
+	// ErrorFiles lists files that encountered errors during extraction.
+	ErrorFiles []extractor.ErrorDetail
+}
+
+// SnapshotOptions configures ExtractSnapshot.
+type SnapshotOptions struct {
+	// Scan configures file discovery. RootPath is the snapshot root.
+	Scan *scanner.ScanOptions
+
+	// Extract configures signature extraction.
+	Extract *extractor.ExtractOptions
+
+	// SecurityCheck enables secret redaction on extracted signatures.
+	SecurityCheck bool
+
+	// Warnings receives security warnings (default: os.Stderr).
+	Warnings io.Writer
+}
+
+// ExtractSnapshot scans and extracts signatures below opts.Scan.RootPath.
+func ExtractSnapshot(ctx context.Context, opts *SnapshotOptions) (*Snapshot, error) {
+	s, err := scanner.NewFileScanner(opts.Scan)
+	if err != nil {
+		return nil, err
+	}
+	scanResult, err := s.Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	extractResult, err := extractor.NewDefaultFileExtractor().Extract(ctx, scanResult, opts.Extract)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.SecurityCheck {
+		warnings := opts.Warnings
+		if warnings == nil {
+			warnings = os.Stderr
+		}
+		extractResult.Files = security.NewScanner(warnings).Scan(extractResult).RedactedFiles
+	}
+
+	// Paths are made relative so both revisions share the same keys
+	// regardless of where each was materialized.
+	base := opts.Scan.RootPath
+	if info, err := os.Stat(base); err == nil && !info.IsDir() {
+		base = filepath.Dir(base)
+	}
+
+	snap := &Snapshot{
+		Files:      make(map[string]extractor.ExtractedFile, len(extractResult.Files)),
+		ErrorFiles: extractResult.ErrorFiles,
+	}
+	for _, ef := range extractResult.Files {
+		rel, err := filepath.Rel(base, ef.Path)
+		if err != nil {
+			rel = ef.Path
+		}
+		ef.Path = filepath.ToSlash(rel)
+		snap.Files[ef.Path] = ef
+	}
+	return snap, nil
+}
+
+// DiffSnapshots compares two snapshots and returns per-file signature changes,
+// sorted by path. Signatures are matched by name and kind; when several share
+// the same name and kind (e.g., overloads), they are paired in source order.
+// Files that failed to extract on either side are skipped.
+func DiffSnapshots(oldSnap, newSnap *Snapshot) []formatter.FileDiff {
+	paths := make(map[string]bool, len(oldSnap.Files)+len(newSnap.Files))
+	for p := range oldSnap.Files {
+		paths[p] = true
+	}
+	for p := range newSnap.Files {
+		paths[p] = true
+	}
+	sorted := make([]string, 0, len(paths))
+	for p := range paths {
+		sorted = append(sorted, p)
+	}
+	sort.Strings(sorted)
+
+	var diffs []formatter.FileDiff
+	for _, path := range sorted {
+		oldFile, inOld := oldSnap.Files[path]
+		newFile, inNew := newSnap.Files[path]
+		if oldFile.Error != nil || newFile.Error != nil {
+			continue
+		}
+
+		changes := diffSignatures(oldFile.Signatures, newFile.Signatures)
+		if len(changes) == 0 {
+			continue
+		}
+
+		lang := newFile.Language
+		if !inNew && inOld {
+			lang = oldFile.Language
+		}
+		diffs = append(diffs, formatter.FileDiff{
+			Path:     path,
+			Language: lang,
+			Changes:  changes,
+		})
+	}
+	return diffs
+}
+
+// signatureKey identifies a signature across revisions.
+type signatureKey struct {
+	name string
+	kind string
+}
+
+// keyOf returns the matching key for a signature. Signatures are keyed by
+// their qualified name (e.g., "pkg.Outer.method"), so same-named methods of
+// different classes or receivers are told apart. Anonymous signatures are
+// keyed by their normalized text, so they can only be added or removed.
+func keyOf(sig parser.Signature) signatureKey {
+	if sig.Name == "" {
+		return signatureKey{name: normalizeSignature(sig.Text), kind: sig.Kind}
+	}
+	return signatureKey{name: sig.FullName(), kind: sig.Kind}
+}
+
+// diffSignatures compares the signatures of one file.
+func diffSignatures(oldSigs, newSigs []parser.Signature) []formatter.SignatureChange {
+	oldByKey := make(map[signatureKey][]int)
+	for i, sig := range oldSigs {
+		k := keyOf(sig)
+		oldByKey[k] = append(oldByKey[k], i)
+	}
+
+	matched := make([]bool, len(oldSigs))
+	var changes []formatter.SignatureChange
+	for i := range newSigs {
+		k := keyOf(newSigs[i])
+		candidates := oldByKey[k]
+		if len(candidates) == 0 {
+			changes = append(changes, formatter.SignatureChange{
+				Type: formatter.ChangeAdded,
+				Name: newSigs[i].Path(),
+				Kind: newSigs[i].Kind,
+				New:  &newSigs[i],
+			})
+			continue
+		}
+		j := candidates[0]
+		oldByKey[k] = candidates[1:]
+		matched[j] = true
+		if declarationText(oldSigs[j]) != declarationText(newSigs[i]) {
+			changes = append(changes, formatter.SignatureChange{
+				Type: formatter.ChangeChanged,
+				Name: newSigs[i].Path(),
+				Kind: newSigs[i].Kind,
+				Old:  &oldSigs[j],
+				New:  &newSigs[i],
+			})
+		}
+	}
+
+	for j := range oldSigs {
+		if matched[j] {
+			continue
+		}
+		changes = append(changes, formatter.SignatureChange{
+			Type: formatter.ChangeRemoved,
+			Name: oldSigs[j].Path(),
+			Kind: oldSigs[j].Kind,
+			Old:  &oldSigs[j],
+		})
+	}
+
+	// Keep changes in source order: removed signatures sort by their old line.
+	sort.SliceStable(changes, func(a, b int) bool {
+		return changeLine(changes[a]) < changeLine(changes[b])
+	})
+	return changes
+}
+
+// changeLine returns the line used to order a change within its file.
+func changeLine(ch formatter.SignatureChange) int {
+	if ch.New != nil {
+		return ch.New.Line
+	}
+	return ch.Old.Line
+}
+
+// declarationText returns the normalized text of sig preceded by its
+// decorators, so added, removed or edited decorators count as changes.
+func declarationText(sig parser.Signature) string {
+	var b strings.Builder
+	for _, dec := range sig.Decorators {
+		b.WriteString(dec.Text)
+		b.WriteByte(' ')
+	}
+	b.WriteString(sig.Text)
+	return normalizeSignature(b.String())
+}
+
+// normalizeSignature collapses whitespace so formatting-only edits are not
+// reported as changes.
+func normalizeSignature(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// BuildDiffData assembles formatter input from the computed file diffs.
+func BuildDiffData(rootPath, version, oldRef, newRef string, files []formatter.FileDiff) *formatter.DiffData {
+	data := &formatter.DiffData{
+		RootPath: rootPath,
+		Version:  version,
+		OldRef:   oldRef,
+		NewRef:   newRef,
+		Files:    files,
+	}
+	for _, f := range files {
+		for _, ch := range f.Changes {
+			switch ch.Type {
+			case formatter.ChangeAdded:
+				data.Added++
+			case formatter.ChangeRemoved:
+				data.Removed++
+			case formatter.ChangeChanged:
+				data.Changed++
+			}
+		}
+	}
+	return data
+}
+
+// FormatDiff renders diff data in the given output format ("xml", "md" or "json").
+func FormatDiff(format string, data *formatter.DiffData) ([]byte, error) {
+	var f formatter.DiffFormatter
+	switch normalizeFormat(format) {
+	case "xml":
+		f = formatter.NewXMLFormatter()
+	case "markdown":
+		f = formatter.NewMarkdownFormatter()
+	case "json":
+		f = formatter.NewJSONFormatter()
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+	return f.FormatDiff(data)
+}
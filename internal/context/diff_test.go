@@ -0,0 +1,166 @@
+package context
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/indigo-net/Brf.it/pkg/extractor"
+	"github.com/indigo-net/Brf.it/pkg/formatter"
+	"github.com/indigo-net/Brf.it/pkg/parser"
+)
+
+func TestDiffSignatures(t *testing.T) {
+	oldSigs := []parser.Signature{
+		{Name: "Keep", Kind: "function", Text: "func Keep()", Line: 1},
+		{Name: "Reflow", Kind: "function", Text: "func Reflow(a int,\n\tb int)", Line: 3},
+		{Name: "Change", Kind: "function", Text: "func Change(a int)", Line: 5},
+		{Name: "Drop", Kind: "function", Text: "func Drop()", Line: 7},
+		{Name: "Drop", Kind: "type", Text: "type Drop struct{}", Line: 9},
+	}
+	newSigs := []parser.Signature{
+		{Name: "Keep", Kind: "function", Text: "func Keep()", Line: 1},
+		{Name: "Reflow", Kind: "function", Text: "func Reflow(a int, b int)", Line: 2},
+		{Name: "Change", Kind: "function", Text: "func Change(a int) error", Line: 4},
+		{Name: "Drop", Kind: "type", Text: "type Drop struct{}", Line: 6},
+		{Name: "Add", Kind: "function", Text: "func Add()", Line: 8},
+	}
+
+	changes := diffSignatures(oldSigs, newSigs)
+
+	var got []string
+	for _, ch := range changes {
+		got = append(got, ch.Type+":"+ch.Name+":"+ch.Kind)
+	}
+	want := []string{"changed:Change:function", "removed:Drop:function", "added:Add:function"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("diffSignatures() = %v, want %v", got, want)
+	}
+}
+
+func TestDiffSignaturesOverloads(t *testing.T) {
+	oldSigs := []parser.Signature{
+		{Name: "Run", Kind: "method", Text: "void Run()", Line: 1},
+		{Name: "Run", Kind: "method", Text: "void Run(int n)", Line: 2},
+	}
+	newSigs := []parser.Signature{
+		{Name: "Run", Kind: "method", Text: "void Run()", Line: 1},
+		{Name: "Run", Kind: "method", Text: "void Run(long n)", Line: 2},
+		{Name: "Run", Kind: "method", Text: "void Run(int n, int m)", Line: 3},
+	}
+
+	changes := diffSignatures(oldSigs, newSigs)
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 changes, got %d: %+v", len(changes), changes)
+	}
+	if changes[0].Type != formatter.ChangeChanged || changes[0].Old.Text != "void Run(int n)" {
+		t.Errorf("expected second overload to be paired as changed, got %+v", changes[0])
+	}
+	if changes[1].Type != formatter.ChangeAdded {
+		t.Errorf("expected extra overload to be added, got %+v", changes[1])
+	}
+}
+
+func TestDiffSignaturesMembers(t *testing.T) {
+	// toString moved from A to B: same name and kind, different containers
+	oldSigs := []parser.Signature{
+		{Name: "A", Kind: "class", Text: "class A", Line: 1},
+		{Name: "toString", Kind: "method", Text: "String toString()", Line: 2, Parent: "A"},
+		{Name: "B", Kind: "class", Text: "class B", Line: 4},
+	}
+	newSigs := []parser.Signature{
+		{Name: "A", Kind: "class", Text: "class A", Line: 1},
+		{Name: "B", Kind: "class", Text: "class B", Line: 3},
+		{Name: "toString", Kind: "method", Text: "String toString()", Line: 4, Parent: "B"},
+	}
+
+	changes := diffSignatures(oldSigs, newSigs)
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 changes, got %d: %+v", len(changes), changes)
+	}
+	if changes[0].Type != formatter.ChangeRemoved || changes[0].Name != "A.toString" {
+		t.Errorf("expected A.toString removed, got %+v", changes[0])
+	}
+	if changes[1].Type != formatter.ChangeAdded || changes[1].Name != "B.toString" {
+		t.Errorf("expected B.toString added, got %+v", changes[1])
+	}
+}
+
+func TestDiffSignaturesQualifiedNames(t *testing.T) {
+	// Go methods have no container signature; the receiver in the qualified
+	// name keeps A.Parse and B.Parse apart.
+	oldSigs := []parser.Signature{
+		{Name: "Parse", Kind: "method", Text: "func (a A) Parse()", Line: 3, QualifiedName: "p.A.Parse"},
+		{Name: "Parse", Kind: "method", Text: "func (b B) Parse()", Line: 5, QualifiedName: "p.B.Parse"},
+	}
+	newSigs := []parser.Signature{
+		{Name: "Parse", Kind: "method", Text: "func (b B) Parse() error", Line: 3, QualifiedName: "p.B.Parse"},
+		{Name: "Parse", Kind: "method", Text: "func (a A) Parse()", Line: 5, QualifiedName: "p.A.Parse"},
+	}
+
+	changes := diffSignatures(oldSigs, newSigs)
+	if len(changes) != 1 {
+		t.Fatalf("expected 1 change, got %d: %+v", len(changes), changes)
+	}
+	if changes[0].Type != formatter.ChangeChanged || changes[0].New.QualifiedName != "p.B.Parse" {
+		t.Errorf("expected p.B.Parse changed, got %+v", changes[0])
+	}
+}
+
+func TestDiffSignaturesDecorators(t *testing.T) {
+	oldSigs := []parser.Signature{
+		{Name: "list", Kind: "function", Text: "def list()", Decorators: []parser.Decorator{{Name: "get", Args: `"/a"`, Text: `@get("/a")`}}},
+		{Name: "show", Kind: "function", Text: "def show()", Decorators: []parser.Decorator{{Name: "get", Text: "@get"}}},
+	}
+	newSigs := []parser.Signature{
+		{Name: "list", Kind: "function", Text: "def list()", Decorators: []parser.Decorator{{Name: "get", Args: `"/b"`, Text: `@get("/b")`}}},
+		{Name: "show", Kind: "function", Text: "def show()", Decorators: []parser.Decorator{{Name: "get", Text: "@get"}}},
+	}
+
+	changes := diffSignatures(oldSigs, newSigs)
+	if len(changes) != 1 || changes[0].Type != formatter.ChangeChanged || changes[0].Name != "list" {
+		t.Errorf("expected list changed, got %+v", changes)
+	}
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	oldSnap := &Snapshot{Files: map[string]extractor.ExtractedFile{
+		"b.go":      {Path: "b.go", Language: "go", Signatures: []parser.Signature{{Name: "B", Kind: "function", Text: "func B()"}}},
+		"gone.go":   {Path: "gone.go", Language: "go", Signatures: []parser.Signature{{Name: "G", Kind: "function", Text: "func G()"}}},
+		"same.go":   {Path: "same.go", Language: "go", Signatures: []parser.Signature{{Name: "S", Kind: "function", Text: "func S()"}}},
+		"broken.go": {Path: "broken.go", Language: "go", Signatures: []parser.Signature{{Name: "X", Kind: "function", Text: "func X()"}}},
+	}}
+	newSnap := &Snapshot{Files: map[string]extractor.ExtractedFile{
+		"a.py":      {Path: "a.py", Language: "python", Signatures: []parser.Signature{{Name: "a", Kind: "function", Text: "def a()"}}},
+		"b.go":      {Path: "b.go", Language: "go", Signatures: []parser.Signature{{Name: "B", Kind: "function", Text: "func B() error"}}},
+		"same.go":   {Path: "same.go", Language: "go", Signatures: []parser.Signature{{Name: "S", Kind: "function", Text: "func S()"}}},
+		"broken.go": {Path: "broken.go", Language: "go", Error: fmt.Errorf("parse failed")},
+	}}
+
+	diffs := DiffSnapshots(oldSnap, newSnap)
+
+	var paths []string
+	for _, d := range diffs {
+		paths = append(paths, d.Path)
+	}
+	if strings.Join(paths, ",") != "a.py,b.go,gone.go" {
+		t.Errorf("unexpected files: %v", paths)
+	}
+	if diffs[2].Language != "go" || diffs[2].Changes[0].Type != formatter.ChangeRemoved {
+		t.Errorf("expected removed file to keep its language and report removals, got %+v", diffs[2])
+	}
+
+	data := BuildDiffData("/repo", "dev", "v1", "v2", diffs)
+	if data.Added != 1 || data.Removed != 1 || data.Changed != 1 {
+		t.Errorf("unexpected totals: added=%d removed=%d changed=%d", data.Added, data.Removed, data.Changed)
+	}
+}
+
+func TestFormatDiffUnsupported(t *testing.T) {
+	if _, err := FormatDiff("yaml", &formatter.DiffData{}); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+	if _, err := FormatDiff("md", &formatter.DiffData{}); err != nil {
+		t.Errorf("unexpected error for md: %v", err)
+	}
+}
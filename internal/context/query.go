@@ -0,0 +1,174 @@
+package context
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/indigo-net/Brf.it/pkg/formatter"
+	"github.com/indigo-net/Brf.it/pkg/parser"
+)
+
+// SymbolQuery selects signatures by name, kind, language, visibility and path.
+// Empty fields match everything.
+type SymbolQuery struct {
+	// Name is a glob (path.Match syntax, e.g. "Parse*") matched against the
+	// whole symbol name. A pattern containing a name separator (".", "::" or
+	// "\\") is matched against the qualified name too (e.g. "scanner.*.Scan").
+	Name string
+
+	// Regex, when set, is matched against the symbol name and qualified name
+	// instead of Name.
+	Regex *regexp.Regexp
+
+	// IgnoreCase makes Name matching case-insensitive.
+	IgnoreCase bool
+
+	// Kinds matches either the raw kind (e.g. "method") or the normalized
+	// kind (e.g. "function").
+	Kinds []string
+
+	// Languages matches the language identifier (e.g. "go").
+	Languages []string
+
+	// Exported filters by visibility when non-nil.
+	Exported *bool
+
+	// Paths are doublestar globs matched against the relative file path.
+	Paths []string
+}
+
+// Validate checks the Name and Paths patterns.
+func (q *SymbolQuery) Validate() error {
+	if _, err := path.Match(q.Name, ""); err != nil {
+		return fmt.Errorf("invalid name pattern %q: %w", q.Name, err)
+	}
+	for _, p := range q.Paths {
+		if !doublestar.ValidatePattern(p) {
+			return fmt.Errorf("invalid path pattern %q", p)
+		}
+	}
+	return nil
+}
+
+// Matches reports whether sig in the file at relPath matches the query.
+func (q *SymbolQuery) Matches(relPath, language string, sig parser.Signature) bool {
+	if !q.matchName(sig.Name) && !q.matchQualifiedName(sig.QualifiedName) {
+		return false
+	}
+	if len(q.Kinds) > 0 && !slices.Contains(q.Kinds, sig.Kind) &&
+		!slices.Contains(q.Kinds, formatter.NormalizeKind(sig.Kind)) {
+		return false
+	}
+	if len(q.Languages) > 0 && !slices.Contains(q.Languages, language) {
+		return false
+	}
+	if q.Exported != nil && sig.Exported != *q.Exported {
+		return false
+	}
+	if len(q.Paths) > 0 && !slices.ContainsFunc(q.Paths, func(p string) bool {
+		// Patterns are validated in Validate; error is unreachable
+		matched, _ := doublestar.Match(p, relPath)
+		return matched
+	}) {
+		return false
+	}
+	return true
+}
+
+// matchName matches name against Regex, or the Name glob.
+func (q *SymbolQuery) matchName(name string) bool {
+	if q.Regex != nil {
+		return q.Regex.MatchString(name)
+	}
+	if q.Name == "" {
+		return true
+	}
+	pattern := q.Name
+	if q.IgnoreCase {
+		pattern, name = strings.ToLower(pattern), strings.ToLower(name)
+	}
+	matched, _ := path.Match(pattern, name)
+	return matched
+}
+
+// matchQualifiedName matches a qualified name against Regex, or against a
+// Name glob that spells out a qualified name.
+func (q *SymbolQuery) matchQualifiedName(name string) bool {
+	if name == "" {
+		return false
+	}
+	if q.Regex == nil && !strings.ContainsAny(q.Name, ".:\\") {
+		return false
+	}
+	return q.matchName(name)
+}
+
+// SymbolMatch is a signature matched by a SymbolQuery.
+type SymbolMatch struct {
+	ID            string `json:"id"`
+	Path          string `json:"path"`
+	Line          int    `json:"line"`
+	EndLine       int    `json:"endLine"`
+	Column        int    `json:"column"`
+	EndColumn     int    `json:"endColumn"`
+	StartByte     int    `json:"startByte"`
+	EndByte       int    `json:"endByte"`
+	Language      string `json:"language"`
+	Kind          string `json:"kind"`
+	Name          string `json:"name"`
+	QualifiedName string `json:"qualifiedName,omitempty"`
+	Parent        string `json:"parent,omitempty"`
+	Text          string `json:"text"`
+	Doc           string `json:"doc,omitempty"`
+	Exported      bool   `json:"exported"`
+	Visibility    string `json:"visibility,omitempty"`
+	Deprecated    bool   `json:"deprecated,omitempty"`
+}
+
+// FindSymbols returns the signatures in snap matching q, ordered by path and line.
+func FindSymbols(snap *Snapshot, q *SymbolQuery) []SymbolMatch {
+	var matches []SymbolMatch
+	for rel, ef := range snap.Files {
+		var ids []string
+		for i, sig := range ef.Signatures {
+			if !q.Matches(rel, ef.Language, sig) {
+				continue
+			}
+			if ids == nil {
+				ids = parser.SymbolIDs(ef.Language, rel, ef.Signatures)
+			}
+			matches = append(matches, SymbolMatch{
+				ID:            ids[i],
+				Path:          rel,
+				Line:          sig.Line,
+				EndLine:       sig.EndLine,
+				Column:        sig.Column,
+				EndColumn:     sig.EndColumn,
+				StartByte:     sig.StartByte,
+				EndByte:       sig.EndByte,
+				Language:      ef.Language,
+				Kind:          sig.Kind,
+				Name:          sig.Name,
+				QualifiedName: sig.QualifiedName,
+				Parent:        sig.Parent,
+				Text:          sig.Text,
+				Doc:           sig.Doc,
+				Exported:      sig.Exported,
+				Visibility:    string(sig.Visibility),
+				Deprecated:    sig.Deprecated,
+			})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Path != matches[j].Path {
+			return matches[i].Path < matches[j].Path
+		}
+		return matches[i].Line < matches[j].Line
+	})
+	return matches
+}
@@ -0,0 +1,145 @@
+package context
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/indigo-net/Brf.it/pkg/extractor"
+	"github.com/indigo-net/Brf.it/pkg/formatter"
+	"github.com/indigo-net/Brf.it/pkg/parser"
+	"github.com/indigo-net/Brf.it/pkg/scanner"
+)
+
+func querySnapshot() *Snapshot {
+	return &Snapshot{Files: map[string]extractor.ExtractedFile{
+		"pkg/parser/parse.go": {Language: "go", Signatures: []parser.Signature{
+			{Name: "ParseFile", Kind: "function", Text: "func ParseFile(path string) error", Line: 10, Exported: true, Doc: "ParseFile parses a file."},
+			{Name: "parseLine", Kind: "function", Text: "func parseLine(s string) int", Line: 20},
+			{Name: "Parser", Kind: "struct", Text: "type Parser struct", Line: 3, Exported: true},
+			{Name: "Parse", Kind: "method", Text: "func (p *Parser) Parse() error", Line: 30, Exported: true, QualifiedName: "parser.Parser.Parse"},
+		}},
+		"web/parse.ts": {Language: "typescript", Signatures: []parser.Signature{
+			{Name: "parseUrl", Kind: "function", Text: "export function parseUrl(u: string): URL", Line: 1, Exported: true},
+		}},
+	}}
+}
+
+func TestFindSymbols(t *testing.T) {
+	exported, private := true, false
+	tests := []struct {
+		name  string
+		query SymbolQuery
+		want  []string // path:name
+	}{
+		{"all", SymbolQuery{}, []string{"pkg/parser/parse.go:Parser", "pkg/parser/parse.go:ParseFile", "pkg/parser/parse.go:parseLine", "pkg/parser/parse.go:Parse", "web/parse.ts:parseUrl"}},
+		{"glob", SymbolQuery{Name: "Parse*"}, []string{"pkg/parser/parse.go:Parser", "pkg/parser/parse.go:ParseFile", "pkg/parser/parse.go:Parse"}},
+		{"glob is anchored", SymbolQuery{Name: "File"}, nil},
+		{"ignore case", SymbolQuery{Name: "parse*", IgnoreCase: true, Languages: []string{"typescript"}}, []string{"web/parse.ts:parseUrl"}},
+		{"regex", SymbolQuery{Regex: regexp.MustCompile(`Line$|Url$`)}, []string{"pkg/parser/parse.go:parseLine", "web/parse.ts:parseUrl"}},
+		{"raw kind", SymbolQuery{Kinds: []string{"method"}}, []string{"pkg/parser/parse.go:Parse"}},
+		{"normalized kind", SymbolQuery{Name: "Parse*", Kinds: []string{"function"}}, []string{"pkg/parser/parse.go:ParseFile", "pkg/parser/parse.go:Parse"}},
+		{"language", SymbolQuery{Languages: []string{"go"}, Kinds: []string{"type"}}, []string{"pkg/parser/parse.go:Parser"}},
+		{"exported", SymbolQuery{Name: "parse*", Exported: &exported}, []string{"web/parse.ts:parseUrl"}},
+		{"private", SymbolQuery{Exported: &private}, []string{"pkg/parser/parse.go:parseLine"}},
+		{"path", SymbolQuery{Paths: []string{"web/**"}}, []string{"web/parse.ts:parseUrl"}},
+		{"qualified glob", SymbolQuery{Name: "parser.*.Parse"}, []string{"pkg/parser/parse.go:Parse"}},
+		{"qualified regex", SymbolQuery{Regex: regexp.MustCompile(`^parser\.Parser\.`)}, []string{"pkg/parser/parse.go:Parse"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.query.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			matches := FindSymbols(querySnapshot(), &tt.query)
+			var got []string
+			for _, m := range matches {
+				got = append(got, m.Path+":"+m.Name)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("match %d = %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFindSymbolsIDsMatchFormatter(t *testing.T) {
+	root := filepath.FromSlash("/tmp/proj")
+	path := filepath.Join(root, "pkg", "a", "a.go")
+	sigs := []parser.Signature{
+		{Name: "Thing", Kind: "type", Text: "type Thing struct", Line: 3, Exported: true, QualifiedName: "a.Thing"},
+		{Name: "Run", Kind: "method", Text: "func (t *Thing) Run()", Line: 5, Exported: true, Parent: "Thing", QualifiedName: "a.Thing.Run"},
+	}
+
+	p := NewPackager(
+		&mockScanner{result: &scanner.ScanResult{Files: []scanner.FileEntry{{Path: path, Language: "go"}}}},
+		&mockExtractor{result: &extractor.ExtractResult{Files: []extractor.ExtractedFile{{Path: path, Language: "go", Signatures: sigs}}}},
+		map[string]formatter.Formatter{"json": formatter.NewJSONFormatter()},
+	)
+	result, err := p.Package(context.Background(), &Options{Path: root, Format: "json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out struct {
+		Files []struct {
+			Signatures []struct {
+				ID      string `json:"id"`
+				Members []struct {
+					ID string `json:"id"`
+				} `json:"members"`
+			} `json:"signatures"`
+		} `json:"files"`
+	}
+	if err := json.Unmarshal(result.Content, &out); err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Files) != 1 || len(out.Files[0].Signatures) != 1 || len(out.Files[0].Signatures[0].Members) != 1 {
+		t.Fatalf("unexpected output:\n%s", result.Content)
+	}
+	formatted := []string{out.Files[0].Signatures[0].ID, out.Files[0].Signatures[0].Members[0].ID}
+
+	snap := &Snapshot{Files: map[string]extractor.ExtractedFile{"pkg/a/a.go": {Language: "go", Signatures: sigs}}}
+	matches := FindSymbols(snap, &SymbolQuery{})
+	if len(matches) != 2 {
+		t.Fatalf("expected 2 matches, got %d", len(matches))
+	}
+	for i, m := range matches {
+		if m.ID != formatted[i] {
+			t.Errorf("query ID %q does not match formatter ID %q", m.ID, formatted[i])
+		}
+	}
+	if matches[1].ID != "go:pkg/a/a.go#a.Thing.Run:method" {
+		t.Errorf("unexpected ID %q", matches[1].ID)
+	}
+}
+
+func TestFindSymbolsMatchFields(t *testing.T) {
+	matches := FindSymbols(querySnapshot(), &SymbolQuery{Name: "ParseFile"})
+	if len(matches) != 1 {
+		t.Fatalf("expected 1 match, got %d", len(matches))
+	}
+	want := SymbolMatch{
+		ID:   "go:pkg/parser/parse.go#ParseFile:function",
+		Path: "pkg/parser/parse.go", Line: 10, Language: "go", Kind: "function", Name: "ParseFile",
+		Text: "func ParseFile(path string) error", Doc: "ParseFile parses a file.", Exported: true,
+	}
+	if matches[0] != want {
+		t.Errorf("got %+v, want %+v", matches[0], want)
+	}
+}
+
+func TestSymbolQueryValidate(t *testing.T) {
+	if err := (&SymbolQuery{Name: "[a-"}).Validate(); err == nil {
+		t.Error("expected error for invalid name pattern")
+	}
+	if err := (&SymbolQuery{Paths: []string{"pkg/[a-"}}).Validate(); err == nil {
+		t.Error("expected error for invalid path pattern")
+	}
+}
@@ -0,0 +1,158 @@
+package context
+
+import (
+	"fmt"
+
+	"github.com/indigo-net/Brf.it/pkg/formatter"
+)
+
+// Chunk is one self-contained piece of split output.
+type Chunk struct {
+	// Content is the formatted output of the chunk (a complete document).
+	Content []byte
+
+	// Files lists the files in the chunk, relative to the packaged root.
+	Files []string
+
+	// TokenCount is the number of tokens in Content.
+	TokenCount int
+
+	// Oversized indicates a single file that exceeds the limit on its own.
+	// Files are never split, so such a chunk is emitted over the limit.
+	Oversized bool
+}
+
+// Manifest describes split output: which files are in which chunk.
+type Manifest struct {
+	Version     string          `json:"version,omitempty"`
+	Path        string          `json:"path,omitempty"`
+	SplitTokens int             `json:"splitTokens"`
+	Chunks      []ManifestChunk `json:"chunks"`
+}
+
+// ManifestChunk is a single chunk entry in the manifest.
+type ManifestChunk struct {
+	File      string   `json:"file"`
+	Tokens    int      `json:"tokens"`
+	Files     []string `json:"files"`
+	Oversized bool     `json:"oversized,omitempty"`
+}
+
+// NewManifest builds the manifest for chunks written to the given file names.
+func NewManifest(opts *Options, chunks []Chunk, names []string) *Manifest {
+	m := &Manifest{
+		Version:     opts.Version,
+		Path:        opts.Path,
+		SplitTokens: opts.SplitTokens,
+		Chunks:      make([]ManifestChunk, len(chunks)),
+	}
+	for i, c := range chunks {
+		files := c.Files
+		if files == nil {
+			files = []string{}
+		}
+		m.Chunks[i] = ManifestChunk{
+			File:      names[i],
+			Tokens:    c.TokenCount,
+			Files:     files,
+			Oversized: c.Oversized,
+		}
+	}
+	return m
+}
+
+// splitChunks partitions data into chunks of at most opts.SplitTokens tokens,
+// keeping each file whole. Every chunk is a complete document with its own
+// header and a directory tree of its files. Files are packed greedily in
+// output order using per-file cost estimates; a chunk whose real count
+// exceeds the limit gives its last file back until it fits.
+func (p *Packager) splitChunks(data *formatter.PackageData, f formatter.Formatter, opts *Options) ([]Chunk, error) {
+	limit := opts.SplitTokens
+
+	// Files the formatter would skip are left out of the chunks and manifest.
+	files := make([]formatter.FileData, 0, len(data.Files))
+	for _, fd := range data.Files {
+		if data.SkipEmpty && formatter.FileIsEmpty(data, fd) {
+			continue
+		}
+		files = append(files, fd)
+	}
+
+	render := func(chunkFiles []formatter.FileData) (Chunk, error) {
+		chunkData := *data
+		chunkData.Files = chunkFiles
+		paths := make([]string, len(chunkFiles))
+		rel := make([]string, len(chunkFiles))
+		for i, fd := range chunkFiles {
+			paths[i] = fd.Path
+			rel[i] = relativeTo(opts.Path, fd.Path)
+		}
+		if data.Tree != "" {
+			chunkData.Tree = BuildTree(opts.Path, paths)
+		}
+		if data.DedupeImports {
+			chunkData.GlobalImports = buildGlobalImports(chunkFiles)
+		}
+		if len(data.Packages) > 0 {
+			chunkData.Packages = buildPackageSummaries(chunkFiles)
+		}
+		content, err := f.Format(&chunkData)
+		if err != nil {
+			return Chunk{}, err
+		}
+		count, err := p.tokenizer.Count(content)
+		if err != nil {
+			return Chunk{}, fmt.Errorf("token count failed: %w", err)
+		}
+		return Chunk{Content: content, Files: rel, TokenCount: count}, nil
+	}
+
+	if len(files) == 0 {
+		c, err := render(nil)
+		if err != nil {
+			return nil, err
+		}
+		return []Chunk{c}, nil
+	}
+
+	header, err := render(nil)
+	if err != nil {
+		return nil, err
+	}
+	costData := *data
+	costData.Files = files
+	costs, err := p.fileCosts(&costData, f)
+	if err != nil {
+		return nil, err
+	}
+
+	var chunks []Chunk
+	for start := 0; start < len(files); {
+		// Greedy estimate of how many files fit.
+		n, running := 0, header.TokenCount
+		for start+n < len(files) {
+			c := costs[start+n]
+			if n > 0 && running+c > limit {
+				break
+			}
+			running += c
+			n++
+		}
+
+		// Verify against the real count and shrink until the chunk fits.
+		for {
+			c, err := render(files[start : start+n])
+			if err != nil {
+				return nil, err
+			}
+			if c.TokenCount <= limit || n == 1 {
+				c.Oversized = c.TokenCount > limit
+				chunks = append(chunks, c)
+				break
+			}
+			n--
+		}
+		start += n
+	}
+	return chunks, nil
+}
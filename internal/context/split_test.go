@@ -0,0 +1,124 @@
+package context
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/indigo-net/Brf.it/pkg/extractor"
+	"github.com/indigo-net/Brf.it/pkg/formatter"
+	"github.com/indigo-net/Brf.it/pkg/parser"
+)
+
+// splitFiles returns n files with one exported signature each.
+func splitFiles(n int) []extractor.ExtractedFile {
+	files := make([]extractor.ExtractedFile, n)
+	for i := range files {
+		name := fmt.Sprintf("Func%d", i)
+		files[i] = extractor.ExtractedFile{
+			Path:     fmt.Sprintf("/repo/pkg%d/file.go", i),
+			Language: "go",
+			Signatures: []parser.Signature{
+				{Name: name, Kind: "function", Text: "func " + name + "(a, b, c int) (int, error)", Exported: true},
+			},
+		}
+	}
+	return files
+}
+
+func TestPackageSplitTokens(t *testing.T) {
+	formats := map[string]func([]byte) error{
+		"xml":  func(b []byte) error { return xml.Unmarshal(b, new(struct{})) },
+		"md":   func([]byte) error { return nil },
+		"json": func(b []byte) error { var v any; return json.Unmarshal(b, &v) },
+	}
+
+	for format, validate := range formats {
+		t.Run(format, func(t *testing.T) {
+			p := newBudgetPackager(splitFiles(10))
+			p.formatters = map[string]formatter.Formatter{
+				"xml":      formatter.NewXMLFormatter(),
+				"markdown": formatter.NewMarkdownFormatter(),
+				"json":     formatter.NewJSONFormatter(),
+			}
+			opts := &Options{Format: format, Path: "/repo", IncludeTree: true, NoSchema: true, SplitTokens: 60}
+
+			res, err := p.Package(context.Background(), opts)
+			if err != nil {
+				t.Fatalf("Package failed: %v", err)
+			}
+			if len(res.Chunks) < 2 {
+				t.Fatalf("expected several chunks, got %d", len(res.Chunks))
+			}
+
+			var seen []string
+			for i, c := range res.Chunks {
+				if c.TokenCount > opts.SplitTokens || c.Oversized {
+					t.Errorf("chunk %d has %d tokens, limit %d", i, c.TokenCount, opts.SplitTokens)
+				}
+				if err := validate(c.Content); err != nil {
+					t.Errorf("chunk %d is not valid %s: %v", i, format, err)
+				}
+				for _, f := range c.Files {
+					if !strings.Contains(string(c.Content), f) {
+						t.Errorf("chunk %d lists %s but does not contain it", i, f)
+					}
+				}
+				seen = append(seen, c.Files...)
+			}
+			if len(seen) != 10 || seen[0] != "pkg0/file.go" || seen[9] != "pkg9/file.go" {
+				t.Errorf("expected every file exactly once in order, got %v", seen)
+			}
+		})
+	}
+}
+
+func TestPackageSplitTokensOversizedFile(t *testing.T) {
+	files := splitFiles(2)
+	files[1].Signatures[0].Doc = strings.Repeat("word ", 100)
+	p := newBudgetPackager(files)
+
+	res, err := p.Package(context.Background(), &Options{Format: "md", Path: "/repo", SplitTokens: 40})
+	if err != nil {
+		t.Fatalf("Package failed: %v", err)
+	}
+	if len(res.Chunks) != 2 {
+		t.Fatalf("expected 2 chunks, got %d", len(res.Chunks))
+	}
+	if res.Chunks[0].Oversized || !res.Chunks[1].Oversized {
+		t.Errorf("expected only the second chunk to be oversized: %v, %v", res.Chunks[0].Oversized, res.Chunks[1].Oversized)
+	}
+}
+
+func TestPackageSplitTokensEmpty(t *testing.T) {
+	p := newBudgetPackager(nil)
+	res, err := p.Package(context.Background(), &Options{Format: "md", SplitTokens: 100})
+	if err != nil {
+		t.Fatalf("Package failed: %v", err)
+	}
+	if len(res.Chunks) != 1 || len(res.Chunks[0].Files) != 0 {
+		t.Errorf("expected a single empty chunk, got %+v", res.Chunks)
+	}
+}
+
+func TestNewManifest(t *testing.T) {
+	chunks := []Chunk{
+		{Files: []string{"a.go", "b.go"}, TokenCount: 90},
+		{TokenCount: 5},
+	}
+	m := NewManifest(&Options{Version: "1.0.0", Path: "/repo", SplitTokens: 100}, chunks, []string{"out.001.xml", "out.002.xml"})
+
+	out, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"version":"1.0.0","path":"/repo","splitTokens":100,"chunks":[` +
+		`{"file":"out.001.xml","tokens":90,"files":["a.go","b.go"]},` +
+		`{"file":"out.002.xml","tokens":5,"files":[]}]}`
+	if string(out) != want {
+		t.Errorf("manifest JSON mismatch:\ngot  %s\nwant %s", out, want)
+	}
+}
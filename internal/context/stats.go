@@ -0,0 +1,238 @@
+package context
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/indigo-net/Brf.it/pkg/extractor"
+	"github.com/indigo-net/Brf.it/pkg/formatter"
+	"github.com/indigo-net/Brf.it/pkg/parser"
+)
+
+// Counts aggregates API surface statistics for a group of files.
+type Counts struct {
+	// Files is the number of files in the group.
+	Files int `json:"files"`
+
+	// Errors is the number of files that failed to parse.
+	Errors int `json:"errors,omitempty"`
+
+	// Signatures is the number of extracted signatures, private ones included.
+	Signatures int `json:"signatures"`
+
+	// Kinds counts signatures by normalized kind ("function", "type", "variable").
+	Kinds map[string]int `json:"kinds"`
+
+	// Exported is the number of exported signatures.
+	Exported int `json:"exported"`
+
+	// Private is the number of non-exported signatures.
+	Private int `json:"private"`
+
+	// Documented is the number of signatures with a non-empty doc comment.
+	Documented int `json:"documented"`
+
+	// ExportedRatio is Exported / Signatures (0 when there are no signatures).
+	ExportedRatio float64 `json:"exportedRatio"`
+
+	// DocCoverage is Documented / Signatures (0 when there are no signatures).
+	DocCoverage float64 `json:"docCoverage"`
+
+	// SourceTokens is the number of tokens in the raw source of the files.
+	SourceTokens int `json:"sourceTokens,omitempty"`
+
+	// BriefTokens is the estimated number of tokens the files contribute to
+	// the packaged output.
+	BriefTokens int `json:"briefTokens,omitempty"`
+}
+
+// add accumulates a single file into c.
+func (c *Counts) add(ef extractor.ExtractedFile, sourceTokens, briefTokens int) {
+	c.Files++
+	if ef.Error != nil {
+		c.Errors++
+	}
+	for _, sig := range ef.Signatures {
+		c.Signatures++
+		c.Kinds[formatter.NormalizeKind(sig.Kind)]++
+		if sig.Exported {
+			c.Exported++
+		} else {
+			c.Private++
+		}
+		if strings.TrimSpace(sig.Doc) != "" {
+			c.Documented++
+		}
+	}
+	c.SourceTokens += sourceTokens
+	c.BriefTokens += briefTokens
+}
+
+// finish computes the ratios from the accumulated counts.
+func (c *Counts) finish() {
+	if c.Signatures > 0 {
+		c.ExportedRatio = float64(c.Exported) / float64(c.Signatures)
+		c.DocCoverage = float64(c.Documented) / float64(c.Signatures)
+	}
+}
+
+// Stats summarizes the API surface of a project.
+type Stats struct {
+	// Version is the brf.it version string.
+	Version string `json:"version,omitempty"`
+
+	// Path is the analyzed root path.
+	Path string `json:"path,omitempty"`
+
+	// Tokenizer is the name of the tokenizer used for token counts.
+	// Empty when token counting is disabled.
+	Tokenizer string `json:"tokenizer,omitempty"`
+
+	// Total holds the counts over all files.
+	Total *Counts `json:"total"`
+
+	// Languages holds the counts per language.
+	Languages map[string]*Counts `json:"languages"`
+
+	// Dirs holds the counts per top-level directory, relative to Path.
+	// Files directly under Path are grouped under ".".
+	Dirs map[string]*Counts `json:"dirs"`
+}
+
+// newCounts returns an empty Counts.
+func newCounts() *Counts {
+	return &Counts{Kinds: make(map[string]int)}
+}
+
+// group returns the Counts for key in m, creating it if needed.
+func group(m map[string]*Counts, key string) *Counts {
+	c, ok := m[key]
+	if !ok {
+		c = newCounts()
+		m[key] = c
+	}
+	return c
+}
+
+// topLevelDir returns the first path element of rel, or "." for files
+// directly under the root.
+func topLevelDir(rel string) string {
+	if i := strings.IndexByte(rel, '/'); i >= 0 {
+		return rel[:i]
+	}
+	return "."
+}
+
+// Stats scans and extracts files like Package, but returns statistics about
+// the extracted API surface instead of formatted output.
+// Private symbols are always extracted so the exported/private ratio is
+// meaningful; brief token counts include only the levels in opts.Visibility
+// (or every level with opts.IncludePrivate).
+// Token counts are zero when no tokenizer is set.
+func (p *Packager) Stats(ctx context.Context, opts *Options) (*Stats, error) {
+	if opts == nil {
+		opts = DefaultOptions()
+	}
+	counting := p.tokenizer.Name() != "noop"
+
+	// 1. Scan files
+	scanResult, err := p.scanner.Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	queries, err := parser.LoadQueries(opts.QueryDir)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Extract signatures (and source, for source token counts)
+	includeImports := opts.IncludeImports && (opts.Mode == "" || opts.Mode == formatter.ModeSig)
+	extractResult, err := p.extractor.Extract(ctx, scanResult, &extractor.ExtractOptions{
+		IncludePrivate: true,
+		IncludeBody:    opts.IncludeBody,
+		IncludeImports: includeImports,
+		IncludeContent: counting,
+		MaxFileSize:    opts.MaxFileSize,
+		ParseTimeout:   opts.ParseTimeout,
+		Queries:        queries,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Estimate each file's share of the packaged output
+	var briefCosts []int
+	if counting {
+		files := make([]formatter.FileData, len(extractResult.Files))
+		for i, ef := range extractResult.Files {
+			sigs := ef.Signatures
+			if !opts.IncludePrivate {
+				sigs = make([]parser.Signature, 0, len(ef.Signatures))
+				for _, sig := range ef.Signatures {
+					if parser.IncludesVisibility(ef.Language, opts.Visibility, sig.Visibility) {
+						sigs = append(sigs, sig)
+					}
+				}
+			}
+			files[i] = formatter.FileData{
+				Path:        ef.Path,
+				Language:    ef.Language,
+				Signatures:  sigs,
+				RawImports:  ef.RawImports,
+				Diagnostics: ef.Diagnostics,
+				Content:     ef.Content,
+				Error:       ef.Error,
+			}
+		}
+		f, ok := p.formatters[normalizeFormat(opts.Format)]
+		if !ok {
+			f = p.formatters["xml"]
+		}
+		briefCosts, err = p.fileCosts(&formatter.PackageData{
+			Files:          files,
+			Mode:           opts.Mode,
+			IncludeImports: includeImports,
+			MaxDocLength:   opts.MaxDocLength,
+			DocStyle:       opts.DocStyle,
+		}, f)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 4. Aggregate
+	stats := &Stats{
+		Version:   opts.Version,
+		Path:      opts.Path,
+		Total:     newCounts(),
+		Languages: make(map[string]*Counts),
+		Dirs:      make(map[string]*Counts),
+	}
+	if counting {
+		stats.Tokenizer = p.tokenizer.Name()
+	}
+	for i, ef := range extractResult.Files {
+		var sourceTokens, briefTokens int
+		if counting {
+			if sourceTokens, err = p.tokenizer.Count([]byte(ef.Content)); err != nil {
+				return nil, fmt.Errorf("token count failed: %w", err)
+			}
+			briefTokens = briefCosts[i]
+		}
+		dir := topLevelDir(relativeTo(opts.Path, ef.Path))
+		for _, c := range []*Counts{stats.Total, group(stats.Languages, ef.Language), group(stats.Dirs, dir)} {
+			c.add(ef, sourceTokens, briefTokens)
+		}
+	}
+
+	stats.Total.finish()
+	for _, c := range stats.Languages {
+		c.finish()
+	}
+	for _, c := range stats.Dirs {
+		c.finish()
+	}
+	return stats, nil
+}
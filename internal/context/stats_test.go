@@ -0,0 +1,139 @@
+package context
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/indigo-net/Brf.it/pkg/extractor"
+	"github.com/indigo-net/Brf.it/pkg/parser"
+)
+
+func statsFiles() []extractor.ExtractedFile {
+	return []extractor.ExtractedFile{
+		{Path: "/repo/main.go", Language: "go", Content: "package main\n\nfunc main() {}\n", Signatures: []parser.Signature{
+			{Name: "main", Kind: "function", Text: "func main(a, b, c int)"},
+		}},
+		{Path: "/repo/pkg/api/api.go", Language: "go", Content: "package api\n\n// Client talks to the API.\ntype Client struct{}\n\nfunc (c *Client) Do() error { return nil }\n", Signatures: []parser.Signature{
+			{Name: "Client", Kind: "struct", Text: "type Client struct{}", Doc: "Client talks to the API.", Exported: true},
+			{Name: "Do", Kind: "method", Text: "func (c *Client) Do() error", Exported: true},
+		}},
+		{Path: "/repo/web/app.ts", Language: "typescript", Content: "export const version = '1';\n", Signatures: []parser.Signature{
+			{Name: "version", Kind: "variable", Text: "export const version = '1'", Doc: "  ", Exported: true},
+		}},
+		{Path: "/repo/web/broken.ts", Language: "typescript", Error: errors.New("parse failed")},
+	}
+}
+
+func TestPackagerStats(t *testing.T) {
+	p := newBudgetPackager(statsFiles())
+	stats, err := p.Stats(context.Background(), &Options{Path: "/repo", Format: "md", IncludePrivate: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	total := stats.Total
+	if total.Files != 4 || total.Errors != 1 || total.Signatures != 4 {
+		t.Errorf("total files/errors/signatures = %d/%d/%d, want 4/1/4", total.Files, total.Errors, total.Signatures)
+	}
+	wantKinds := map[string]int{"function": 2, "type": 1, "variable": 1}
+	for k, v := range wantKinds {
+		if total.Kinds[k] != v {
+			t.Errorf("kinds[%q] = %d, want %d", k, total.Kinds[k], v)
+		}
+	}
+	if total.Exported != 3 || total.Private != 1 || total.ExportedRatio != 0.75 {
+		t.Errorf("exported/private/ratio = %d/%d/%v, want 3/1/0.75", total.Exported, total.Private, total.ExportedRatio)
+	}
+	// Whitespace-only docs are not counted as documented.
+	if total.Documented != 1 || total.DocCoverage != 0.25 {
+		t.Errorf("documented/coverage = %d/%v, want 1/0.25", total.Documented, total.DocCoverage)
+	}
+
+	if stats.Tokenizer != "words" {
+		t.Errorf("tokenizer = %q, want %q", stats.Tokenizer, "words")
+	}
+	wantSource := 0
+	for _, f := range statsFiles() {
+		n, _ := wordTokenizer{}.Count([]byte(f.Content))
+		wantSource += n
+	}
+	if total.SourceTokens != wantSource {
+		t.Errorf("source tokens = %d, want %d", total.SourceTokens, wantSource)
+	}
+	if total.BriefTokens <= 0 {
+		t.Errorf("expected positive brief tokens, got %d", total.BriefTokens)
+	}
+
+	if got := stats.Languages["typescript"]; got == nil || got.Files != 2 || got.Errors != 1 {
+		t.Errorf("unexpected typescript counts: %+v", got)
+	}
+	if got := stats.Languages["go"]; got == nil || got.Files != 2 || got.Signatures != 3 {
+		t.Errorf("unexpected go counts: %+v", got)
+	}
+
+	wantDirs := map[string]int{".": 1, "pkg": 1, "web": 2}
+	if len(stats.Dirs) != len(wantDirs) {
+		t.Errorf("dirs = %v, want keys of %v", stats.Dirs, wantDirs)
+	}
+	for dir, files := range wantDirs {
+		if got := stats.Dirs[dir]; got == nil || got.Files != files {
+			t.Errorf("dirs[%q] = %+v, want %d files", dir, got, files)
+		}
+	}
+}
+
+func TestPackagerStatsBriefExcludesPrivate(t *testing.T) {
+	withPrivate, err := newBudgetPackager(statsFiles()).Stats(context.Background(), &Options{Path: "/repo", Format: "md", IncludePrivate: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exportedOnly, err := newBudgetPackager(statsFiles()).Stats(context.Background(), &Options{Path: "/repo", Format: "md"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Private symbols are always counted, but only add brief tokens when included.
+	if exportedOnly.Total.Private != 1 {
+		t.Errorf("expected private symbols to be counted, got %d", exportedOnly.Total.Private)
+	}
+	if exportedOnly.Total.BriefTokens >= withPrivate.Total.BriefTokens {
+		t.Errorf("brief tokens without private (%d) should be below with private (%d)",
+			exportedOnly.Total.BriefTokens, withPrivate.Total.BriefTokens)
+	}
+	if exportedOnly.Total.SourceTokens != withPrivate.Total.SourceTokens {
+		t.Errorf("source tokens should not depend on IncludePrivate")
+	}
+}
+
+func TestPackagerStatsWithoutTokenizer(t *testing.T) {
+	p := newBudgetPackager(statsFiles())
+	p.SetTokenizer(nil)
+	stats, err := p.Stats(context.Background(), &Options{Path: "/repo", Format: "md"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Tokenizer != "" || stats.Total.SourceTokens != 0 || stats.Total.BriefTokens != 0 {
+		t.Errorf("expected no token counts, got tokenizer=%q source=%d brief=%d",
+			stats.Tokenizer, stats.Total.SourceTokens, stats.Total.BriefTokens)
+	}
+	if stats.Total.Signatures != 4 {
+		t.Errorf("expected 4 signatures, got %d", stats.Total.Signatures)
+	}
+}
+
+func TestTopLevelDir(t *testing.T) {
+	tests := []struct {
+		rel  string
+		want string
+	}{
+		{"main.go", "."},
+		{"pkg/api/api.go", "pkg"},
+		{"web/app.ts", "web"},
+	}
+	for _, tt := range tests {
+		if got := topLevelDir(tt.rel); got != tt.want {
+			t.Errorf("topLevelDir(%q) = %q, want %q", tt.rel, got, tt.want)
+		}
+	}
+}
@@ -4,6 +4,7 @@ package extractor
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"runtime"
@@ -33,19 +34,33 @@ type ExtractedFile struct {
 	// Calls is the list of function call references.
 	Calls []parser.FunctionCall
 
+	// Summary is the first paragraph of the file's own documentation (see
+	// parser.ParseResult.Summary).
+	Summary string
+
+	// Diagnostics are the syntax errors of the file (see
+	// parser.ParseResult.Diagnostics).
+	Diagnostics []parser.Diagnostic
+
 	// Size is the file size in bytes.
 	Size int64
 
+	// Content is the raw file source, set when ExtractOptions.IncludeContent is true.
+	Content string
+
 	// Error is any error that occurred during extraction.
 	Error error
 }
 
-// ErrorDetail describes a per-file extraction error.
+// ErrorDetail describes a per-file extraction error or syntax errors.
 type ErrorDetail struct {
 	// Path is the file that failed.
 	Path string
 	// Err is the error that occurred.
 	Err error
+	// Syntax indicates that the file was parsed, with syntax errors
+	// summarized by Err.
+	Syntax bool
 }
 
 // ExtractResult contains the results of an extraction operation.
@@ -59,9 +74,14 @@ type ExtractResult struct {
 	// TotalSize is the total size of processed files.
 	TotalSize int64
 
-	// ErrorCount is the number of files that had errors.
+	// ErrorCount is the number of files that had errors, including files
+	// that parsed with syntax errors.
 	ErrorCount int
 
+	// SyntaxErrorCount is the number of files that parsed with syntax
+	// errors.
+	SyntaxErrorCount int
+
 	// ErrorFiles lists files that encountered errors during extraction.
 	// This provides quick access to error details without iterating Files.
 	ErrorFiles []ErrorDetail
@@ -69,9 +89,17 @@ type ExtractResult struct {
 
 // ExtractOptions configures the extraction behavior.
 type ExtractOptions struct {
-	// IncludePrivate whether to include non-exported/private signatures.
+	// IncludePrivate whether to include signatures of every visibility,
+	// overriding Visibility.
 	IncludePrivate bool
 
+	// Visibility lists the visibility levels of the signatures to include.
+	// parser.LanguageDefaultVisibility is used when empty.
+	Visibility []parser.Visibility
+
+	// ExcludeDeprecated whether to leave out deprecated signatures.
+	ExcludeDeprecated bool
+
 	// IncludeBody whether to include function/method bodies.
 	IncludeBody bool
 
@@ -81,6 +109,9 @@ type ExtractOptions struct {
 	// IncludeCalls whether to include function call references.
 	IncludeCalls bool
 
+	// IncludeContent whether to keep the raw file source in ExtractedFile.Content.
+	IncludeContent bool
+
 	// Concurrency is the number of concurrent workers.
 	// 0 = auto (runtime.NumCPU()), 1 = sequential.
 	Concurrency int
@@ -88,6 +119,14 @@ type ExtractOptions struct {
 	// MaxFileSize is the maximum file size in bytes for TOCTOU re-check.
 	// If positive, file content size is verified after reading.
 	MaxFileSize int64
+
+	// ParseTimeout limits the time spent parsing each file. A file that
+	// takes longer fails with a timeout error. 0 means no limit.
+	ParseTimeout time.Duration
+
+	// Queries are the user-supplied query overrides, keyed by language
+	// (see parser.LoadQueries).
+	Queries map[string]*parser.Queries
 }
 
 // Extractor defines the interface for signature extraction.
@@ -189,7 +228,8 @@ func (e *FileExtractor) Extract(ctx context.Context, scanResult *scanner.ScanRes
 	}
 	// Wait for goroutines with context awareness.
 	// If context is cancelled, give in-flight goroutines a grace period
-	// to finish before returning (e.g. CGO parser calls cannot be interrupted).
+	// to finish before returning. Parsers stop at their next progress
+	// check, but a parser that ignores the context may run on.
 	waitDone := make(chan struct{})
 	go func() {
 		wg.Wait()
@@ -220,12 +260,7 @@ func (e *FileExtractor) Extract(ctx context.Context, scanResult *scanner.ScanRes
 	// Aggregate (sequential)
 	result := &ExtractResult{Files: extracted}
 	for _, ef := range extracted {
-		result.TotalSignatures += len(ef.Signatures)
-		result.TotalSize += ef.Size
-		if ef.Error != nil {
-			result.ErrorCount++
-			result.ErrorFiles = append(result.ErrorFiles, ErrorDetail{Path: ef.Path, Err: ef.Error})
-		}
+		result.count(ef)
 	}
 	return result, nil
 }
@@ -239,16 +274,35 @@ func (e *FileExtractor) extractSequential(ctx context.Context, files []scanner.F
 		}
 		extracted := e.extractFile(ctx, fileEntry, opts)
 		result.Files = append(result.Files, extracted)
-		result.TotalSignatures += len(extracted.Signatures)
-		result.TotalSize += extracted.Size
-		if extracted.Error != nil {
-			result.ErrorCount++
-			result.ErrorFiles = append(result.ErrorFiles, ErrorDetail{Path: extracted.Path, Err: extracted.Error})
-		}
+		result.count(extracted)
 	}
 	return result, nil
 }
 
+// count adds the signatures, size and errors of ef to the totals of r.
+func (r *ExtractResult) count(ef ExtractedFile) {
+	r.TotalSignatures += len(ef.Signatures)
+	r.TotalSize += ef.Size
+	switch {
+	case ef.Error != nil:
+		r.ErrorCount++
+		r.ErrorFiles = append(r.ErrorFiles, ErrorDetail{Path: ef.Path, Err: ef.Error})
+	case len(ef.Diagnostics) > 0:
+		r.ErrorCount++
+		r.SyntaxErrorCount++
+		r.ErrorFiles = append(r.ErrorFiles, ErrorDetail{Path: ef.Path, Err: syntaxError(ef.Diagnostics), Syntax: true})
+	}
+}
+
+// syntaxError summarizes the syntax errors of a file.
+func syntaxError(diags []parser.Diagnostic) error {
+	first := diags[0].Message + " at " + diags[0].Location()
+	if len(diags) == 1 {
+		return fmt.Errorf("%s", first)
+	}
+	return fmt.Errorf("%d syntax errors, first: %s", len(diags), first)
+}
+
 // binarySniffSize is the number of bytes inspected for NUL to detect binary content.
 const binarySniffSize = 512
 
@@ -322,14 +376,33 @@ func (e *FileExtractor) extractFile(ctx context.Context, entry scanner.FileEntry
 		return extracted
 	}
 
+	if opts.IncludeContent {
+		extracted.Content = string(content)
+	}
+
 	// Parse content (no string conversion needed)
+	parseCtx := ctx
+	if opts.ParseTimeout > 0 {
+		var cancel context.CancelFunc
+		parseCtx, cancel = context.WithTimeout(ctx, opts.ParseTimeout)
+		defer cancel()
+	}
 	parseResult, err := p.Parse(content, &parser.Options{
-		Language:       entry.Language,
-		IncludePrivate: opts.IncludePrivate,
-		IncludeBody:    opts.IncludeBody,
-		IncludeImports: opts.IncludeImports,
-		IncludeCalls:   opts.IncludeCalls,
+		Language:          entry.Language,
+		IncludePrivate:    opts.IncludePrivate,
+		Visibility:        opts.Visibility,
+		ExcludeDeprecated: opts.ExcludeDeprecated,
+		IncludeBody:       opts.IncludeBody,
+		IncludeImports:    opts.IncludeImports,
+		IncludeCalls:      opts.IncludeCalls,
+		Module:            moduleFor(entry.Language, entry.Path),
+		Context:           parseCtx,
+		Queries:           opts.Queries[entry.Language],
 	})
+	if err != nil && ctx.Err() == nil && errors.Is(err, context.DeadlineExceeded) {
+		extracted.Error = fmt.Errorf("parsing %q timed out after %s", entry.Path, opts.ParseTimeout)
+		return extracted
+	}
 	if err != nil {
 		extracted.Error = fmt.Errorf("failed to parse %q: %w", entry.Path, err)
 		return extracted
@@ -338,5 +411,7 @@ func (e *FileExtractor) extractFile(ctx context.Context, entry scanner.FileEntry
 	extracted.Signatures = parseResult.Signatures
 	extracted.RawImports = parseResult.RawImports
 	extracted.Calls = parseResult.Calls
+	extracted.Summary = parseResult.Summary
+	extracted.Diagnostics = parseResult.Diagnostics
 	return extracted
 }
@@ -164,6 +164,33 @@ func TestFileExtractorTOCTOUGuardDisabled(t *testing.T) {
 	}
 }
 
+func TestFileExtractorIncludeContent(t *testing.T) {
+	testCode := "package test\n\nfunc Foo() {}\n"
+	scanResult := &scanner.ScanResult{
+		Files: []scanner.FileEntry{
+			{Path: "test.go", Language: "go", Size: int64(len(testCode)), Content: []byte(testCode)},
+		},
+	}
+
+	extractor := NewDefaultFileExtractor()
+	for _, include := range []bool{false, true} {
+		result, err := extractor.Extract(context.Background(), scanResult, &ExtractOptions{IncludeContent: include})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := ""
+		if include {
+			want = testCode
+		}
+		if got := result.Files[0].Content; got != want {
+			t.Errorf("IncludeContent=%v: Content = %q, want %q", include, got, want)
+		}
+		if len(result.Files[0].Signatures) != 1 {
+			t.Errorf("IncludeContent=%v: expected 1 signature, got %d", include, len(result.Files[0].Signatures))
+		}
+	}
+}
+
 func TestExtractConcurrencySequential(t *testing.T) {
 	tmpDir, err := os.MkdirTemp("", "brfit-test-*")
 	if err != nil {
@@ -341,6 +368,89 @@ func TestExtractConcurrencyWithErrors(t *testing.T) {
 	}
 }
 
+func TestExtractSyntaxErrors(t *testing.T) {
+	tmpDir := t.TempDir()
+	validCode := "package test\n\nfunc Valid() {}\n"
+	brokenCode := "package test\n\nfunc Broken() {\n\tx := )\n}\n\nfunc After() {}\n"
+	validFile := filepath.Join(tmpDir, "valid.go")
+	brokenFile := filepath.Join(tmpDir, "broken.go")
+	if err := os.WriteFile(validFile, []byte(validCode), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(brokenFile, []byte(brokenCode), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	scanResult := &scanner.ScanResult{
+		Files: []scanner.FileEntry{
+			{Path: validFile, Language: "go", Size: int64(len(validCode))},
+			{Path: brokenFile, Language: "go", Size: int64(len(brokenCode))},
+			{Path: filepath.Join(tmpDir, "missing.go"), Language: "go", Size: 100},
+		},
+	}
+
+	for _, concurrency := range []int{1, 2} {
+		t.Run(fmt.Sprintf("concurrency=%d", concurrency), func(t *testing.T) {
+			result, err := NewDefaultFileExtractor().Extract(context.Background(), scanResult, &ExtractOptions{Concurrency: concurrency})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.ErrorCount != 2 || result.SyntaxErrorCount != 1 {
+				t.Errorf("expected 2 errors, 1 syntax, got %d, %d", result.ErrorCount, result.SyntaxErrorCount)
+			}
+			broken := result.Files[1]
+			if broken.Error != nil || len(broken.Diagnostics) != 1 {
+				t.Fatalf("expected one diagnostic and no error, got %v, %v", broken.Diagnostics, broken.Error)
+			}
+			if len(broken.Signatures) == 0 {
+				t.Error("expected signatures of the broken file to be kept")
+			}
+			if len(result.ErrorFiles) != 2 {
+				t.Fatalf("expected 2 error files, got %d", len(result.ErrorFiles))
+			}
+			detail := result.ErrorFiles[0]
+			if detail.Path != brokenFile || !detail.Syntax || detail.Err.Error() != "syntax error at 4:2-4:8" {
+				t.Errorf("unexpected syntax error detail: %+v", detail)
+			}
+			if result.ErrorFiles[1].Syntax {
+				t.Error("missing file should not be reported as a syntax error")
+			}
+		})
+	}
+}
+
+func TestExtractParseTimeout(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "slow.go")
+	code := "package test\n\nfunc Slow() {}\n"
+	if err := os.WriteFile(path, []byte(code), 0644); err != nil {
+		t.Fatal(err)
+	}
+	scanResult := &scanner.ScanResult{
+		Files: []scanner.FileEntry{{Path: path, Language: "go", Size: int64(len(code))}},
+	}
+
+	// A timeout too short for any parse fails the file, not the run
+	result, err := NewDefaultFileExtractor().Extract(context.Background(), scanResult, &ExtractOptions{ParseTimeout: time.Nanosecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ErrorCount != 1 {
+		t.Fatalf("expected 1 error, got %d", result.ErrorCount)
+	}
+	want := fmt.Sprintf("parsing %q timed out after 1ns", path)
+	if err := result.Files[0].Error; err == nil || err.Error() != want {
+		t.Errorf("expected error %q, got %v", want, err)
+	}
+
+	result, err = NewDefaultFileExtractor().Extract(context.Background(), scanResult, &ExtractOptions{ParseTimeout: time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ErrorCount != 0 || len(result.Files[0].Signatures) != 1 {
+		t.Errorf("expected the file to parse within the timeout, got %+v", result.Files[0])
+	}
+}
+
 func TestFileExtractorUnsupportedLanguage(t *testing.T) {
 	// Create registry without any parsers
 	registry := parser.NewRegistry()
@@ -0,0 +1,80 @@
+package extractor
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// moduleFor returns the module path of the file at path for languages whose
+// qualified names derive from the file layout rather than from a declaration
+// in the source: Python packages and Rust crate modules.
+func moduleFor(language, path string) string {
+	if path == "" {
+		return ""
+	}
+	switch language {
+	case "python":
+		return pythonModule(path)
+	case "rust":
+		return rustModule(path)
+	}
+	return ""
+}
+
+// pythonModule returns the dotted module path of a Python file. Enclosing
+// directories are part of the path as long as they contain __init__.py
+// (e.g., "pkg/sub/mod.py" → "pkg.sub.mod", "pkg/__init__.py" → "pkg").
+func pythonModule(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	var parts []string
+	if name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)); name != "__init__" {
+		parts = append(parts, name)
+	}
+	for dir := filepath.Dir(path); ; {
+		if _, err := os.Stat(filepath.Join(dir, "__init__.py")); err != nil {
+			break
+		}
+		parts = append(parts, filepath.Base(dir))
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	slices.Reverse(parts)
+	return strings.Join(parts, ".")
+}
+
+// rustModule returns the module path of a Rust file from its location below
+// the crate's src directory (e.g., "src/fs/read.rs" → "crate::fs::read",
+// "src/fs/mod.rs" → "crate::fs"). Crate roots (lib.rs, main.rs, src/bin/*
+// and files outside src such as tests) are "crate".
+func rustModule(path string) string {
+	parts := strings.Split(filepath.ToSlash(path), "/")
+	src := -1
+	for i := len(parts) - 2; i >= 0; i-- {
+		if parts[i] == "src" {
+			src = i
+			break
+		}
+	}
+	if src < 0 {
+		return "crate"
+	}
+	mods := slices.Clone(parts[src+1:])
+	last := strings.TrimSuffix(mods[len(mods)-1], ".rs")
+	mods[len(mods)-1] = last
+	switch {
+	case mods[0] == "bin":
+		return "crate"
+	case len(mods) == 1 && (last == "lib" || last == "main"):
+		return "crate"
+	case last == "mod":
+		mods = mods[:len(mods)-1]
+	}
+	return "crate::" + strings.Join(mods, "::")
+}
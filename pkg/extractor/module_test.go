@@ -0,0 +1,64 @@
+package extractor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPythonModule(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"pkg/__init__.py", "pkg/sub/__init__.py", "pkg/sub/mod.py", "script.py"} {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"pkg/sub/mod.py", "pkg.sub.mod"},
+		{"pkg/sub/__init__.py", "pkg.sub"},
+		{"pkg/__init__.py", "pkg"},
+		{"script.py", "script"},
+	}
+	for _, tt := range tests {
+		if got := moduleFor("python", filepath.Join(root, filepath.FromSlash(tt.path))); got != tt.want {
+			t.Errorf("pythonModule(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestRustModule(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/work/crate/src/lib.rs", "crate"},
+		{"/work/crate/src/main.rs", "crate"},
+		{"/work/crate/src/bin/tool.rs", "crate"},
+		{"/work/crate/src/fs.rs", "crate::fs"},
+		{"/work/crate/src/fs/read.rs", "crate::fs::read"},
+		{"/work/crate/src/fs/mod.rs", "crate::fs"},
+		{"/work/crate/tests/it.rs", "crate"},
+	}
+	for _, tt := range tests {
+		if got := moduleFor("rust", filepath.FromSlash(tt.path)); got != tt.want {
+			t.Errorf("rustModule(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestModuleForOtherLanguages(t *testing.T) {
+	if got := moduleFor("go", "/work/main.go"); got != "" {
+		t.Errorf("moduleFor(go) = %q, want empty", got)
+	}
+	if got := moduleFor("python", ""); got != "" {
+		t.Errorf("moduleFor(python, \"\") = %q, want empty", got)
+	}
+}
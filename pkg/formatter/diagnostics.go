@@ -0,0 +1,62 @@
+package formatter
+
+import (
+	"bytes"
+)
+
+// jsonDiagnostic represents a syntax error in the JSON output.
+type jsonDiagnostic struct {
+	Message   string `json:"message"`
+	Line      int    `json:"line"`
+	Column    int    `json:"column"`
+	EndLine   int    `json:"endLine"`
+	EndColumn int    `json:"endColumn"`
+}
+
+// jsonDiagnostics converts the syntax errors of file to their JSON form.
+func jsonDiagnostics(file FileData) []jsonDiagnostic {
+	if file.Error != nil || len(file.Diagnostics) == 0 {
+		return nil
+	}
+	out := make([]jsonDiagnostic, len(file.Diagnostics))
+	for i, d := range file.Diagnostics {
+		out[i] = jsonDiagnostic{
+			Message:   d.Message,
+			Line:      d.Line,
+			Column:    d.Column,
+			EndLine:   d.EndLine,
+			EndColumn: d.EndColumn,
+		}
+	}
+	return out
+}
+
+// writeXMLDiagnostics writes a <diagnostic> element per syntax error of file.
+func writeXMLDiagnostics(buf *bytes.Buffer, file FileData) {
+	if file.Error != nil {
+		return
+	}
+	for _, d := range file.Diagnostics {
+		buf.WriteString("      <diagnostic")
+		writeXMLAttr(buf, "loc", d.Location())
+		buf.WriteByte('>')
+		buf.WriteString(escapeXML(d.Message))
+		buf.WriteString("</diagnostic>\n")
+	}
+}
+
+// writeMarkdownDiagnostics writes the syntax errors of file as a quoted list.
+func writeMarkdownDiagnostics(buf *bytes.Buffer, file FileData) {
+	if file.Error != nil || len(file.Diagnostics) == 0 {
+		return
+	}
+	buf.WriteString("> **Syntax errors** (signatures may be missing):\n")
+	for _, d := range file.Diagnostics {
+		buf.WriteString("> - ")
+		buf.WriteString(d.Location())
+		buf.WriteString(": ")
+		buf.WriteString(escapeMarkdown(d.Message))
+		buf.WriteString("\n")
+	}
+	buf.WriteString("\n")
+}
@@ -0,0 +1,60 @@
+package formatter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/indigo-net/Brf.it/pkg/parser"
+)
+
+func TestFormatDiagnostics(t *testing.T) {
+	diags := []parser.Diagnostic{
+		{Message: "syntax error", Line: 4, Column: 2, EndLine: 4, EndColumn: 8},
+		{Message: `missing ")"`, Line: 9, Column: 14, EndLine: 9, EndColumn: 14},
+	}
+	tests := []struct {
+		formatter Formatter
+		mode      string
+		want      []string
+	}{
+		{NewXMLFormatter(), ModeSig, []string{
+			`<diagnostic loc="4:2-4:8">syntax error</diagnostic>`,
+			`<diagnostic loc="9:14-9:14">missing &quot;)&quot;</diagnostic>`,
+			`<file path="broken.go"`,
+		}},
+		{NewXMLFormatter(), ModeOutline, []string{`<diagnostic loc="4:2-4:8">syntax error</diagnostic>`}},
+		{NewMarkdownFormatter(), ModeSig, []string{
+			"### broken.go\n\n> **Syntax errors** (signatures may be missing):\n> - 4:2-4:8: syntax error\n> - 9:14-9:14: missing \")\"\n",
+		}},
+		{NewJSONFormatter(), ModeSig, []string{
+			`"diagnostics":[{"message":"syntax error","line":4,"column":2,"endLine":4,"endColumn":8}`,
+		}},
+		{NewJSONFormatter(), ModeDocs, []string{`"diagnostics":[{"message":"syntax error"`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.formatter.Name()+"/"+tt.mode, func(t *testing.T) {
+			// A file whose signatures were all lost is still reported
+			data := &PackageData{
+				Mode:      tt.mode,
+				SkipEmpty: true,
+				Files: []FileData{
+					{Path: "broken.go", Language: "go", Diagnostics: diags},
+					{Path: "empty.go", Language: "go"},
+				},
+			}
+			out, err := tt.formatter.Format(data)
+			if err != nil {
+				t.Fatalf("Format: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(out), want) {
+					t.Errorf("output missing %q:\n%s", want, out)
+				}
+			}
+			if strings.Contains(string(out), "empty.go") {
+				t.Errorf("empty file not skipped:\n%s", out)
+			}
+		})
+	}
+}
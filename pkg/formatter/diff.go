@@ -0,0 +1,324 @@
+package formatter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/indigo-net/Brf.it/pkg/parser"
+)
+
+// Change types reported for a signature in a diff.
+const (
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+	ChangeChanged = "changed"
+)
+
+// SignatureChange describes a single signature that differs between two revisions.
+type SignatureChange struct {
+	// Type is one of ChangeAdded, ChangeRemoved or ChangeChanged.
+	Type string
+
+	// Name is the identifier name the signatures were matched by.
+	Name string
+
+	// Kind is the signature kind the signatures were matched by.
+	Kind string
+
+	// Old is the signature in the old revision (nil when added).
+	Old *parser.Signature
+
+	// New is the signature in the new revision (nil when removed).
+	New *parser.Signature
+}
+
+// FileDiff lists the signature changes of a single file.
+type FileDiff struct {
+	// Path is the file path relative to the compared root.
+	Path string
+
+	// Language is the detected language.
+	Language string
+
+	// Changes is the list of signature changes in source order.
+	Changes []SignatureChange
+}
+
+// DiffData contains all data needed for formatting a signature diff.
+type DiffData struct {
+	// RootPath is the path being compared.
+	RootPath string
+
+	// Version is the brf.it version string.
+	Version string
+
+	// OldRef is the old revision (e.g., "v1.2.0").
+	OldRef string
+
+	// NewRef is the new revision (e.g., "HEAD", or "working tree").
+	NewRef string
+
+	// Files is the list of files with at least one change.
+	Files []FileDiff
+
+	// Added, Removed and Changed are the total counts per change type.
+	Added, Removed, Changed int
+
+	// MaxDocLength is the maximum length of documentation comments.
+	// 0 means no limit (default).
+	MaxDocLength int
+}
+
+// DiffFormatter formats a signature diff.
+type DiffFormatter interface {
+	// FormatDiff converts the diff data to formatted output.
+	FormatDiff(data *DiffData) ([]byte, error)
+}
+
+// FormatDiff implements DiffFormatter for XML output.
+func (f *XMLFormatter) FormatDiff(data *DiffData) ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8"?>`)
+	buf.WriteByte('\n')
+	buf.WriteString("<brfit-diff")
+	writeXMLAttr(&buf, "old", data.OldRef)
+	writeXMLAttr(&buf, "new", data.NewRef)
+	buf.WriteString(">\n")
+
+	buf.WriteString("  <metadata>\n")
+	if data.Version != "" {
+		buf.WriteString("    <version>")
+		buf.WriteString(escapeXML(data.Version))
+		buf.WriteString("</version>\n")
+	}
+	if data.RootPath != "" {
+		buf.WriteString("    <path>")
+		buf.WriteString(escapeXML(data.RootPath))
+		buf.WriteString("</path>\n")
+	}
+	buf.WriteString("    <summary")
+	writeXMLAttr(&buf, "added", strconv.Itoa(data.Added))
+	writeXMLAttr(&buf, "removed", strconv.Itoa(data.Removed))
+	writeXMLAttr(&buf, "changed", strconv.Itoa(data.Changed))
+	buf.WriteString(" />\n")
+	buf.WriteString("  </metadata>\n")
+
+	buf.WriteString("  <files>\n")
+	for _, file := range data.Files {
+		buf.WriteString("    <file")
+		writeXMLAttr(&buf, "path", file.Path)
+		writeXMLAttr(&buf, "language", file.Language)
+		buf.WriteString(">\n")
+
+		for _, ch := range file.Changes {
+			buf.WriteString("      <")
+			buf.WriteString(ch.Type)
+			writeXMLAttr(&buf, "name", ch.Name)
+			writeXMLAttr(&buf, "kind", NormalizeKind(ch.Kind))
+			buf.WriteString(">\n")
+			if ch.Old != nil {
+				writeXMLDiffSig(&buf, "old", ch.Old, data.MaxDocLength)
+			}
+			if ch.New != nil {
+				writeXMLDiffSig(&buf, "new", ch.New, data.MaxDocLength)
+			}
+			buf.WriteString("      </")
+			buf.WriteString(ch.Type)
+			buf.WriteString(">\n")
+		}
+
+		buf.WriteString("    </file>\n")
+	}
+	buf.WriteString("  </files>\n")
+	buf.WriteString("</brfit-diff>\n")
+
+	return buf.Bytes(), nil
+}
+
+// writeXMLAttr writes ` name="value"` with the value escaped.
+func writeXMLAttr(buf *bytes.Buffer, name, value string) {
+	buf.WriteByte(' ')
+	buf.WriteString(name)
+	buf.WriteString("=\"")
+	buf.WriteString(escapeXML(value))
+	buf.WriteByte('"')
+}
+
+// writeXMLDiffSig writes one side of a signature change as an <old> or <new> element.
+func writeXMLDiffSig(buf *bytes.Buffer, tag string, sig *parser.Signature, maxDocLength int) {
+	buf.WriteString("        <")
+	buf.WriteString(tag)
+	if sig.Line > 0 {
+		writeXMLAttr(buf, "line", strconv.Itoa(sig.Line))
+	}
+	buf.WriteByte('>')
+	buf.WriteString(escapeXML(sig.Text))
+	buf.WriteString("</")
+	buf.WriteString(tag)
+	buf.WriteString(">\n")
+	for _, dec := range sig.Decorators {
+		buf.WriteString("        <decorator>")
+		buf.WriteString(escapeXML(dec.Text))
+		buf.WriteString("</decorator>\n")
+	}
+	if sig.Doc != "" {
+		buf.WriteString("        <doc>")
+		buf.WriteString(escapeXML(TruncateDoc(sig.Doc, maxDocLength)))
+		buf.WriteString("</doc>\n")
+	}
+}
+
+// FormatDiff implements DiffFormatter for Markdown output.
+// Each file is rendered as a fenced diff block so reviewers get +/- highlighting.
+func (f *MarkdownFormatter) FormatDiff(data *DiffData) ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteString("# API Diff: ")
+	buf.WriteString(data.OldRef)
+	buf.WriteString(" → ")
+	buf.WriteString(data.NewRef)
+	buf.WriteString("\n\n")
+
+	if data.RootPath != "" {
+		buf.WriteString("*")
+		buf.WriteString(data.RootPath)
+		if data.Version != "" {
+			buf.WriteString(" · brf.it ")
+			buf.WriteString(data.Version)
+		}
+		buf.WriteString("*\n\n")
+	}
+
+	fmt.Fprintf(&buf, "**%d added, %d removed, %d changed**\n\n", data.Added, data.Removed, data.Changed)
+
+	if len(data.Files) == 0 {
+		buf.WriteString("No signature changes.\n")
+		return buf.Bytes(), nil
+	}
+
+	buf.WriteString("## Files\n\n")
+	for _, file := range data.Files {
+		buf.WriteString("### ")
+		buf.WriteString(file.Path)
+		buf.WriteString("\n\n")
+		buf.WriteString("```diff\n")
+		for _, ch := range file.Changes {
+			if ch.Old != nil {
+				writeDiffSig(&buf, "- ", ch.Old)
+			}
+			if ch.New != nil {
+				writeDiffSig(&buf, "+ ", ch.New)
+			}
+		}
+		buf.WriteString("```\n\n")
+	}
+
+	return buf.Bytes(), nil
+}
+
+// writeDiffSig writes the decorators and text of sig as prefixed diff lines.
+func writeDiffSig(buf *bytes.Buffer, prefix string, sig *parser.Signature) {
+	for _, dec := range sig.Decorators {
+		writeDiffLines(buf, prefix, dec.Text)
+	}
+	writeDiffLines(buf, prefix, sig.Text)
+}
+
+// writeDiffLines writes text with prefix prepended to every line.
+func writeDiffLines(buf *bytes.Buffer, prefix, text string) {
+	for _, line := range bytes.Split([]byte(text), []byte("\n")) {
+		buf.WriteString(prefix)
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+}
+
+// jsonDiffOutput represents the top-level JSON diff structure.
+type jsonDiffOutput struct {
+	Version string         `json:"version,omitempty"`
+	Path    string         `json:"path,omitempty"`
+	Old     string         `json:"old"`
+	New     string         `json:"new"`
+	Summary jsonDiffCounts `json:"summary"`
+	Files   []jsonDiffFile `json:"files"`
+}
+
+// jsonDiffCounts holds the change totals in the JSON diff output.
+type jsonDiffCounts struct {
+	Added   int `json:"added"`
+	Removed int `json:"removed"`
+	Changed int `json:"changed"`
+}
+
+// jsonDiffFile represents a single file in the JSON diff output.
+type jsonDiffFile struct {
+	Path     string           `json:"path"`
+	Language string           `json:"language"`
+	Changes  []jsonDiffChange `json:"changes"`
+}
+
+// jsonDiffChange represents a single signature change in the JSON diff output.
+type jsonDiffChange struct {
+	Change string   `json:"change"`
+	Name   string   `json:"name"`
+	Kind   string   `json:"kind"`
+	Old    *jsonSig `json:"old,omitempty"`
+	New    *jsonSig `json:"new,omitempty"`
+}
+
+// FormatDiff implements DiffFormatter for JSON output.
+func (f *JSONFormatter) FormatDiff(data *DiffData) ([]byte, error) {
+	output := jsonDiffOutput{
+		Version: data.Version,
+		Path:    data.RootPath,
+		Old:     data.OldRef,
+		New:     data.NewRef,
+		Summary: jsonDiffCounts{
+			Added:   data.Added,
+			Removed: data.Removed,
+			Changed: data.Changed,
+		},
+		Files: make([]jsonDiffFile, 0, len(data.Files)),
+	}
+
+	for _, file := range data.Files {
+		jf := jsonDiffFile{
+			Path:     file.Path,
+			Language: file.Language,
+			Changes:  make([]jsonDiffChange, 0, len(file.Changes)),
+		}
+		for _, ch := range file.Changes {
+			jf.Changes = append(jf.Changes, jsonDiffChange{
+				Change: ch.Type,
+				Name:   ch.Name,
+				Kind:   NormalizeKind(ch.Kind),
+				Old:    toJSONSig(ch.Old, data.MaxDocLength),
+				New:    toJSONSig(ch.New, data.MaxDocLength),
+			})
+		}
+		output.Files = append(output.Files, jf)
+	}
+
+	return json.Marshal(output)
+}
+
+// toJSONSig converts a signature to its JSON representation (nil-safe).
+func toJSONSig(sig *parser.Signature, maxDocLength int) *jsonSig {
+	if sig == nil {
+		return nil
+	}
+	js := &jsonSig{
+		Kind:       NormalizeKind(sig.Kind),
+		Text:       sig.Text,
+		Line:       sig.Line,
+		Exported:   sig.Exported,
+		Decorators: jsonDecorators(sig.Decorators),
+	}
+	if sig.Doc != "" {
+		js.Doc = TruncateDoc(sig.Doc, maxDocLength)
+	}
+	return js
+}
@@ -0,0 +1,126 @@
+package formatter
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/indigo-net/Brf.it/pkg/parser"
+)
+
+func sampleDiffData() *DiffData {
+	return &DiffData{
+		RootPath: "/repo",
+		Version:  "1.0.0",
+		OldRef:   "v1.2.0",
+		NewRef:   "HEAD",
+		Files: []FileDiff{
+			{
+				Path:     "api.go",
+				Language: "go",
+				Changes: []SignatureChange{
+					{
+						Type: ChangeChanged, Name: "Less", Kind: "function",
+						Old: &parser.Signature{Text: "func Less(a, b int) bool", Line: 3},
+						New: &parser.Signature{Text: "func Less[T cmp.Ordered](a, b T) bool", Line: 3, Doc: "Less reports a < b."},
+					},
+					{Type: ChangeAdded, Name: "New", Kind: "struct", New: &parser.Signature{Text: "type New struct{}", Line: 9}},
+					{Type: ChangeRemoved, Name: "Old", Kind: "method", Old: &parser.Signature{Text: "func (r *R) Old()", Line: 12}},
+				},
+			},
+		},
+		Added:   1,
+		Removed: 1,
+		Changed: 1,
+	}
+}
+
+func TestDiffFormattersImplementDiffFormatter(t *testing.T) {
+	var _ DiffFormatter = NewXMLFormatter()
+	var _ DiffFormatter = NewMarkdownFormatter()
+	var _ DiffFormatter = NewJSONFormatter()
+}
+
+func TestXMLFormatterFormatDiff(t *testing.T) {
+	out, err := NewXMLFormatter().FormatDiff(sampleDiffData())
+	if err != nil {
+		t.Fatalf("FormatDiff failed: %v", err)
+	}
+	content := string(out)
+
+	if err := xml.Unmarshal(out, new(struct{})); err != nil {
+		t.Fatalf("output is not well-formed XML: %v\n%s", err, content)
+	}
+	for _, want := range []string{
+		`<brfit-diff old="v1.2.0" new="HEAD">`,
+		`<summary added="1" removed="1" changed="1" />`,
+		`<changed name="Less" kind="function">`,
+		`<old line="3">func Less(a, b int) bool</old>`,
+		`<doc>Less reports a &lt; b.</doc>`,
+		`<added name="New" kind="type">`,
+		`<removed name="Old" kind="function">`,
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("expected XML to contain %q:\n%s", want, content)
+		}
+	}
+}
+
+func TestMarkdownFormatterFormatDiff(t *testing.T) {
+	out, err := NewMarkdownFormatter().FormatDiff(sampleDiffData())
+	if err != nil {
+		t.Fatalf("FormatDiff failed: %v", err)
+	}
+	content := string(out)
+
+	for _, want := range []string{
+		"# API Diff: v1.2.0 → HEAD",
+		"**1 added, 1 removed, 1 changed**",
+		"### api.go",
+		"```diff\n- func Less(a, b int) bool\n+ func Less[T cmp.Ordered](a, b T) bool\n+ type New struct{}\n- func (r *R) Old()\n```",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("expected Markdown to contain %q:\n%s", want, content)
+		}
+	}
+
+	empty, err := NewMarkdownFormatter().FormatDiff(&DiffData{OldRef: "a", NewRef: "b"})
+	if err != nil {
+		t.Fatalf("FormatDiff failed: %v", err)
+	}
+	if !strings.Contains(string(empty), "No signature changes.") {
+		t.Errorf("expected empty diff message, got:\n%s", empty)
+	}
+}
+
+func TestJSONFormatterFormatDiff(t *testing.T) {
+	out, err := NewJSONFormatter().FormatDiff(sampleDiffData())
+	if err != nil {
+		t.Fatalf("FormatDiff failed: %v", err)
+	}
+
+	var parsed jsonDiffOutput
+	if err := json.Unmarshal(out, &parsed); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if parsed.Old != "v1.2.0" || parsed.New != "HEAD" {
+		t.Errorf("unexpected refs: %+v", parsed)
+	}
+	if parsed.Summary != (jsonDiffCounts{Added: 1, Removed: 1, Changed: 1}) {
+		t.Errorf("unexpected summary: %+v", parsed.Summary)
+	}
+	changes := parsed.Files[0].Changes
+	if len(changes) != 3 {
+		t.Fatalf("expected 3 changes, got %d", len(changes))
+	}
+	if changes[0].Old == nil || changes[0].New == nil || changes[0].New.Doc != "Less reports a < b." {
+		t.Errorf("unexpected changed entry: %+v", changes[0])
+	}
+	if changes[1].Old != nil || changes[1].Kind != "type" {
+		t.Errorf("unexpected added entry: %+v", changes[1])
+	}
+	if changes[2].New != nil || changes[2].Change != ChangeRemoved {
+		t.Errorf("unexpected removed entry: %+v", changes[2])
+	}
+}
@@ -0,0 +1,83 @@
+package formatter
+
+import (
+	"strings"
+
+	"github.com/indigo-net/Brf.it/pkg/parser"
+)
+
+// Doc styles select how much of each documentation comment is rendered.
+const (
+	// DocStyleFull renders the whole doc as plain text.
+	DocStyleFull = "full"
+
+	// DocStyleSummary renders only the first sentence of the description.
+	DocStyleSummary = "summary-line"
+
+	// DocStyleParams renders the summary line followed by the documented
+	// parameters, return value, exceptions and deprecation, one per line.
+	DocStyleParams = "params-only"
+)
+
+// DocStyles lists the supported doc styles.
+var DocStyles = []string{DocStyleFull, DocStyleSummary, DocStyleParams}
+
+// FormatDoc renders a documentation comment in the given style. Javadoc
+// HTML and inline tags are converted to plain text. An empty or unknown
+// style renders the full doc.
+func FormatDoc(doc, style string) string {
+	if doc == "" {
+		return ""
+	}
+	doc = parser.DocPlainText(doc)
+	switch style {
+	case DocStyleSummary:
+		return parser.ParseDoc(doc).Summary()
+	case DocStyleParams:
+		return formatDocParams(parser.ParseDoc(doc))
+	}
+	return doc
+}
+
+// formatDocParams renders the summary line of d and its tags in JSDoc form
+// (e.g., "@param name description").
+func formatDocParams(d parser.DocComment) string {
+	var lines []string
+	if s := d.Summary(); s != "" {
+		lines = append(lines, s)
+	}
+	for _, p := range d.Params {
+		lines = append(lines, docTagLine("@param", p))
+	}
+	if d.Returns != "" {
+		lines = append(lines, "@returns "+d.Returns)
+	}
+	for _, p := range d.Throws {
+		lines = append(lines, docTagLine("@throws", p))
+	}
+	if d.Deprecated {
+		lines = append(lines, strings.TrimSpace("@deprecated "+d.DeprecationNote))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// docTagLine renders a parameter or exception as a tag line.
+func docTagLine(tag string, p parser.DocParam) string {
+	line := tag
+	if p.Type != "" {
+		line += " {" + p.Type + "}"
+	}
+	if p.Name != "" {
+		line += " " + p.Name
+	}
+	if p.Description != "" {
+		line += " " + p.Description
+	}
+	return line
+}
+
+// docText returns the doc of a signature as rendered for data: in its doc
+// style and truncated to its maximum doc length.
+func docText(data *PackageData, doc string) string {
+	return TruncateDoc(FormatDoc(doc, data.DocStyle), data.MaxDocLength)
+}
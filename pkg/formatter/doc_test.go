@@ -0,0 +1,86 @@
+package formatter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/indigo-net/Brf.it/pkg/parser"
+)
+
+func TestFormatDoc(t *testing.T) {
+	jsdoc := "Fetches a user. Results are cached.\n" +
+		"@param {string} id - The user ID.\n" +
+		"@returns The user.\n" +
+		"@throws {NotFoundError} When missing.\n" +
+		"@deprecated Use getUser instead.\n" +
+		"@example\nfetchUser(\"42\")"
+
+	tests := []struct {
+		name  string
+		doc   string
+		style string
+		want  string
+	}{
+		{"empty", "", DocStyleSummary, ""},
+		{"full", jsdoc, DocStyleFull, jsdoc},
+		{"default is full", jsdoc, "", jsdoc},
+		{"summary", jsdoc, DocStyleSummary, "Fetches a user."},
+		{
+			"params",
+			jsdoc,
+			DocStyleParams,
+			"Fetches a user.\n" +
+				"@param {string} id The user ID.\n" +
+				"@returns The user.\n" +
+				"@throws NotFoundError When missing.\n" +
+				"@deprecated Use getUser instead.",
+		},
+		{"javadoc html", "Returns the <b>first</b> {@code Item}.<p>Never null.", DocStyleFull, "Returns the first Item.\n\nNever null."},
+		{"javadoc summary", "Returns the <b>first</b> {@code Item}.<p>Never null.", DocStyleSummary, "Returns the first Item."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatDoc(tt.doc, tt.style); got != tt.want {
+				t.Errorf("FormatDoc(%q) =\n%s\nwant\n%s", tt.style, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDocStyleOutput(t *testing.T) {
+	data := &PackageData{
+		Files: []FileData{{
+			Path:     "user.ts",
+			Language: "typescript",
+			Signatures: []parser.Signature{{
+				Name: "fetchUser",
+				Kind: "function",
+				Text: "function fetchUser(id: string): User",
+				Doc:  "Fetches a user. Results are cached.\n@param id The user ID.",
+			}},
+		}},
+		IncludeStructure: true,
+		DocStyle:         DocStyleSummary,
+	}
+
+	formatters := map[string]Formatter{
+		"xml":      NewXMLFormatter(),
+		"markdown": NewMarkdownFormatter(),
+		"json":     NewJSONFormatter(),
+	}
+	for name, f := range formatters {
+		t.Run(name, func(t *testing.T) {
+			out, err := f.Format(data)
+			if err != nil {
+				t.Fatalf("Format: %v", err)
+			}
+			if !strings.Contains(string(out), "Fetches a user.") {
+				t.Errorf("output missing summary line:\n%s", out)
+			}
+			if strings.Contains(string(out), "Results are cached") || strings.Contains(string(out), "@param") {
+				t.Errorf("summary-line output kept the rest of the doc:\n%s", out)
+			}
+		})
+	}
+}
@@ -22,6 +22,17 @@ type FileData struct {
 	// Calls is the list of function call references.
 	Calls []parser.FunctionCall
 
+	// Summary is the first paragraph of the file's own documentation (see
+	// parser.ParseResult.Summary), rendered ahead of its signatures.
+	Summary string
+
+	// Diagnostics are the syntax errors of the file (see
+	// parser.ParseResult.Diagnostics), rendered ahead of its signatures.
+	Diagnostics []parser.Diagnostic
+
+	// Content is the raw file source, set in full mode.
+	Content string
+
 	// Error is any error that occurred during extraction.
 	Error error
 }
@@ -34,6 +45,10 @@ type PackageData struct {
 	// Version is the brf.it version string.
 	Version string
 
+	// Mode selects what is rendered per file: ModeSig (default), ModeOutline,
+	// ModeDocs or ModeFull. Empty means ModeSig.
+	Mode string
+
 	// Tree is the directory tree string.
 	Tree string
 
@@ -57,20 +72,52 @@ type PackageData struct {
 	// Only populated when DedupeImports is true.
 	GlobalImports []ImportCount
 
+	// Packages holds the summaries of the directories (packages, modules)
+	// of the files, sorted by path. A file whose summary is its package's
+	// is not repeated under the file.
+	Packages []PackageSummary
+
 	// MaxDocLength is the maximum length of documentation comments.
 	// 0 means no limit (default).
 	MaxDocLength int
 
+	// DocStyle selects how much of each documentation comment to render:
+	// DocStyleFull, DocStyleSummary or DocStyleParams. Empty means
+	// DocStyleFull.
+	DocStyle string
+
 	// NoSchema indicates whether to omit the schema section in output.
 	NoSchema bool
 
 	// IncludeCallGraph indicates whether to include function call references.
 	IncludeCallGraph bool
 
+	// IncludeLocations indicates whether XML and Markdown render symbol IDs
+	// and source ranges. JSON always includes them.
+	IncludeLocations bool
+
+	// IncludeStructure indicates whether XML renders parameters, return
+	// types, type parameters, receivers and modifiers as attributes. JSON
+	// always includes them.
+	IncludeStructure bool
+
 	// SkipEmpty omits files with no signatures/imports from the output entirely.
 	SkipEmpty bool
 }
 
+// PackageSummary is the documentation of a directory's package or module,
+// taken from one of its files (e.g., "doc.go", "__init__.py", "lib.rs").
+type PackageSummary struct {
+	// Path is the directory path.
+	Path string
+
+	// Summary is the first paragraph of the package documentation.
+	Summary string
+
+	// Source is the path of the file the summary was taken from.
+	Source string
+}
+
 // ImportCount represents an import with its usage count across files.
 type ImportCount struct {
 	// Import is the raw import statement text.
@@ -1,6 +1,7 @@
 package formatter
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 	"testing"
@@ -391,6 +392,12 @@ func TestKindToTag(t *testing.T) {
 		{"macro", "variable"},
 		{"export", "variable"},
 
+		// component 그룹
+		{"component", "component"},
+		{"props", "props"},
+		{"prop", "prop"},
+		{"emits", "emits"},
+
 		// fallback
 		{"", "signature"},
 		{"unknown", "signature"},
@@ -962,3 +969,436 @@ func TestXMLFormatterWithNoSchema(t *testing.T) {
 		t.Error("expected no <schema> section with --no-schema flag")
 	}
 }
+
+func TestFormattersNestMembers(t *testing.T) {
+	data := &PackageData{
+		NoSchema: true,
+		Files: []FileData{
+			{
+				Path:     "Outer.java",
+				Language: "java",
+				Signatures: []parser.Signature{
+					{Name: "Outer", Kind: "class", Text: "public class Outer", Line: 1},
+					{Name: "a", Kind: "method", Text: "public void a()", Line: 2, Parent: "Outer"},
+					{Name: "Inner", Kind: "class", Text: "public static class Inner", Line: 3, Parent: "Outer", Doc: "Inner doc"},
+					{Name: "b", Kind: "method", Text: "public void b()", Line: 4, Parent: "Outer.Inner"},
+					{Name: "main", Kind: "function", Text: "void main()", Line: 8},
+				},
+			},
+		},
+	}
+
+	t.Run("xml", func(t *testing.T) {
+		out, err := NewXMLFormatter().Format(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := `      <type>public class Outer</type>
+      <members of="Outer">
+        <function>public void a()</function>
+        <type>public static class Inner</type>
+        <doc>Inner doc</doc>
+        <members of="Outer.Inner">
+          <function>public void b()</function>
+        </members>
+      </members>
+      <function>void main()</function>
+`
+		if !strings.Contains(string(out), want) {
+			t.Errorf("expected nested members:\n%s\ngot:\n%s", want, out)
+		}
+	})
+
+	t.Run("markdown", func(t *testing.T) {
+		out, err := NewMarkdownFormatter().Format(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "public class Outer\n  public void a()\n  public static class Inner\n    public void b()\nvoid main()\n"
+		if !strings.Contains(string(out), want) {
+			t.Errorf("expected indented members:\n%s\ngot:\n%s", want, out)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		out, err := NewJSONFormatter().Format(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var parsed jsonOutput
+		if err := json.Unmarshal(out, &parsed); err != nil {
+			t.Fatal(err)
+		}
+		sigs := parsed.Files[0].Signatures
+		if len(sigs) != 2 || sigs[0].Name != "Outer" || sigs[1].Name != "main" {
+			t.Fatalf("expected Outer and main at the top level, got %+v", sigs)
+		}
+		members := sigs[0].Members
+		if len(members) != 2 || members[1].Name != "Inner" || len(members[1].Members) != 1 {
+			t.Fatalf("unexpected members: %+v", members)
+		}
+		if b := members[1].Members[0]; b.Name != "b" || b.Parent != "Outer.Inner" {
+			t.Errorf("unexpected nested member: %+v", b)
+		}
+	})
+}
+
+func TestFormattersQualifiedNames(t *testing.T) {
+	data := &PackageData{
+		NoSchema:         true,
+		IncludeCallGraph: true,
+		Files: []FileData{
+			{
+				Path:     "scanner.go",
+				Language: "go",
+				Signatures: []parser.Signature{
+					{Name: "Scan", Kind: "method", Text: "func (s *FileScanner) Scan() error", Line: 3, QualifiedName: "scanner.FileScanner.Scan"},
+				},
+				Calls: []parser.FunctionCall{
+					{Caller: "Scan", QualifiedCaller: "scanner.FileScanner.Scan", Callee: "walk", Line: 4},
+				},
+			},
+		},
+	}
+
+	t.Run("json", func(t *testing.T) {
+		out, err := NewJSONFormatter().Format(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var parsed jsonOutput
+		if err := json.Unmarshal(out, &parsed); err != nil {
+			t.Fatal(err)
+		}
+		file := parsed.Files[0]
+		if sig := file.Signatures[0]; sig.QualifiedName != "scanner.FileScanner.Scan" ||
+			sig.ID != "go:scanner.go#scanner.FileScanner.Scan:method" {
+			t.Errorf("unexpected signature: %+v", sig)
+		}
+		if len(file.Calls) != 1 || file.Calls[0].Caller != "Scan" || file.Calls[0].QualifiedCaller != "scanner.FileScanner.Scan" {
+			t.Errorf("unexpected calls: %+v", file.Calls)
+		}
+	})
+
+	t.Run("xml", func(t *testing.T) {
+		out, err := NewXMLFormatter().Format(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(out), `caller="scanner.FileScanner.Scan"`) {
+			t.Errorf("expected qualified caller, got:\n%s", out)
+		}
+	})
+
+	t.Run("markdown", func(t *testing.T) {
+		out, err := NewMarkdownFormatter().Format(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(out), "- `scanner.FileScanner.Scan` → `walk`") {
+			t.Errorf("expected qualified caller, got:\n%s", out)
+		}
+	})
+}
+
+func TestFormattersStructure(t *testing.T) {
+	data := &PackageData{
+		NoSchema:         true,
+		IncludeStructure: true,
+		Files: []FileData{
+			{
+				Path:     "scanner.go",
+				Language: "go",
+				Signatures: []parser.Signature{
+					{
+						Name: "Scan", Kind: "method", Line: 3,
+						Text:     "func (s *FileScanner) Scan(ctx context.Context, opts ...Option) (int, error)",
+						Receiver: &parser.Parameter{Name: "s", Type: "*FileScanner"},
+						Params: []parser.Parameter{
+							{Name: "ctx", Type: "context.Context"},
+							{Name: "opts", Type: "Option", Variadic: true},
+						},
+						Returns: []string{"int", "error"},
+					},
+					{
+						Name: "Map", Kind: "function", Line: 8, Text: "func Map[K comparable](m map[K]int, n int) []K",
+						TypeParams: []parser.TypeParameter{{Name: "K", Constraint: "comparable"}},
+						Modifiers:  []string{"static"},
+						Visibility: parser.VisibilityPackage,
+					},
+				},
+			},
+		},
+	}
+
+	xmlOut, err := NewXMLFormatter().Format(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`<function params="ctx: context.Context; ...opts: Option" returns="int; error" receiver="s: *FileScanner">`,
+		`<function typeParams="K: comparable" modifiers="static" visibility="package">`,
+	} {
+		if !strings.Contains(string(xmlOut), want) {
+			t.Errorf("expected %q in XML output:\n%s", want, xmlOut)
+		}
+	}
+
+	// Without IncludeStructure, XML stays unchanged
+	data.IncludeStructure = false
+	xmlOut, _ = NewXMLFormatter().Format(data)
+	if strings.Contains(string(xmlOut), "params=") {
+		t.Errorf("unexpected structure attributes without IncludeStructure:\n%s", xmlOut)
+	}
+
+	// JSON always includes the structured parts
+	jsonOut, err := NewJSONFormatter().Format(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var parsed jsonOutput
+	if err := json.Unmarshal(jsonOut, &parsed); err != nil {
+		t.Fatal(err)
+	}
+	scan, m := parsed.Files[0].Signatures[0], parsed.Files[0].Signatures[1]
+	if len(scan.Params) != 2 || !scan.Params[1].Variadic || scan.Params[1].Type != "Option" ||
+		len(scan.Returns) != 2 || scan.Receiver == nil || scan.Receiver.Type != "*FileScanner" {
+		t.Errorf("unexpected structure: %+v", scan)
+	}
+	if len(m.TypeParams) != 1 || m.TypeParams[0].Constraint != "comparable" || len(m.Modifiers) != 1 || m.Visibility != "package" {
+		t.Errorf("unexpected structure: %+v", m)
+	}
+}
+
+func TestFormattersDecorators(t *testing.T) {
+	data := &PackageData{
+		NoSchema: true,
+		Files: []FileData{
+			{
+				Path:     "app.py",
+				Language: "python",
+				Signatures: []parser.Signature{
+					{
+						Name: "list_users", Kind: "function", Line: 3, Text: "def list_users()",
+						Decorators: []parser.Decorator{
+							{Name: "app.route", Args: `"/users"`, Text: `@app.route("/users")`},
+							{Name: "login_required", Text: "@login_required"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	xmlOut, err := NewXMLFormatter().Format(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "<function>def list_users()</function>\n" +
+		"      <decorator>@app.route(&quot;/users&quot;)</decorator>\n" +
+		"      <decorator>@login_required</decorator>\n"
+	if !strings.Contains(string(xmlOut), want) {
+		t.Errorf("expected %q in XML output:\n%s", want, xmlOut)
+	}
+
+	mdOut, err := NewMarkdownFormatter().Format(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "@app.route(\"/users\")\n@login_required\ndef list_users()\n"; !strings.Contains(string(mdOut), want) {
+		t.Errorf("expected %q in Markdown output:\n%s", want, mdOut)
+	}
+
+	jsonOut, err := NewJSONFormatter().Format(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var parsed jsonOutput
+	if err := json.Unmarshal(jsonOut, &parsed); err != nil {
+		t.Fatal(err)
+	}
+	decs := parsed.Files[0].Signatures[0].Decorators
+	if len(decs) != 2 || decs[0].Name != "app.route" || decs[0].Args != `"/users"` || decs[1].Text != "@login_required" {
+		t.Errorf("unexpected decorators: %+v", decs)
+	}
+}
+
+func TestFormattersLocations(t *testing.T) {
+	data := &PackageData{
+		NoSchema:         true,
+		IncludeLocations: true,
+		Files: []FileData{
+			{
+				Path:     "pkg/a.go",
+				Language: "go",
+				Signatures: []parser.Signature{
+					{Name: "Run", Kind: "function", Text: "func Run()", Line: 3, Column: 1, EndLine: 5, EndColumn: 2, StartByte: 11, EndByte: 40},
+				},
+			},
+		},
+	}
+
+	xmlOut, err := NewXMLFormatter().Format(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `<function id="go:pkg/a.go#Run:function" loc="3:1-5:2" bytes="11-40">func Run()</function>`; !strings.Contains(string(xmlOut), want) {
+		t.Errorf("expected %q in XML output:\n%s", want, xmlOut)
+	}
+
+	mdOut, err := NewMarkdownFormatter().Format(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "#### Locations\n\n- `go:pkg/a.go#Run:function` 3:1-5:2 (bytes 11-40)\n"; !strings.Contains(string(mdOut), want) {
+		t.Errorf("expected %q in Markdown output:\n%s", want, mdOut)
+	}
+
+	// Without IncludeLocations, XML and Markdown stay unchanged
+	data.IncludeLocations = false
+	xmlOut, _ = NewXMLFormatter().Format(data)
+	mdOut, _ = NewMarkdownFormatter().Format(data)
+	if strings.Contains(string(xmlOut), "loc=") || strings.Contains(string(mdOut), "Locations") {
+		t.Errorf("unexpected locations without IncludeLocations:\n%s\n%s", xmlOut, mdOut)
+	}
+
+	// JSON always includes IDs and ranges
+	jsonOut, err := NewJSONFormatter().Format(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var parsed jsonOutput
+	if err := json.Unmarshal(jsonOut, &parsed); err != nil {
+		t.Fatal(err)
+	}
+	got := parsed.Files[0].Signatures[0]
+	if got.ID != "go:pkg/a.go#Run:function" || got.EndLine != 5 || got.Column != 1 || got.EndColumn != 2 ||
+		got.StartByte != 11 || got.EndByte != 40 {
+		t.Errorf("unexpected JSON location fields: %+v", got)
+	}
+}
+
+func TestFormatDeprecated(t *testing.T) {
+	data := &PackageData{
+		Files: []FileData{{
+			Path:     "api.go",
+			Language: "go",
+			Signatures: []parser.Signature{
+				{Name: "Old", Kind: "function", Text: "func Old()", Deprecated: true, DeprecationNote: "Use New."},
+				{Name: "Gone", Kind: "function", Text: "func Gone()", Deprecated: true},
+				{Name: "New", Kind: "function", Text: "func New()"},
+			},
+		}},
+	}
+
+	tests := []struct {
+		formatter Formatter
+		want      []string
+	}{
+		{NewXMLFormatter(), []string{`<function deprecated="Use New.">func Old()</function>`, `<function deprecated="true">func Gone()</function>`, `<function>func New()</function>`}},
+		{NewMarkdownFormatter(), []string{"// Deprecated: Use New.\nfunc Old()\n// Deprecated\nfunc Gone()\nfunc New()"}},
+		{NewJSONFormatter(), []string{`"deprecated":true,"deprecationNote":"Use New."`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.formatter.Name(), func(t *testing.T) {
+			out, err := tt.formatter.Format(data)
+			if err != nil {
+				t.Fatalf("Format: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(out), want) {
+					t.Errorf("output missing %q:\n%s", want, out)
+				}
+			}
+		})
+	}
+}
+
+func TestFormatComponents(t *testing.T) {
+	data := &PackageData{
+		Files: []FileData{{
+			Path:     "Button.tsx",
+			Language: "tsx",
+			Signatures: []parser.Signature{
+				{Name: "Button", Kind: "component", Text: "export function Button({ label }: ButtonProps)"},
+				{Name: "useToggle", Kind: "function", Text: "export function useToggle()"},
+			},
+		}},
+	}
+
+	tests := []struct {
+		formatter Formatter
+		want      []string
+	}{
+		{NewXMLFormatter(), []string{`<component>export function Button({ label }: ButtonProps)</component>`, `<function>export function useToggle()</function>`}},
+		{NewMarkdownFormatter(), []string{"#### Components\n\n- `Button` (component)\n"}},
+		{NewJSONFormatter(), []string{`"kind":"component","name":"Button"`, `"kind":"function","name":"useToggle"`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.formatter.Name(), func(t *testing.T) {
+			out, err := tt.formatter.Format(data)
+			if err != nil {
+				t.Fatalf("Format: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(out), want) {
+					t.Errorf("output missing %q:\n%s", want, out)
+				}
+			}
+			if tt.formatter.Name() == "markdown" && strings.Contains(string(out), "`useToggle` (") {
+				t.Errorf("function listed as component:\n%s", out)
+			}
+		})
+	}
+}
+
+func TestFormatComponentAPI(t *testing.T) {
+	data := &PackageData{
+		Files: []FileData{{
+			Path:     "Card.vue",
+			Language: "vue",
+			Signatures: []parser.Signature{
+				{Name: "props", Kind: "props", Text: "const props = defineProps<Props>()", Params: []parser.Parameter{{Name: "title", Type: "string"}, {Name: "count", Type: "number"}}},
+				{Name: "emit", Kind: "emits", Text: "const emit = defineEmits<{ close: [] }>()", Params: []parser.Parameter{{Name: "change"}, {Name: "close", Type: "[]"}}},
+				{Name: "label", Kind: "prop", Text: "export let label: string;"},
+			},
+		}},
+	}
+
+	tests := []struct {
+		formatter Formatter
+		want      []string
+	}{
+		{NewXMLFormatter(), []string{
+			`<props props="title: string; count: number">const props = defineProps&lt;Props&gt;()</props>`,
+			`<emits events="change; close: []">const emit = defineEmits&lt;{ close: [] }&gt;()</emits>`,
+			`<prop>export let label: string;</prop>`,
+		}},
+		{NewMarkdownFormatter(), []string{"#### Components\n\n- `props` (props): title: string; count: number\n- `emit` (emits): change; close: []\n- `label` (prop)\n"}},
+		{NewJSONFormatter(), []string{
+			`"kind":"props","name":"props"`,
+			`"params":[{"name":"title","type":"string"},{"name":"count","type":"number"}]`,
+			`"kind":"emits","name":"emit"`,
+			`"kind":"prop","name":"label"`,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.formatter.Name(), func(t *testing.T) {
+			out, err := tt.formatter.Format(data)
+			if err != nil {
+				t.Fatalf("Format: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(out), want) {
+					t.Errorf("output missing %q:\n%s", want, out)
+				}
+			}
+			if strings.Contains(string(out), "<variable>") || strings.Contains(string(out), `"kind":"variable"`) {
+				t.Errorf("component API reported as variable:\n%s", out)
+			}
+		})
+	}
+}
@@ -1,11 +1,20 @@
 package formatter
 
-import "unicode/utf8"
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 
-// normalizeKind normalizes a signature kind string to one of the canonical
-// categories: "function", "type", or "variable". If the kind does not match
-// any known category, it is returned unchanged.
-func normalizeKind(kind string) string {
+	"github.com/indigo-net/Brf.it/pkg/parser"
+)
+
+// NormalizeKind normalizes a signature kind string to one of the canonical
+// categories: "function", "type", "variable", or for UI components
+// "component" and their API kinds "props", "prop" and "emits". If the kind
+// does not match any known category, it is returned unchanged.
+func NormalizeKind(kind string) string {
 	switch kind {
 	case "function", "method", "constructor", "destructor", "arrow", "local_function", "module_function":
 		return "function"
@@ -13,6 +22,8 @@ func normalizeKind(kind string) string {
 		return "type"
 	case "variable", "field", "macro", "export":
 		return "variable"
+	case "component", "props", "prop", "emits":
+		return kind
 	default:
 		return kind
 	}
@@ -23,19 +34,38 @@ func getEmptyComment(lang string) string {
 	switch lang {
 	case "python", "ruby":
 		return "# (empty)"
-	case "html", "xml":
+	case "html", "xml", "vue", "svelte", "astro":
 		return "<!-- (empty) -->"
-	case "go", "c", "cpp", "java", "javascript", "typescript":
+	case "go", "c", "cpp", "java", "javascript", "typescript", "jsx", "tsx":
 		return "// (empty)"
 	default:
 		return "// (empty)"
 	}
 }
 
-// truncateDoc truncates a documentation string to maxLen characters (Unicode code points).
+// deprecationComment returns a line comment in lang marking sig
+// deprecated (e.g., "// Deprecated: use New").
+func deprecationComment(lang string, sig parser.Signature) string {
+	text := "Deprecated"
+	if sig.DeprecationNote != "" {
+		text += ": " + sig.DeprecationNote
+	}
+	switch lang {
+	case "python", "ruby", "shell", "yaml", "toml", "elixir":
+		return "# " + text
+	case "lua", "sql":
+		return "-- " + text
+	default:
+		return "// " + text
+	}
+}
+
+// TruncateDoc truncates a documentation string to maxLen characters (Unicode code points).
 // If maxLen <= 0 or the doc is shorter than or equal to maxLen, returns doc unchanged.
-// If truncation occurs, "..." is appended.
-func truncateDoc(doc string, maxLen int) string {
+// Otherwise the doc is cut after the last sentence that fits, or when that
+// would drop more than half of the allowed length, at the last word
+// boundary, and "..." is appended.
+func TruncateDoc(doc string, maxLen int) string {
 	if maxLen <= 0 {
 		return doc
 	}
@@ -45,5 +75,176 @@ func truncateDoc(doc string, maxLen int) string {
 	}
 
 	runes := []rune(doc)
-	return string(runes[:maxLen]) + "..."
+	cut := runes[:maxLen]
+	for i := len(cut) - 1; i >= maxLen/2; i-- {
+		if isSentenceEnd(runes, i) {
+			return strings.TrimSpace(string(runes[:i+1]))
+		}
+	}
+	for i := len(cut); i > maxLen/2; i-- {
+		if unicode.IsSpace(runes[i]) {
+			return strings.TrimRight(string(runes[:i]), " \t\n,;:") + "..."
+		}
+	}
+	return string(cut) + "..."
+}
+
+// isSentenceEnd reports whether runes[i] ends a sentence: a period,
+// exclamation or question mark followed by whitespace.
+func isSentenceEnd(runes []rune, i int) bool {
+	switch runes[i] {
+	case '.', '!', '?':
+		return i+1 < len(runes) && unicode.IsSpace(runes[i+1])
+	}
+	return false
+}
+
+// sigNode is a signature together with the signatures declared inside it.
+type sigNode struct {
+	sig     parser.Signature
+	id      string
+	members []*sigNode
+}
+
+// callerName returns the qualified caller of call, or its plain name when
+// the caller is not qualified.
+func callerName(call parser.FunctionCall) string {
+	if call.QualifiedCaller != "" {
+		return call.QualifiedCaller
+	}
+	return call.Caller
+}
+
+// sigRange formats the source range of sig as "line:column-endLine:endColumn".
+func sigRange(sig parser.Signature) string {
+	return strconv.Itoa(sig.Line) + ":" + strconv.Itoa(sig.Column) + "-" +
+		strconv.Itoa(sig.EndLine) + ":" + strconv.Itoa(sig.EndColumn)
+}
+
+// sigBytes formats the byte range of sig as "start-end".
+func sigBytes(sig parser.Signature) string {
+	return strconv.Itoa(sig.StartByte) + "-" + strconv.Itoa(sig.EndByte)
+}
+
+// formatParam renders p as "name: type = default", with "..." before the
+// name of a variadic parameter.
+func formatParam(p parser.Parameter) string {
+	var sb strings.Builder
+	if p.Variadic {
+		sb.WriteString("...")
+	}
+	sb.WriteString(p.Name)
+	if p.Type != "" {
+		if p.Name != "" {
+			sb.WriteString(": ")
+		}
+		sb.WriteString(p.Type)
+	}
+	if p.Default != "" {
+		sb.WriteString(" = ")
+		sb.WriteString(p.Default)
+	}
+	return sb.String()
+}
+
+// formatParams renders parameters separated by "; " (types may contain commas).
+func formatParams(params []parser.Parameter) string {
+	parts := make([]string, len(params))
+	for i, p := range params {
+		parts[i] = formatParam(p)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// componentMembers returns the props or events declared by a component API
+// signature, formatted by formatParams, with the name of the XML attribute
+// that lists them ("props" or "events"). It returns empty strings for other
+// signatures.
+func componentMembers(sig parser.Signature) (attr, list string) {
+	if len(sig.Params) == 0 {
+		return "", ""
+	}
+	switch sig.Kind {
+	case "props":
+		return "props", formatParams(sig.Params)
+	case "emits":
+		return "events", formatParams(sig.Params)
+	}
+	return "", ""
+}
+
+// formatTypeParams renders type parameters as "name: constraint = default",
+// separated by "; ".
+func formatTypeParams(params []parser.TypeParameter) string {
+	parts := make([]string, len(params))
+	for i, tp := range params {
+		parts[i] = formatParam(parser.Parameter{Name: tp.Name, Type: tp.Constraint, Default: tp.Default})
+	}
+	return strings.Join(parts, "; ")
+}
+
+// symbolIDs returns the symbol IDs of the signatures of file.
+func symbolIDs(data *PackageData, file FileData) []string {
+	return parser.SymbolIDs(file.Language, symbolPath(data.RootPath, file.Path), file.Signatures)
+}
+
+// symbolPath returns the path used in the symbol IDs of a file: relative to
+// root and slash-separated, as in brfit query, so that IDs do not depend on
+// where the project is checked out. Paths outside root are kept as is.
+func symbolPath(root, path string) string {
+	if root == "" {
+		return filepath.ToSlash(path)
+	}
+	rel, err := filepath.Rel(root, path)
+	switch {
+	case err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)):
+		return filepath.ToSlash(path)
+	case rel == ".":
+		// The root is the file itself
+		return filepath.Base(path)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// nestSignatures arranges the signatures of file into a tree using
+// Signature.Parent, keeping their order, and assigns their symbol IDs. A
+// signature whose container is not in the file (e.g., a filtered private
+// class) is attached to the closest container that is present, or stays at
+// the top level.
+func nestSignatures(data *PackageData, file FileData) []*sigNode {
+	sigs := file.Signatures
+	ids := symbolIDs(data, file)
+	var roots []*sigNode
+	var open []*sigNode // containers enclosing the current position, outermost first
+	for i, sig := range sigs {
+		n := &sigNode{sig: sig, id: ids[i]}
+		parent := -1
+		if sig.Parent != "" {
+			for i := len(open) - 1; i >= 0; i-- {
+				path := open[i].sig.Path()
+				if path == sig.Parent || strings.HasPrefix(sig.Parent, path+".") {
+					parent = i
+					break
+				}
+			}
+		}
+		if parent >= 0 {
+			open[parent].members = append(open[parent].members, n)
+			open = open[:parent+1]
+		} else {
+			roots = append(roots, n)
+			open = open[:0]
+		}
+		open = append(open, n)
+	}
+	return roots
+}
+
+// walkSignatures calls fn for each node of the tree in source order with its
+// nesting depth (0 for top-level signatures).
+func walkSignatures(nodes []*sigNode, depth int, fn func(n *sigNode, depth int)) {
+	for _, n := range nodes {
+		fn(n, depth)
+		walkSignatures(n.members, depth+1, fn)
+	}
 }
@@ -1,7 +1,10 @@
 package formatter
 
 import (
+	"strings"
 	"testing"
+
+	"github.com/indigo-net/Brf.it/pkg/parser"
 )
 
 func TestGetEmptyComment(t *testing.T) {
@@ -30,3 +33,70 @@ func TestGetEmptyComment(t *testing.T) {
 		})
 	}
 }
+
+func TestNestSignatures(t *testing.T) {
+	sigs := []parser.Signature{
+		{Name: "Outer", Kind: "class"},
+		{Name: "a", Kind: "method", Parent: "Outer"},
+		{Name: "Inner", Kind: "class", Parent: "Outer"},
+		{Name: "b", Kind: "method", Parent: "Outer.Inner"},
+		{Name: "c", Kind: "method", Parent: "Outer.Hidden"}, // container filtered out
+		{Name: "d", Kind: "method", Parent: "Outer"},
+		{Name: "top", Kind: "function"},
+		{Name: "m", Kind: "method", Parent: "Gone"}, // no enclosing container present
+	}
+
+	var lines []string
+	walkSignatures(nestSignatures(&PackageData{}, FileData{Language: "java", Path: "Outer.java", Signatures: sigs}), 0, func(n *sigNode, depth int) {
+		lines = append(lines, strings.Repeat("  ", depth)+n.sig.Name)
+	})
+	got := strings.Join(lines, "\n")
+	want := strings.Join([]string{"Outer", "  a", "  Inner", "    b", "  c", "  d", "top", "m"}, "\n")
+	if got != want {
+		t.Errorf("got tree:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestTruncateDoc(t *testing.T) {
+	tests := []struct {
+		name   string
+		doc    string
+		maxLen int
+		want   string
+	}{
+		{"no limit", "Adds two numbers. Returns the sum.", 0, "Adds two numbers. Returns the sum."},
+		{"fits", "Adds two numbers.", 17, "Adds two numbers."},
+		{"sentence boundary", "Adds two numbers. Returns the sum of both.", 30, "Adds two numbers."},
+		{"word boundary", "Adds two numbers together and returns the sum", 20, "Adds two numbers..."},
+		{"no boundary", "Supercalifragilistic", 5, "Super..."},
+		{"unicode", "한글 문서 주석입니다", 5, "한글 문서..."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TruncateDoc(tt.doc, tt.maxLen); got != tt.want {
+				t.Errorf("TruncateDoc(%q, %d) = %q, want %q", tt.doc, tt.maxLen, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeprecationComment(t *testing.T) {
+	tests := []struct {
+		lang string
+		sig  parser.Signature
+		want string
+	}{
+		{"go", parser.Signature{Deprecated: true, DeprecationNote: "Use New."}, "// Deprecated: Use New."},
+		{"python", parser.Signature{Deprecated: true}, "# Deprecated"},
+		{"lua", parser.Signature{Deprecated: true, DeprecationNote: "gone"}, "-- Deprecated: gone"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.lang, func(t *testing.T) {
+			if got := deprecationComment(tt.lang, tt.sig); got != tt.want {
+				t.Errorf("deprecationComment(%q) = %q, want %q", tt.lang, got, tt.want)
+			}
+		})
+	}
+}
@@ -2,6 +2,8 @@ package formatter
 
 import (
 	"encoding/json"
+
+	"github.com/indigo-net/Brf.it/pkg/parser"
 )
 
 // JSONFormatter implements Formatter for JSON output.
@@ -23,9 +25,17 @@ type jsonOutput struct {
 	Path          string            `json:"path,omitempty"`
 	Tree          string            `json:"tree,omitempty"`
 	GlobalImports []jsonImportCount `json:"globalImports,omitempty"`
+	Packages      []jsonPackage     `json:"packages,omitempty"`
 	Files         []jsonFile        `json:"files"`
 }
 
+// jsonPackage represents a directory package summary.
+type jsonPackage struct {
+	Path    string `json:"path"`
+	Summary string `json:"summary"`
+	Source  string `json:"source,omitempty"`
+}
+
 // jsonImportCount represents a global import with usage count.
 type jsonImportCount struct {
 	Import string `json:"import"`
@@ -34,28 +44,138 @@ type jsonImportCount struct {
 
 // jsonFile represents a single file in the JSON output.
 type jsonFile struct {
-	Path       string     `json:"path"`
-	Language   string     `json:"language"`
-	Signatures []jsonSig  `json:"signatures,omitempty"`
-	Imports    []string   `json:"imports,omitempty"`
-	Calls      []jsonCall `json:"calls,omitempty"`
-	Error      string     `json:"error,omitempty"`
+	Path        string           `json:"path"`
+	Language    string           `json:"language"`
+	Summary     string           `json:"summary,omitempty"`
+	Diagnostics []jsonDiagnostic `json:"diagnostics,omitempty"`
+	Signatures  []jsonSig        `json:"signatures,omitempty"`
+	Imports     []string         `json:"imports,omitempty"`
+	Calls       []jsonCall       `json:"calls,omitempty"`
+	Symbols     []jsonSymbol     `json:"symbols,omitempty"`
+	Docs        []jsonDoc        `json:"docs,omitempty"`
+	Content     string           `json:"content,omitempty"`
+	Error       string           `json:"error,omitempty"`
 }
 
 // jsonCall represents a function call reference in the JSON output.
 type jsonCall struct {
-	Caller string `json:"caller,omitempty"`
-	Callee string `json:"callee"`
-	Line   int    `json:"line"`
+	Caller          string `json:"caller,omitempty"`
+	QualifiedCaller string `json:"qualifiedCaller,omitempty"`
+	Callee          string `json:"callee"`
+	Line            int    `json:"line"`
 }
 
 // jsonSig represents a signature in the JSON output.
 type jsonSig struct {
-	Kind     string `json:"kind"`
-	Text     string `json:"text"`
-	Doc      string `json:"doc,omitempty"`
-	Line     int    `json:"line,omitempty"`
-	Exported bool   `json:"exported,omitempty"`
+	ID              string          `json:"id"`
+	Kind            string          `json:"kind"`
+	Name            string          `json:"name,omitempty"`
+	QualifiedName   string          `json:"qualifiedName,omitempty"`
+	Parent          string          `json:"parent,omitempty"`
+	Text            string          `json:"text"`
+	Doc             string          `json:"doc,omitempty"`
+	Line            int             `json:"line,omitempty"`
+	EndLine         int             `json:"endLine,omitempty"`
+	Column          int             `json:"column,omitempty"`
+	EndColumn       int             `json:"endColumn,omitempty"`
+	StartByte       int             `json:"startByte"`
+	EndByte         int             `json:"endByte,omitempty"`
+	Exported        bool            `json:"exported,omitempty"`
+	Visibility      string          `json:"visibility,omitempty"`
+	Deprecated      bool            `json:"deprecated,omitempty"`
+	DeprecationNote string          `json:"deprecationNote,omitempty"`
+	Params          []jsonParam     `json:"params,omitempty"`
+	Returns         []string        `json:"returns,omitempty"`
+	TypeParams      []jsonTypeParam `json:"typeParams,omitempty"`
+	Receiver        *jsonParam      `json:"receiver,omitempty"`
+	Modifiers       []string        `json:"modifiers,omitempty"`
+	Decorators      []jsonDecorator `json:"decorators,omitempty"`
+	Members         []jsonSig       `json:"members,omitempty"`
+}
+
+// jsonParam represents a function, method or receiver parameter.
+type jsonParam struct {
+	Name     string `json:"name,omitempty"`
+	Type     string `json:"type,omitempty"`
+	Default  string `json:"default,omitempty"`
+	Variadic bool   `json:"variadic,omitempty"`
+}
+
+// jsonDecorator represents a decorator, annotation or attribute.
+type jsonDecorator struct {
+	Name string `json:"name"`
+	Args string `json:"args,omitempty"`
+	Text string `json:"text"`
+}
+
+// jsonTypeParam represents a generic type parameter.
+type jsonTypeParam struct {
+	Name       string `json:"name"`
+	Constraint string `json:"constraint,omitempty"`
+	Default    string `json:"default,omitempty"`
+}
+
+// newJSONParam converts a parameter for the JSON output.
+func newJSONParam(p parser.Parameter) jsonParam {
+	return jsonParam{Name: p.Name, Type: p.Type, Default: p.Default, Variadic: p.Variadic}
+}
+
+// jsonDecorators converts decorators for the JSON output.
+func jsonDecorators(decs []parser.Decorator) []jsonDecorator {
+	if len(decs) == 0 {
+		return nil
+	}
+	out := make([]jsonDecorator, len(decs))
+	for i, dec := range decs {
+		out[i] = jsonDecorator{Name: dec.Name, Args: dec.Args, Text: dec.Text}
+	}
+	return out
+}
+
+// jsonSignatures converts nodes into JSON signatures with nested members.
+func jsonSignatures(data *PackageData, nodes []*sigNode) []jsonSig {
+	sigs := make([]jsonSig, 0, len(nodes))
+	for _, n := range nodes {
+		js := jsonSig{
+			ID:              n.id,
+			Kind:            NormalizeKind(n.sig.Kind),
+			Name:            n.sig.Name,
+			QualifiedName:   n.sig.QualifiedName,
+			Parent:          n.sig.Parent,
+			Text:            n.sig.Text,
+			Line:            n.sig.Line,
+			EndLine:         n.sig.EndLine,
+			Column:          n.sig.Column,
+			EndColumn:       n.sig.EndColumn,
+			StartByte:       n.sig.StartByte,
+			EndByte:         n.sig.EndByte,
+			Exported:        n.sig.Exported,
+			Visibility:      string(n.sig.Visibility),
+			Deprecated:      n.sig.Deprecated,
+			DeprecationNote: n.sig.DeprecationNote,
+			Returns:         n.sig.Returns,
+			Modifiers:       n.sig.Modifiers,
+			Decorators:      jsonDecorators(n.sig.Decorators),
+		}
+		for _, p := range n.sig.Params {
+			js.Params = append(js.Params, newJSONParam(p))
+		}
+		for _, tp := range n.sig.TypeParams {
+			js.TypeParams = append(js.TypeParams, jsonTypeParam{Name: tp.Name, Constraint: tp.Constraint, Default: tp.Default})
+		}
+		if n.sig.Receiver != nil {
+			recv := newJSONParam(*n.sig.Receiver)
+			js.Receiver = &recv
+		}
+		if n.sig.Doc != "" {
+			js.Doc = docText(data, n.sig.Doc)
+		}
+		if len(n.members) > 0 {
+			js.Members = jsonSignatures(data, n.members)
+		}
+		sigs = append(sigs, js)
+	}
+	return sigs
 }
 
 // Format implements Formatter interface.
@@ -78,9 +198,21 @@ func (f *JSONFormatter) Format(data *PackageData) ([]byte, error) {
 		}
 	}
 
+	for _, pkg := range data.Packages {
+		output.Packages = append(output.Packages, jsonPackage{
+			Path:    pkg.Path,
+			Summary: docText(data, pkg.Summary),
+			Source:  pkg.Source,
+		})
+	}
+
 	for _, file := range data.Files {
 		// SkipEmpty: 빈 파일 건너뜀
-		if data.SkipEmpty && file.Error == nil {
+		if data.SkipEmpty && !isSigMode(data.Mode) {
+			if FileIsEmpty(data, file) {
+				continue
+			}
+		} else if data.SkipEmpty && file.Error == nil && len(file.Diagnostics) == 0 {
 			hasImports := data.IncludeImports && len(file.RawImports) > 0 && !data.DedupeImports
 			if len(file.Signatures) == 0 && !hasImports {
 				continue
@@ -92,24 +224,19 @@ func (f *JSONFormatter) Format(data *PackageData) ([]byte, error) {
 			Language: file.Language,
 		}
 
+		if file.Error == nil {
+			jf.Summary = summaryText(data, file)
+		}
+		jf.Diagnostics = jsonDiagnostics(file)
+
 		if file.Error != nil {
 			jf.Error = file.Error.Error()
+		} else if !isSigMode(data.Mode) {
+			fillJSONModeBody(&jf, data, file)
 		} else {
 			// Add signatures
 			if len(file.Signatures) > 0 {
-				jf.Signatures = make([]jsonSig, 0, len(file.Signatures))
-				for _, sig := range file.Signatures {
-					js := jsonSig{
-						Kind:     normalizeKind(sig.Kind),
-						Text:     sig.Text,
-						Line:     sig.Line,
-						Exported: sig.Exported,
-					}
-					if sig.Doc != "" {
-						js.Doc = truncateDoc(sig.Doc, data.MaxDocLength)
-					}
-					jf.Signatures = append(jf.Signatures, js)
-				}
+				jf.Signatures = jsonSignatures(data, nestSignatures(data, file))
 			}
 
 			// Add imports if requested (skip if deduping)
@@ -122,9 +249,10 @@ func (f *JSONFormatter) Format(data *PackageData) ([]byte, error) {
 				jf.Calls = make([]jsonCall, 0, len(file.Calls))
 				for _, call := range file.Calls {
 					jf.Calls = append(jf.Calls, jsonCall{
-						Caller: call.Caller,
-						Callee: call.Callee,
-						Line:   call.Line,
+						Caller:          call.Caller,
+						QualifiedCaller: call.QualifiedCaller,
+						Callee:          call.Callee,
+						Line:            call.Line,
 					})
 				}
 			}
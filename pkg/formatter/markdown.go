@@ -62,6 +62,9 @@ func (f *MarkdownFormatter) Format(data *PackageData) ([]byte, error) {
 		buf.WriteString("\n")
 	}
 
+	// Package summaries
+	writeMarkdownPackages(&buf, data)
+
 	// Files
 	buf.WriteString("## Files\n\n")
 	for _, file := range data.Files {
@@ -73,9 +76,13 @@ func (f *MarkdownFormatter) Format(data *PackageData) ([]byte, error) {
 
 		// 빈 파일 확인
 		isEmpty := file.Error == nil && len(file.Signatures) == 0 && !hasRenderedImports
+		if !isSigMode(data.Mode) {
+			hasRenderedImports = false
+			isEmpty = FileIsEmpty(data, file)
+		}
 
-		// SkipEmpty가 true이면 빈 파일 전체를 건너뜀
-		if data.SkipEmpty && isEmpty {
+		// SkipEmpty가 true이면 빈 파일 전체를 건너뜀 (syntax errors are still reported)
+		if data.SkipEmpty && isEmpty && len(file.Diagnostics) == 0 {
 			continue
 		}
 
@@ -83,10 +90,20 @@ func (f *MarkdownFormatter) Format(data *PackageData) ([]byte, error) {
 		buf.WriteString(file.Path)
 		buf.WriteString("\n\n")
 
+		if file.Error == nil && !isEmpty {
+			if summary := summaryText(data, file); summary != "" {
+				buf.WriteString(escapeMarkdown(summary))
+				buf.WriteString("\n\n")
+			}
+		}
+		writeMarkdownDiagnostics(&buf, file)
+
 		if file.Error != nil {
 			buf.WriteString("> **Error:** ")
 			buf.WriteString(escapeMarkdown(file.Error.Error()))
 			buf.WriteString("\n\n")
+		} else if !isSigMode(data.Mode) && !isEmpty {
+			writeMarkdownModeBody(&buf, data, file)
 		} else {
 			buf.WriteString("```")
 			buf.WriteString(file.Language)
@@ -102,33 +119,57 @@ func (f *MarkdownFormatter) Format(data *PackageData) ([]byte, error) {
 						buf.WriteString("\n")
 					}
 				}
-				// Then include signatures
-				for _, sig := range file.Signatures {
-					buf.WriteString(sig.Text)
+				// Then include signatures, indenting members under their container
+				// and preceding each with its decorators as in the source
+				walkSignatures(nestSignatures(data, file), 0, func(n *sigNode, depth int) {
+					indent := strings.Repeat("  ", depth)
+					if n.sig.Deprecated {
+						buf.WriteString(indent)
+						buf.WriteString(deprecationComment(file.Language, n.sig))
+						buf.WriteString("\n")
+					}
+					for _, dec := range n.sig.Decorators {
+						buf.WriteString(indent)
+						buf.WriteString(dec.Text)
+						buf.WriteString("\n")
+					}
+					buf.WriteString(indent)
+					buf.WriteString(strings.ReplaceAll(n.sig.Text, "\n", "\n"+indent))
 					buf.WriteString("\n")
-				}
+				})
 			}
 			buf.WriteString("```\n")
 
 			// Add docs as quotes (빈 파일이면 건너뜀)
 			if !isEmpty {
 				for _, sig := range file.Signatures {
-					if sig.Doc != "" {
+					if doc := docText(data, sig.Doc); doc != "" {
 						buf.WriteString("> ")
-						buf.WriteString(escapeMarkdown(truncateDoc(sig.Doc, data.MaxDocLength)))
+						buf.WriteString(escapeMarkdown(doc))
 						buf.WriteString("\n")
 					}
 				}
 			}
 
+			// Components section
+			if !isEmpty {
+				writeMarkdownComponents(&buf, nestSignatures(data, file))
+			}
+
+			// Symbol locations section
+			if data.IncludeLocations && !isEmpty {
+				buf.WriteString("\n#### Locations\n\n")
+				writeMarkdownLocations(&buf, nestSignatures(data, file))
+			}
+
 			// Call graph section
 			if data.IncludeCallGraph && len(file.Calls) > 0 {
 				buf.WriteString("\n#### Calls\n\n")
 				for _, call := range file.Calls {
 					buf.WriteString("- ")
-					if call.Caller != "" {
+					if caller := callerName(call); caller != "" {
 						buf.WriteString("`")
-						buf.WriteString(escapeMarkdown(call.Caller))
+						buf.WriteString(escapeMarkdown(caller))
 						buf.WriteString("`")
 					} else {
 						buf.WriteString("(top-level)")
@@ -148,6 +189,48 @@ func (f *MarkdownFormatter) Format(data *PackageData) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// writeMarkdownComponents writes a "Components" section listing the
+// components among nodes and their props and events, if any, since the code
+// block does not show their kind.
+func writeMarkdownComponents(buf *bytes.Buffer, nodes []*sigNode) {
+	var components []*sigNode
+	walkSignatures(nodes, 0, func(n *sigNode, _ int) {
+		switch NormalizeKind(n.sig.Kind) {
+		case "component", "props", "prop", "emits":
+			components = append(components, n)
+		}
+	})
+	if len(components) == 0 {
+		return
+	}
+	buf.WriteString("\n#### Components\n\n")
+	for _, n := range components {
+		buf.WriteString("- `")
+		buf.WriteString(escapeMarkdown(n.sig.Path()))
+		buf.WriteString("` (")
+		buf.WriteString(n.sig.Kind)
+		buf.WriteString(")")
+		if _, list := componentMembers(n.sig); list != "" {
+			buf.WriteString(": ")
+			buf.WriteString(escapeMarkdown(list))
+		}
+		buf.WriteString("\n")
+	}
+}
+
+// writeMarkdownLocations writes one "- `id` range (bytes start-end)" line per symbol.
+func writeMarkdownLocations(buf *bytes.Buffer, nodes []*sigNode) {
+	walkSignatures(nodes, 0, func(n *sigNode, _ int) {
+		buf.WriteString("- `")
+		buf.WriteString(escapeMarkdown(n.id))
+		buf.WriteString("` ")
+		buf.WriteString(sigRange(n.sig))
+		buf.WriteString(" (bytes ")
+		buf.WriteString(sigBytes(n.sig))
+		buf.WriteString(")\n")
+	})
+}
+
 // escapeMarkdown escapes special characters for Markdown content.
 func escapeMarkdown(s string) string {
 	// Only escape backticks to avoid breaking code blocks
@@ -0,0 +1,259 @@
+package formatter
+
+import (
+	"bytes"
+	"strings"
+)
+
+// Output modes (see PackageData.Mode).
+const (
+	// ModeSig renders signatures with their docs (default).
+	ModeSig = "sig"
+
+	// ModeOutline renders only symbol names and kinds per file.
+	ModeOutline = "outline"
+
+	// ModeDocs renders documentation comments grouped by symbol.
+	ModeDocs = "docs"
+
+	// ModeFull renders the raw (redacted) source of each file.
+	ModeFull = "full"
+)
+
+// Modes lists all supported output modes.
+var Modes = []string{ModeSig, ModeOutline, ModeDocs, ModeFull}
+
+// isSigMode reports whether mode renders signatures (the default).
+func isSigMode(mode string) bool {
+	return mode == "" || mode == ModeSig
+}
+
+// FileIsEmpty reports whether file has nothing to render in data's mode.
+// Files with errors or syntax errors are never empty.
+func FileIsEmpty(data *PackageData, file FileData) bool {
+	if file.Error != nil || len(file.Diagnostics) > 0 {
+		return false
+	}
+	switch data.Mode {
+	case ModeOutline:
+		return len(file.Signatures) == 0
+	case ModeDocs:
+		for _, sig := range file.Signatures {
+			if docText(data, sig.Doc) != "" {
+				return false
+			}
+		}
+		return true
+	case ModeFull:
+		return file.Content == ""
+	default:
+		return len(file.Signatures) == 0 && !(data.IncludeImports && len(file.RawImports) > 0)
+	}
+}
+
+// writeXMLModeBody writes the body of a file element for outline, docs and full modes.
+func writeXMLModeBody(buf *bytes.Buffer, data *PackageData, file FileData) {
+	switch data.Mode {
+	case ModeOutline:
+		writeXMLOutline(buf, data, nestSignatures(data, file), "      ")
+	case ModeDocs:
+		ids := symbolIDs(data, file)
+		for i, sig := range file.Signatures {
+			doc := docText(data, sig.Doc)
+			if doc == "" {
+				continue
+			}
+			buf.WriteString("      <doc")
+			writeXMLAttr(buf, "kind", sig.Kind)
+			writeXMLAttr(buf, "name", sig.Path())
+			if data.IncludeLocations {
+				writeXMLLocation(buf, &sigNode{sig: sig, id: ids[i]})
+			}
+			buf.WriteByte('>')
+			buf.WriteString(escapeXML(doc))
+			buf.WriteString("</doc>\n")
+		}
+	case ModeFull:
+		buf.WriteString("      <source>")
+		buf.WriteString(escapeXML(file.Content))
+		buf.WriteString("</source>\n")
+	}
+}
+
+// writeXMLOutline writes a <symbol> element per node, with members as child elements.
+func writeXMLOutline(buf *bytes.Buffer, data *PackageData, nodes []*sigNode, indent string) {
+	for _, n := range nodes {
+		buf.WriteString(indent)
+		buf.WriteString("<symbol")
+		writeXMLAttr(buf, "kind", n.sig.Kind)
+		writeXMLAttr(buf, "name", n.sig.Name)
+		if n.sig.Deprecated {
+			writeXMLAttr(buf, "deprecated", "true")
+		}
+		if data.IncludeLocations {
+			writeXMLLocation(buf, n)
+		}
+		if len(n.members) == 0 {
+			buf.WriteString(" />\n")
+			continue
+		}
+		buf.WriteString(">\n")
+		writeXMLOutline(buf, data, n.members, indent+"  ")
+		buf.WriteString(indent)
+		buf.WriteString("</symbol>\n")
+	}
+}
+
+// writeMarkdownModeBody writes the body of a file section for outline, docs and full modes.
+func writeMarkdownModeBody(buf *bytes.Buffer, data *PackageData, file FileData) {
+	switch data.Mode {
+	case ModeOutline:
+		walkSignatures(nestSignatures(data, file), 0, func(n *sigNode, depth int) {
+			buf.WriteString(strings.Repeat("  ", depth))
+			buf.WriteString("- ")
+			buf.WriteString(n.sig.Kind)
+			buf.WriteString(" `")
+			buf.WriteString(escapeMarkdown(n.sig.Name))
+			buf.WriteString("`")
+			if n.sig.Deprecated {
+				buf.WriteString(" *(deprecated)*")
+			}
+			if data.IncludeLocations {
+				buf.WriteString(" ")
+				buf.WriteString(sigRange(n.sig))
+				buf.WriteString(" `")
+				buf.WriteString(escapeMarkdown(n.id))
+				buf.WriteString("`")
+			}
+			buf.WriteString("\n")
+		})
+	case ModeDocs:
+		ids := symbolIDs(data, file)
+		for i, sig := range file.Signatures {
+			doc := docText(data, sig.Doc)
+			if doc == "" {
+				continue
+			}
+			buf.WriteString("#### `")
+			buf.WriteString(escapeMarkdown(sig.Path()))
+			buf.WriteString("` (")
+			buf.WriteString(sig.Kind)
+			buf.WriteString(")\n\n")
+			if data.IncludeLocations {
+				buf.WriteString("`")
+				buf.WriteString(escapeMarkdown(ids[i]))
+				buf.WriteString("` ")
+				buf.WriteString(sigRange(sig))
+				buf.WriteString("\n\n")
+			}
+			buf.WriteString(doc)
+			buf.WriteString("\n\n")
+		}
+	case ModeFull:
+		fence := codeFence(file.Content)
+		buf.WriteString(fence)
+		buf.WriteString(file.Language)
+		buf.WriteByte('\n')
+		buf.WriteString(file.Content)
+		if !strings.HasSuffix(file.Content, "\n") {
+			buf.WriteByte('\n')
+		}
+		buf.WriteString(fence)
+		buf.WriteByte('\n')
+	}
+}
+
+// codeFence returns a backtick fence longer than any backtick run in content,
+// so source containing ``` cannot terminate the code block early.
+func codeFence(content string) string {
+	longest, run := 0, 0
+	for i := 0; i < len(content); i++ {
+		if content[i] == '`' {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	return strings.Repeat("`", max(3, longest+1))
+}
+
+// jsonSymbol represents a symbol in outline mode JSON output.
+type jsonSymbol struct {
+	ID            string       `json:"id"`
+	Kind          string       `json:"kind"`
+	Name          string       `json:"name"`
+	QualifiedName string       `json:"qualifiedName,omitempty"`
+	Line          int          `json:"line,omitempty"`
+	EndLine       int          `json:"endLine,omitempty"`
+	Deprecated    bool         `json:"deprecated,omitempty"`
+	Members       []jsonSymbol `json:"members,omitempty"`
+}
+
+// jsonSymbols converts nodes into outline symbols with nested members.
+func jsonSymbols(nodes []*sigNode) []jsonSymbol {
+	symbols := make([]jsonSymbol, 0, len(nodes))
+	for _, n := range nodes {
+		js := jsonSymbol{
+			ID:            n.id,
+			Kind:          n.sig.Kind,
+			Name:          n.sig.Name,
+			QualifiedName: n.sig.QualifiedName,
+			Line:          n.sig.Line,
+			EndLine:       n.sig.EndLine,
+			Deprecated:    n.sig.Deprecated,
+		}
+		if len(n.members) > 0 {
+			js.Members = jsonSymbols(n.members)
+		}
+		symbols = append(symbols, js)
+	}
+	return symbols
+}
+
+// jsonDoc represents a documented symbol in docs mode JSON output.
+type jsonDoc struct {
+	ID   string `json:"id"`
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	Line int    `json:"line,omitempty"`
+	Doc  string `json:"doc"`
+}
+
+// fillJSONModeBody fills jf for outline, docs and full modes.
+func fillJSONModeBody(jf *jsonFile, data *PackageData, file FileData) {
+	switch data.Mode {
+	case ModeOutline:
+		if len(file.Signatures) > 0 {
+			jf.Symbols = jsonSymbols(nestSignatures(data, file))
+		}
+	case ModeDocs:
+		ids := symbolIDs(data, file)
+		for i, sig := range file.Signatures {
+			if doc := docText(data, sig.Doc); doc != "" {
+				jf.Docs = append(jf.Docs, jsonDoc{
+					ID:   ids[i],
+					Kind: sig.Kind,
+					Name: sig.Path(),
+					Line: sig.Line,
+					Doc:  doc,
+				})
+			}
+		}
+	case ModeFull:
+		jf.Content = file.Content
+	}
+}
+
+// modeSchemaTags returns the XML schema entries for tags specific to a mode.
+func modeSchemaTags(mode string) []string {
+	switch mode {
+	case ModeOutline:
+		return []string{`<tag name="symbol" description="Symbol (kind, name attributes; members nested as child symbols)" />`}
+	case ModeDocs:
+		return []string{`<tag name="doc" description="Documentation comment (kind attribute, name attribute with container path)" />`}
+	case ModeFull:
+		return []string{`<tag name="source" description="Raw file source (secrets redacted)" />`}
+	}
+	return nil
+}
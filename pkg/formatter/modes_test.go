@@ -0,0 +1,227 @@
+package formatter
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/indigo-net/Brf.it/pkg/parser"
+)
+
+func sampleModeData(mode string) *PackageData {
+	return &PackageData{
+		RootPath:       "/project",
+		Mode:           mode,
+		IncludeImports: true,
+		SkipEmpty:      true,
+		Files: []FileData{
+			{
+				Path:     "/project/api.go",
+				Language: "go",
+				Signatures: []parser.Signature{
+					{Name: "Less", Kind: "function", Text: "func Less(a, b int) bool", Doc: "Less reports a < b.", Line: 3},
+					{Name: "Point", Kind: "struct", Text: "type Point struct", Line: 7},
+				},
+				RawImports: []string{`import "fmt"`},
+				Content:    "package api\n\n// Less reports a < b.\nfunc Less(a, b int) bool { return a < b }\n",
+			},
+			{
+				Path:     "/project/empty.go",
+				Language: "go",
+				Content:  "package api\n",
+			},
+		},
+		TotalSignatures: 2,
+	}
+}
+
+func TestFileIsEmpty(t *testing.T) {
+	withSigs := FileData{Signatures: []parser.Signature{{Name: "A"}}}
+	withDoc := FileData{Signatures: []parser.Signature{{Name: "A", Doc: "A does."}}}
+	importsOnly := FileData{RawImports: []string{`import "fmt"`}}
+	withContent := FileData{Content: "package a\n"}
+
+	tests := []struct {
+		name string
+		mode string
+		file FileData
+		want bool
+	}{
+		{"sig with signatures", ModeSig, withSigs, false},
+		{"sig imports only", ModeSig, importsOnly, false},
+		{"sig nothing", "", FileData{}, true},
+		{"outline with signatures", ModeOutline, withSigs, false},
+		{"outline imports only", ModeOutline, importsOnly, true},
+		{"docs without docs", ModeDocs, withSigs, true},
+		{"docs with docs", ModeDocs, withDoc, false},
+		{"full with content", ModeFull, withContent, false},
+		{"full without content", ModeFull, withSigs, true},
+		{"error is never empty", ModeDocs, FileData{Error: errors.New("parse failed")}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := &PackageData{Mode: tt.mode, IncludeImports: true}
+			if got := FileIsEmpty(data, tt.file); got != tt.want {
+				t.Errorf("FileIsEmpty() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestXMLFormatterModes(t *testing.T) {
+	tests := []struct {
+		mode    string
+		want    []string
+		notWant []string
+	}{
+		{
+			mode:    ModeOutline,
+			want:    []string{`<symbol kind="function" name="Less" />`, `<symbol kind="struct" name="Point" />`, `<tag name="symbol"`},
+			notWant: []string{"func Less", "<imports>", "empty.go", "<doc"},
+		},
+		{
+			mode:    ModeDocs,
+			want:    []string{`<doc kind="function" name="Less">Less reports a &lt; b.</doc>`},
+			notWant: []string{"Point", "func Less", "empty.go"},
+		},
+		{
+			mode:    ModeFull,
+			want:    []string{"<source>package api\n\n// Less reports a &lt; b.\nfunc Less(a, b int) bool { return a &lt; b }\n</source>", "empty.go", `<tag name="source"`},
+			notWant: []string{"<function", "<imports>"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			out, err := NewXMLFormatter().Format(sampleModeData(tt.mode))
+			if err != nil {
+				t.Fatalf("Format failed: %v", err)
+			}
+			content := string(out)
+			if err := xml.Unmarshal(out, new(struct{})); err != nil {
+				t.Fatalf("output is not well-formed XML: %v\n%s", err, content)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(content, w) {
+					t.Errorf("expected %q in output:\n%s", w, content)
+				}
+			}
+			for _, w := range tt.notWant {
+				if strings.Contains(content, w) {
+					t.Errorf("unexpected %q in output:\n%s", w, content)
+				}
+			}
+		})
+	}
+}
+
+func TestMarkdownFormatterModes(t *testing.T) {
+	tests := []struct {
+		mode    string
+		want    []string
+		notWant []string
+	}{
+		{
+			mode:    ModeOutline,
+			want:    []string{"- function `Less`\n", "- struct `Point`\n"},
+			notWant: []string{"func Less", "empty.go"},
+		},
+		{
+			mode:    ModeDocs,
+			want:    []string{"#### `Less` (function)\n\nLess reports a < b.\n"},
+			notWant: []string{"Point", "empty.go"},
+		},
+		{
+			mode:    ModeFull,
+			want:    []string{"```go\npackage api\n\n// Less reports a < b.\n", "empty.go"},
+			notWant: []string{`import "fmt"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			out, err := NewMarkdownFormatter().Format(sampleModeData(tt.mode))
+			if err != nil {
+				t.Fatalf("Format failed: %v", err)
+			}
+			content := string(out)
+			for _, w := range tt.want {
+				if !strings.Contains(content, w) {
+					t.Errorf("expected %q in output:\n%s", w, content)
+				}
+			}
+			for _, w := range tt.notWant {
+				if strings.Contains(content, w) {
+					t.Errorf("unexpected %q in output:\n%s", w, content)
+				}
+			}
+		})
+	}
+}
+
+func TestJSONFormatterModes(t *testing.T) {
+	decode := func(t *testing.T, mode string) jsonOutput {
+		t.Helper()
+		out, err := NewJSONFormatter().Format(sampleModeData(mode))
+		if err != nil {
+			t.Fatalf("Format failed: %v", err)
+		}
+		var parsed jsonOutput
+		if err := json.Unmarshal(out, &parsed); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, out)
+		}
+		return parsed
+	}
+
+	t.Run(ModeOutline, func(t *testing.T) {
+		parsed := decode(t, ModeOutline)
+		if len(parsed.Files) != 1 {
+			t.Fatalf("expected 1 file, got %d", len(parsed.Files))
+		}
+		f := parsed.Files[0]
+		if len(f.Symbols) != 2 || f.Symbols[0].Kind != "function" || f.Symbols[0].Name != "Less" {
+			t.Errorf("unexpected symbols: %+v", f.Symbols)
+		}
+		if len(f.Signatures) != 0 || len(f.Imports) != 0 {
+			t.Errorf("expected no signatures or imports, got %+v", f)
+		}
+	})
+
+	t.Run(ModeDocs, func(t *testing.T) {
+		parsed := decode(t, ModeDocs)
+		if len(parsed.Files) != 1 {
+			t.Fatalf("expected 1 file, got %d", len(parsed.Files))
+		}
+		want := []jsonDoc{{ID: "go:api.go#Less:function", Kind: "function", Name: "Less", Line: 3, Doc: "Less reports a < b."}}
+		if got := parsed.Files[0].Docs; len(got) != 1 || got[0] != want[0] {
+			t.Errorf("docs = %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run(ModeFull, func(t *testing.T) {
+		parsed := decode(t, ModeFull)
+		if len(parsed.Files) != 2 {
+			t.Fatalf("expected 2 files, got %d", len(parsed.Files))
+		}
+		if !strings.HasPrefix(parsed.Files[0].Content, "package api\n\n// Less") {
+			t.Errorf("unexpected content: %q", parsed.Files[0].Content)
+		}
+	})
+}
+
+func TestCodeFence(t *testing.T) {
+	tests := []struct {
+		content string
+		want    string
+	}{
+		{"plain", "```"},
+		{"a `b` c", "```"},
+		{"```go\n```", "````"},
+		{"`````", "``````"},
+	}
+	for _, tt := range tests {
+		if got := codeFence(tt.content); got != tt.want {
+			t.Errorf("codeFence(%q) = %q, want %q", tt.content, got, tt.want)
+		}
+	}
+}
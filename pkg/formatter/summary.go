@@ -0,0 +1,53 @@
+package formatter
+
+import (
+	"bytes"
+)
+
+// summaryText returns the summary rendered ahead of file's signatures: its
+// Summary in data's doc style. It is "" in full mode, where the source
+// holds it, and when the summary is listed as its package's.
+func summaryText(data *PackageData, file FileData) string {
+	if file.Summary == "" || data.Mode == ModeFull {
+		return ""
+	}
+	for _, pkg := range data.Packages {
+		if pkg.Source == file.Path {
+			return ""
+		}
+	}
+	return docText(data, file.Summary)
+}
+
+// writeXMLPackages writes the <packages> section listing package summaries.
+func writeXMLPackages(buf *bytes.Buffer, data *PackageData) {
+	if len(data.Packages) == 0 {
+		return
+	}
+	buf.WriteString("  <packages>\n")
+	for _, pkg := range data.Packages {
+		buf.WriteString("    <package")
+		writeXMLAttr(buf, "path", pkg.Path)
+		buf.WriteByte('>')
+		buf.WriteString(escapeXML(docText(data, pkg.Summary)))
+		buf.WriteString("</package>\n")
+	}
+	buf.WriteString("  </packages>\n")
+}
+
+// writeMarkdownPackages writes the "## Packages" section listing package
+// summaries.
+func writeMarkdownPackages(buf *bytes.Buffer, data *PackageData) {
+	if len(data.Packages) == 0 {
+		return
+	}
+	buf.WriteString("## Packages\n\n")
+	for _, pkg := range data.Packages {
+		buf.WriteString("- `")
+		buf.WriteString(escapeMarkdown(pkg.Path))
+		buf.WriteString("`: ")
+		buf.WriteString(escapeMarkdown(docText(data, pkg.Summary)))
+		buf.WriteString("\n")
+	}
+	buf.WriteString("\n")
+}
@@ -0,0 +1,64 @@
+package formatter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/indigo-net/Brf.it/pkg/parser"
+)
+
+func TestSummaryOutput(t *testing.T) {
+	data := &PackageData{
+		Files: []FileData{
+			{
+				Path:       "pkg/foo/doc.go",
+				Language:   "go",
+				Summary:    "Package foo provides fooing.",
+				Signatures: []parser.Signature{{Name: "Foo", Kind: "function", Text: "func Foo()"}},
+			},
+			{
+				Path:       "web/util.ts",
+				Language:   "typescript",
+				Summary:    "Routing helpers.",
+				Signatures: []parser.Signature{{Name: "route", Kind: "function", Text: "function route()"}},
+			},
+		},
+		Packages: []PackageSummary{{Path: "pkg/foo", Summary: "Package foo provides fooing.", Source: "pkg/foo/doc.go"}},
+	}
+
+	tests := []struct {
+		formatter Formatter
+		want      []string
+	}{
+		{NewXMLFormatter(), []string{
+			`<package path="pkg/foo">Package foo provides fooing.</package>`,
+			"<summary>Routing helpers.</summary>\n      <function>function route()</function>",
+		}},
+		{NewMarkdownFormatter(), []string{
+			"## Packages\n\n- `pkg/foo`: Package foo provides fooing.",
+			"### web/util.ts\n\nRouting helpers.\n\n```typescript",
+		}},
+		{NewJSONFormatter(), []string{
+			`"packages":[{"path":"pkg/foo","summary":"Package foo provides fooing.","source":"pkg/foo/doc.go"}]`,
+			`"language":"typescript","summary":"Routing helpers."`,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.formatter.Name(), func(t *testing.T) {
+			out, err := tt.formatter.Format(data)
+			if err != nil {
+				t.Fatalf("Format: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(out), want) {
+					t.Errorf("output missing %q:\n%s", want, out)
+				}
+			}
+			// The package summary is not repeated under its source file
+			if n := strings.Count(string(out), "Package foo provides fooing."); n != 1 {
+				t.Errorf("package summary rendered %d times, want 1:\n%s", n, out)
+			}
+		})
+	}
+}
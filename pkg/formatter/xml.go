@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"strconv"
 	"strings"
+
+	"github.com/indigo-net/Brf.it/pkg/parser"
 )
 
 // XMLFormatter implements Formatter for XML output.
@@ -65,17 +67,48 @@ func (f *XMLFormatter) Format(data *PackageData) ([]byte, error) {
 			buf.WriteString(`      <tag name="function" description="Function, method, or constructor declaration" />` + "\n")
 			buf.WriteString(`      <tag name="type" description="Type, class, interface, struct, or enum declaration" />` + "\n")
 			buf.WriteString(`      <tag name="variable" description="Variable, constant, or field declaration" />` + "\n")
+			buf.WriteString(`      <tag name="component" description="UI component declaration (e.g., a React function component)" />` + "\n")
+			buf.WriteString(`      <tag name="props" description="Props declaration of a component (props attribute: the props)" />` + "\n")
+			buf.WriteString(`      <tag name="prop" description="Single component prop (e.g., Svelte export let)" />` + "\n")
+			buf.WriteString(`      <tag name="emits" description="Events declaration of a component (events attribute: the events)" />` + "\n")
 			buf.WriteString(`      <tag name="signature" description="Fallback for unknown declaration kinds" />` + "\n")
 			buf.WriteString(`      <tag name="imports" description="Raw import/export statements (verbatim text)" />` + "\n")
 			buf.WriteString(`      <tag name="call" description="Function/method call reference within the file" />` + "\n")
+			buf.WriteString(`      <tag name="decorator" description="Decorator, annotation or attribute of the preceding declaration" />` + "\n")
 			buf.WriteString(`      <tag name="doc" description="Documentation comment" />` + "\n")
+			buf.WriteString(`      <tag name="members" description="Declarations nested inside the preceding one (of attribute: container path)" />` + "\n")
 			buf.WriteString(`      <tag name="error" description="Parse error message" />` + "\n")
+			buf.WriteString(`      <tag name="diagnostic" description="Syntax error (loc attribute: line:column-endLine:endColumn); signatures may be missing around it" />` + "\n")
+			buf.WriteString(`      <tag name="summary" description="File summary from its package comment, module docstring or header comment" />` + "\n")
+			buf.WriteString(`      <tag name="packages" description="Directory package/module summaries (package path attribute)" />` + "\n")
+			buf.WriteString(`      <attribute name="deprecated" description="Deprecated symbol: the deprecation note, or true" />` + "\n")
+			buf.WriteString(`      <attribute name="props" description="Props of a props declaration as name: type, separated by ';'" />` + "\n")
+			buf.WriteString(`      <attribute name="events" description="Events of an emits declaration as name: payload, separated by ';'" />` + "\n")
+			for _, tag := range modeSchemaTags(data.Mode) {
+				buf.WriteString("      " + tag + "\n")
+			}
+			if data.IncludeLocations {
+				buf.WriteString(`      <attribute name="id" description="Stable symbol ID (language:path#qualified.name:kind)" />` + "\n")
+				buf.WriteString(`      <attribute name="loc" description="Source range line:column-endLine:endColumn (1-indexed)" />` + "\n")
+				buf.WriteString(`      <attribute name="bytes" description="Byte offsets start-end (end exclusive)" />` + "\n")
+			}
+			if data.IncludeStructure {
+				buf.WriteString(`      <attribute name="params" description="Parameters as name: type = default, separated by ';' (... marks variadic)" />` + "\n")
+				buf.WriteString(`      <attribute name="returns" description="Return types, separated by ';'" />` + "\n")
+				buf.WriteString(`      <attribute name="typeParams" description="Generic type parameters as name: constraint = default, separated by ';'" />` + "\n")
+				buf.WriteString(`      <attribute name="receiver" description="Go method receiver as name: type" />` + "\n")
+				buf.WriteString(`      <attribute name="modifiers" description="Declaration modifiers (static, async, abstract, ...), space-separated" />` + "\n")
+				buf.WriteString(`      <attribute name="visibility" description="Visibility: public, protected, internal, package, file or private" />` + "\n")
+			}
 			buf.WriteString("    </schema>\n")
 		}
 
 		buf.WriteString("  </metadata>\n")
 	}
 
+	// Package summaries
+	writeXMLPackages(&buf, data)
+
 	// Files section
 	buf.WriteString("  <files>\n")
 	for _, file := range data.Files {
@@ -87,9 +120,14 @@ func (f *XMLFormatter) Format(data *PackageData) ([]byte, error) {
 
 		// 빈 파일 확인
 		isEmpty := file.Error == nil && len(file.Signatures) == 0 && !hasRenderedImports
+		if !isSigMode(data.Mode) {
+			// Imports are part of the source in full mode and omitted otherwise
+			hasRenderedImports = false
+			isEmpty = FileIsEmpty(data, file)
+		}
 
-		// SkipEmpty가 true이면 빈 파일 전체를 건너뜀
-		if data.SkipEmpty && isEmpty {
+		// SkipEmpty가 true이면 빈 파일 전체를 건너뜀 (syntax errors are still reported)
+		if data.SkipEmpty && isEmpty && len(file.Diagnostics) == 0 {
 			continue
 		}
 
@@ -99,6 +137,16 @@ func (f *XMLFormatter) Format(data *PackageData) ([]byte, error) {
 		buf.WriteString(escapeXML(file.Language))
 		buf.WriteString("\">\n")
 
+		// Render the file summary ahead of its contents
+		if file.Error == nil && !isEmpty {
+			if summary := summaryText(data, file); summary != "" {
+				buf.WriteString("      <summary>")
+				buf.WriteString(escapeXML(summary))
+				buf.WriteString("</summary>\n")
+			}
+		}
+		writeXMLDiagnostics(&buf, file)
+
 		// Render imports
 		if hasRenderedImports {
 			buf.WriteString("      <imports>")
@@ -113,32 +161,19 @@ func (f *XMLFormatter) Format(data *PackageData) ([]byte, error) {
 		} else {
 			if isEmpty {
 				buf.WriteString("      <!-- empty -->\n")
+			} else if !isSigMode(data.Mode) {
+				writeXMLModeBody(&buf, data, file)
 			} else {
-				for _, sig := range file.Signatures {
-					tag := kindToTag(sig.Kind)
-					buf.WriteString("      <")
-					buf.WriteString(tag)
-					buf.WriteByte('>')
-					buf.WriteString(escapeXML(sig.Text))
-					buf.WriteString("</")
-					buf.WriteString(tag)
-					buf.WriteString(">\n")
-
-					if sig.Doc != "" {
-						buf.WriteString("      <doc>")
-						buf.WriteString(escapeXML(truncateDoc(sig.Doc, data.MaxDocLength)))
-						buf.WriteString("</doc>\n")
-					}
-				}
+				writeXMLSignatures(&buf, data, nestSignatures(data, file), "      ")
 
 				// Call graph section
 				if data.IncludeCallGraph && len(file.Calls) > 0 {
 					buf.WriteString("      <calls>\n")
 					for _, call := range file.Calls {
 						buf.WriteString("        <call")
-						if call.Caller != "" {
+						if caller := callerName(call); caller != "" {
 							buf.WriteString(" caller=\"")
-							buf.WriteString(escapeXML(call.Caller))
+							buf.WriteString(escapeXML(caller))
 							buf.WriteByte('"')
 						}
 						buf.WriteString(" callee=\"")
@@ -161,6 +196,89 @@ func (f *XMLFormatter) Format(data *PackageData) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// writeXMLSignatures writes the signature elements of nodes, wrapping the
+// members of each container in a <members> element.
+func writeXMLSignatures(buf *bytes.Buffer, data *PackageData, nodes []*sigNode, indent string) {
+	for _, n := range nodes {
+		sig := n.sig
+		tag := kindToTag(sig.Kind)
+		buf.WriteString(indent)
+		buf.WriteByte('<')
+		buf.WriteString(tag)
+		if data.IncludeLocations {
+			writeXMLLocation(buf, n)
+		}
+		if data.IncludeStructure {
+			writeXMLStructure(buf, sig)
+		}
+		if attr, list := componentMembers(sig); list != "" {
+			writeXMLAttr(buf, attr, list)
+		}
+		if sig.Deprecated {
+			writeXMLDeprecated(buf, sig)
+		}
+		buf.WriteByte('>')
+		buf.WriteString(escapeXML(sig.Text))
+		buf.WriteString("</")
+		buf.WriteString(tag)
+		buf.WriteString(">\n")
+
+		for _, dec := range sig.Decorators {
+			buf.WriteString(indent)
+			buf.WriteString("<decorator>")
+			buf.WriteString(escapeXML(dec.Text))
+			buf.WriteString("</decorator>\n")
+		}
+
+		if doc := docText(data, sig.Doc); doc != "" {
+			buf.WriteString(indent)
+			buf.WriteString("<doc>")
+			buf.WriteString(escapeXML(doc))
+			buf.WriteString("</doc>\n")
+		}
+
+		if len(n.members) > 0 {
+			buf.WriteString(indent)
+			buf.WriteString("<members")
+			writeXMLAttr(buf, "of", sig.Path())
+			buf.WriteString(">\n")
+			writeXMLSignatures(buf, data, n.members, indent+"  ")
+			buf.WriteString(indent)
+			buf.WriteString("</members>\n")
+		}
+	}
+}
+
+// writeXMLLocation writes the id, loc and bytes attributes of a symbol.
+func writeXMLLocation(buf *bytes.Buffer, n *sigNode) {
+	writeXMLAttr(buf, "id", n.id)
+	writeXMLAttr(buf, "loc", sigRange(n.sig))
+	writeXMLAttr(buf, "bytes", sigBytes(n.sig))
+}
+
+// writeXMLStructure writes the params, returns, typeParams, receiver,
+// modifiers and visibility attributes of a signature, skipping empty ones.
+func writeXMLStructure(buf *bytes.Buffer, sig parser.Signature) {
+	if len(sig.Params) > 0 {
+		writeXMLAttr(buf, "params", formatParams(sig.Params))
+	}
+	if len(sig.Returns) > 0 {
+		writeXMLAttr(buf, "returns", strings.Join(sig.Returns, "; "))
+	}
+	if len(sig.TypeParams) > 0 {
+		writeXMLAttr(buf, "typeParams", formatTypeParams(sig.TypeParams))
+	}
+	if sig.Receiver != nil {
+		writeXMLAttr(buf, "receiver", formatParam(*sig.Receiver))
+	}
+	if len(sig.Modifiers) > 0 {
+		writeXMLAttr(buf, "modifiers", strings.Join(sig.Modifiers, " "))
+	}
+	if sig.Visibility != "" {
+		writeXMLAttr(buf, "visibility", string(sig.Visibility))
+	}
+}
+
 // escapeXML escapes special characters for XML content.
 // Optimized to scan the string only once instead of 5 sequential ReplaceAll calls.
 func escapeXML(s string) string {
@@ -202,14 +320,24 @@ func escapeXML(s string) string {
 }
 
 // kindToTag maps a signature Kind to the appropriate XML tag name.
-// It uses normalizeKind for the common mapping and falls back to "signature"
+// It uses NormalizeKind for the common mapping and falls back to "signature"
 // for unknown or empty kinds.
 func kindToTag(kind string) string {
-	result := normalizeKind(kind)
+	result := NormalizeKind(kind)
 	switch result {
-	case "function", "type", "variable":
+	case "function", "type", "variable", "component", "props", "prop", "emits":
 		return result
 	default:
 		return "signature" // fallback for empty or unknown kinds
 	}
 }
+
+// writeXMLDeprecated writes the deprecated attribute of a deprecated
+// signature: its deprecation note, or "true" when it has none.
+func writeXMLDeprecated(buf *bytes.Buffer, sig parser.Signature) {
+	note := sig.DeprecationNote
+	if note == "" {
+		note = "true"
+	}
+	writeXMLAttr(buf, "deprecated", note)
+}
@@ -0,0 +1,583 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+)
+
+// DocComment is a documentation comment split into its parts.
+type DocComment struct {
+	// Description is the free text ahead of any tag or section.
+	Description string
+
+	// Params are the documented parameters, in order.
+	Params []DocParam
+
+	// Returns describes the return value.
+	Returns string
+
+	// Throws are the documented exceptions or errors; Name holds the type.
+	Throws []DocParam
+
+	// Deprecated reports whether the doc marks the declaration deprecated
+	// ("@deprecated", "Deprecated:", ".. deprecated::").
+	Deprecated bool
+
+	// DeprecationNote is the text given with the deprecation, if any.
+	DeprecationNote string
+
+	// Examples are the usage examples, one entry per example or section.
+	Examples []string
+
+	// Sections are the other tags and headed sections (e.g., "since",
+	// "Panics", "Note"), in order.
+	Sections []DocSection
+}
+
+// DocParam is a documented parameter or exception.
+type DocParam struct {
+	// Name is the parameter name, or the type of an exception.
+	Name string
+
+	// Type is the documented type, if any (e.g., "string" for JSDoc
+	// "{string}").
+	Type string
+
+	// Description is the text documenting the parameter.
+	Description string
+}
+
+// DocSection is a tag or headed section of a doc comment.
+type DocSection struct {
+	// Title is the tag name or heading (e.g., "since", "Panics").
+	Title string
+
+	// Body is the text of the section.
+	Body string
+}
+
+// Summary returns the first sentence of the description.
+func (d DocComment) Summary() string {
+	para, _, _ := strings.Cut(strings.TrimSpace(d.Description), "\n\n")
+	para = strings.Join(strings.Fields(para), " ")
+	for i := 0; i < len(para); i++ {
+		switch para[i] {
+		case '.', '!', '?':
+			if i+1 < len(para) && para[i+1] != ' ' {
+				continue
+			}
+			if para[i] == '.' && (strings.HasSuffix(para[:i], "e.g") || strings.HasSuffix(para[:i], "i.e")) {
+				continue
+			}
+			return para[:i+1]
+		}
+	}
+	return para
+}
+
+// HasTags reports whether the doc has any part besides its description.
+func (d DocComment) HasTags() bool {
+	return len(d.Params) > 0 || d.Returns != "" || len(d.Throws) > 0 || d.Deprecated ||
+		len(d.Examples) > 0 || len(d.Sections) > 0
+}
+
+// docSectionKind is what the lines of a doc section document.
+type docSectionKind int
+
+const (
+	docDescription docSectionKind = iota
+	docParams
+	docReturns
+	docThrows
+	docExamples
+	docDeprecated
+	docOther
+)
+
+// docHeadings maps section headings (Google and NumPy docstrings, Rust and
+// Go doc headings) to their kind. Lookups are case-insensitive.
+var docHeadings = map[string]docSectionKind{
+	"args":       docParams,
+	"arguments":  docParams,
+	"parameters": docParams,
+	"params":     docParams,
+	"returns":    docReturns,
+	"return":     docReturns,
+	"yields":     docReturns,
+	"raises":     docThrows,
+	"throws":     docThrows,
+	"errors":     docThrows,
+	"example":    docExamples,
+	"examples":   docExamples,
+	"deprecated": docDeprecated,
+	"note":       docOther,
+	"notes":      docOther,
+	"warning":    docOther,
+	"warnings":   docOther,
+	"see also":   docOther,
+	"attributes": docOther,
+	"todo":       docOther,
+	"references": docOther,
+	"panics":     docOther,
+	"safety":     docOther,
+}
+
+// docTagKinds maps tags ("@param", ":param:") to their kind. Other tags
+// become sections.
+var docTagKinds = map[string]docSectionKind{
+	"param":      docParams,
+	"parameter":  docParams,
+	"arg":        docParams,
+	"argument":   docParams,
+	"key":        docParams,
+	"keyword":    docParams,
+	"return":     docReturns,
+	"returns":    docReturns,
+	"yield":      docReturns,
+	"yields":     docReturns,
+	"throws":     docThrows,
+	"throw":      docThrows,
+	"exception":  docThrows,
+	"raise":      docThrows,
+	"raises":     docThrows,
+	"example":    docExamples,
+	"deprecated": docDeprecated,
+}
+
+var (
+	// docTagLine matches JSDoc, Javadoc, PHPDoc and KDoc tags ("@param x ...").
+	docTagLine = regexp.MustCompile(`^@(\w+)(?:\s+(.*))?$`)
+
+	// sphinxFieldLine matches Sphinx fields (":param int x: ...").
+	sphinxFieldLine = regexp.MustCompile(`^:(\w+)((?:\s+[^:]+)?):\s*(.*)$`)
+
+	// markdownHeading matches Markdown headings ("# Examples").
+	markdownHeading = regexp.MustCompile(`^#{1,3}\s+(\S.*?)\s*$`)
+
+	// googleHeading matches Google docstring section headings ("Args:").
+	googleHeading = regexp.MustCompile(`^([A-Z][A-Za-z ]*):\s*$`)
+
+	// numpyUnderline matches the underline of a NumPy section heading.
+	numpyUnderline = regexp.MustCompile(`^-{3,}\s*$`)
+
+	// googleParam matches a Google docstring parameter ("x (int): ...").
+	googleParam = regexp.MustCompile(`^(\*{0,2}[\w.]+)\s*(?:\(([^)]*)\))?\s*:\s*(.*)$`)
+
+	// numpyParam matches a NumPy docstring parameter ("x : int").
+	numpyParam = regexp.MustCompile(`^(\*{0,2}[\w.]+(?:\s*,\s*\*{0,2}[\w.]+)*)\s*:\s*(.*)$`)
+
+	// listParam matches a parameter list item ("* `x` - ...", "- x: ...").
+	listParam = regexp.MustCompile("^[*-]\\s+`?([\\w.]+)`?\\s*(?:[-:–]\\s*(.*))?$")
+
+	// deprecatedLine matches a Go "Deprecated: ..." paragraph or a Sphinx
+	// ".. deprecated::" directive.
+	deprecatedLine = regexp.MustCompile(`^(?:Deprecated:|\.\. deprecated::)\s*(.*)$`)
+)
+
+// docSectionStyle is how a doc section was opened, which decides where it
+// ends and how its list items are laid out.
+type docSectionStyle int
+
+const (
+	// styleTag sections ("@param", ":param x:") run to the next tag or heading.
+	styleTag docSectionStyle = iota
+
+	// styleGoogle sections ("Args:") end at the first line indented no
+	// deeper than their heading.
+	styleGoogle
+
+	// styleNumPy sections (a heading underlined with dashes) list their
+	// items at the heading's indentation, descriptions indented below.
+	styleNumPy
+
+	// styleHeading sections ("# Examples") run to the next heading.
+	styleHeading
+)
+
+// docParser holds the state of ParseDoc.
+type docParser struct {
+	doc         DocComment
+	description []string
+	kind        docSectionKind
+	style       docSectionStyle
+	indent      int     // indentation of the section heading
+	itemIndent  int     // indentation of list items, -1 before the first
+	target      *string // text that continuation lines extend
+}
+
+// ParseDoc splits a doc comment into its description, parameters, return
+// value, exceptions, deprecation, examples and other sections. It reads
+// JSDoc/Javadoc/PHPDoc tags ("@param"), Sphinx fields (":param x:"),
+// Google and NumPy docstring sections ("Args:"), Markdown headings as used
+// by Rust docs ("# Panics") and Go "Deprecated:" paragraphs. Javadoc HTML
+// is converted to plain text first.
+func ParseDoc(text string) DocComment {
+	p := &docParser{}
+	inFence := false
+	lines := strings.Split(DocPlainText(text), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], " \t")
+		trimmed := strings.TrimSpace(line)
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+
+		if strings.HasPrefix(trimmed, "```") {
+			inFence = !inFence
+			p.addText(line)
+			continue
+		}
+		if inFence {
+			p.addText(line)
+			continue
+		}
+
+		if i+1 < len(lines) && numpyUnderline.MatchString(strings.TrimSpace(lines[i+1])) {
+			if kind, ok := docHeadings[strings.ToLower(trimmed)]; ok {
+				p.startSection(kind, styleNumPy, trimmed, indent)
+				i++
+				continue
+			}
+		}
+		if m := googleHeading.FindStringSubmatch(trimmed); m != nil {
+			if kind, ok := docHeadings[strings.ToLower(m[1])]; ok {
+				p.startSection(kind, styleGoogle, m[1], indent)
+				continue
+			}
+		}
+		if m := markdownHeading.FindStringSubmatch(trimmed); m != nil {
+			kind, ok := docHeadings[strings.ToLower(m[1])]
+			if !ok {
+				kind = docOther
+			}
+			p.startSection(kind, styleHeading, m[1], indent)
+			continue
+		}
+		if m := docTagLine.FindStringSubmatch(trimmed); m != nil {
+			p.addTag(m[1], "", m[2])
+			continue
+		}
+		if m := sphinxFieldLine.FindStringSubmatch(trimmed); m != nil && p.addSphinxField(m[1], strings.TrimSpace(m[2]), m[3]) {
+			continue
+		}
+		if m := deprecatedLine.FindStringSubmatch(trimmed); m != nil && (i == 0 || strings.TrimSpace(lines[i-1]) == "") {
+			p.startSection(docDeprecated, styleHeading, "", indent)
+			p.doc.DeprecationNote = m[1]
+			continue
+		}
+
+		if trimmed != "" && p.kind != docDescription && p.style == styleGoogle && indent <= p.indent {
+			p.kind, p.target = docDescription, nil
+		}
+		if trimmed != "" && (p.kind == docParams || p.kind == docThrows) && p.startItem(trimmed, indent) {
+			continue
+		}
+		p.addText(line)
+	}
+
+	p.doc.Description = strings.TrimSpace(dedentDoc(strings.Join(p.description, "\n")))
+	p.doc.Returns = flattenDoc(p.doc.Returns)
+	p.doc.DeprecationNote = flattenDoc(p.doc.DeprecationNote)
+	for i := range p.doc.Params {
+		p.doc.Params[i].Description = flattenDoc(p.doc.Params[i].Description)
+	}
+	for i := range p.doc.Throws {
+		p.doc.Throws[i].Description = flattenDoc(p.doc.Throws[i].Description)
+	}
+	for i := range p.doc.Examples {
+		p.doc.Examples[i] = dedentDoc(p.doc.Examples[i])
+	}
+	for i := range p.doc.Sections {
+		p.doc.Sections[i].Body = dedentDoc(p.doc.Sections[i].Body)
+	}
+	return p.doc
+}
+
+// startSection opens a headed section of the given kind.
+func (p *docParser) startSection(kind docSectionKind, style docSectionStyle, title string, indent int) {
+	p.kind, p.style, p.indent, p.itemIndent = kind, style, indent, -1
+	p.target = nil
+	switch kind {
+	case docReturns:
+		p.target = &p.doc.Returns
+	case docDeprecated:
+		p.doc.Deprecated = true
+		p.target = &p.doc.DeprecationNote
+	case docExamples:
+		p.doc.Examples = append(p.doc.Examples, "")
+		p.target = &p.doc.Examples[len(p.doc.Examples)-1]
+	case docOther:
+		p.doc.Sections = append(p.doc.Sections, DocSection{Title: title})
+		p.target = &p.doc.Sections[len(p.doc.Sections)-1].Body
+	}
+}
+
+// addTag adds a tag ("@param x desc") whose text continues on the
+// following lines. typ is a type given apart from rest (Sphinx).
+func (p *docParser) addTag(tag, typ, rest string) {
+	kind, ok := docTagKinds[strings.ToLower(tag)]
+	if !ok {
+		kind = docOther
+	}
+	p.kind, p.style = kind, styleTag
+	switch kind {
+	case docParams:
+		param := parseTagParam(rest)
+		if typ != "" {
+			param.Type = typ
+		}
+		p.doc.Params = append(p.doc.Params, param)
+		p.target = &p.doc.Params[len(p.doc.Params)-1].Description
+	case docReturns:
+		_, desc := cutTagType(rest)
+		p.doc.Returns = joinDocText(p.doc.Returns, desc)
+		p.target = &p.doc.Returns
+	case docThrows:
+		t, desc := cutTagType(rest)
+		if t == "" {
+			t, desc, _ = strings.Cut(desc, " ")
+		}
+		p.doc.Throws = append(p.doc.Throws, DocParam{Name: t, Description: strings.TrimSpace(desc)})
+		p.target = &p.doc.Throws[len(p.doc.Throws)-1].Description
+	case docDeprecated:
+		p.doc.Deprecated = true
+		p.doc.DeprecationNote = joinDocText(p.doc.DeprecationNote, rest)
+		p.target = &p.doc.DeprecationNote
+	case docExamples:
+		p.doc.Examples = append(p.doc.Examples, rest)
+		p.target = &p.doc.Examples[len(p.doc.Examples)-1]
+	default:
+		p.doc.Sections = append(p.doc.Sections, DocSection{Title: tag, Body: rest})
+		p.target = &p.doc.Sections[len(p.doc.Sections)-1].Body
+	}
+}
+
+// addSphinxField adds a Sphinx field (":param int x: desc"). It reports
+// whether the field was recognized.
+func (p *docParser) addSphinxField(field, arg, rest string) bool {
+	switch strings.ToLower(field) {
+	case "type":
+		for i := range p.doc.Params {
+			if p.doc.Params[i].Name == arg {
+				p.doc.Params[i].Type = rest
+			}
+		}
+		p.kind, p.target = docOther, nil
+		return true
+	case "rtype":
+		p.kind, p.target = docOther, nil
+		return true
+	}
+	kind, ok := docTagKinds[strings.ToLower(field)]
+	if !ok {
+		return false
+	}
+	switch kind {
+	case docParams:
+		typ, name := "", arg
+		if i := strings.LastIndexByte(arg, ' '); i >= 0 {
+			typ, name = strings.TrimSpace(arg[:i]), arg[i+1:]
+		}
+		p.addTag(field, typ, name+" "+rest)
+	case docThrows:
+		p.addTag(field, "", "{"+arg+"} "+rest)
+	default:
+		p.addTag(field, "", strings.TrimSpace(arg+" "+rest))
+	}
+	return true
+}
+
+// startItem adds a new item to the current parameter or exception list
+// when the line starts one, and reports whether it did.
+func (p *docParser) startItem(trimmed string, indent int) bool {
+	var item DocParam
+	switch p.style {
+	case styleTag:
+		return false
+	case styleNumPy:
+		if indent > p.indent {
+			return false
+		}
+		if m := numpyParam.FindStringSubmatch(trimmed); m != nil {
+			item = DocParam{Name: m[1], Type: m[2]}
+		} else {
+			item = DocParam{Name: trimmed}
+		}
+	case styleGoogle:
+		if p.itemIndent >= 0 && indent > p.itemIndent {
+			return false
+		}
+		m := googleParam.FindStringSubmatch(trimmed)
+		if m == nil {
+			return false
+		}
+		p.itemIndent = indent
+		item = DocParam{Name: m[1], Type: m[2], Description: m[3]}
+	case styleHeading:
+		m := listParam.FindStringSubmatch(trimmed)
+		if m == nil {
+			return false
+		}
+		item = DocParam{Name: m[1], Description: m[2]}
+	}
+
+	if p.kind == docThrows {
+		p.doc.Throws = append(p.doc.Throws, item)
+		p.target = &p.doc.Throws[len(p.doc.Throws)-1].Description
+	} else {
+		p.doc.Params = append(p.doc.Params, item)
+		p.target = &p.doc.Params[len(p.doc.Params)-1].Description
+	}
+	return true
+}
+
+// addText adds a line that continues the current section, or the
+// description outside of sections.
+func (p *docParser) addText(line string) {
+	if p.kind == docDescription {
+		p.description = append(p.description, line)
+		return
+	}
+	if p.target == nil {
+		return
+	}
+	if *p.target == "" {
+		*p.target = line
+		return
+	}
+	*p.target += "\n" + line
+}
+
+// parseTagParam parses the text of a parameter tag: "{type} name desc",
+// "name desc" or "[name=default] - desc".
+func parseTagParam(rest string) DocParam {
+	typ, rest := cutTagType(rest)
+	name, desc, _ := strings.Cut(rest, " ")
+	name = strings.TrimSuffix(strings.TrimPrefix(name, "["), "]")
+	name, _, _ = strings.Cut(name, "=")
+	desc = strings.TrimSpace(desc)
+	desc = strings.TrimSpace(strings.TrimPrefix(desc, "-"))
+	return DocParam{Name: name, Type: typ, Description: desc}
+}
+
+// cutTagType splits a leading JSDoc type ("{string} rest").
+func cutTagType(rest string) (typ, after string) {
+	rest = strings.TrimSpace(rest)
+	if !strings.HasPrefix(rest, "{") {
+		return "", rest
+	}
+	depth := 0
+	for i, r := range rest {
+		switch r {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return rest[1:i], strings.TrimSpace(rest[i+1:])
+			}
+		}
+	}
+	return "", rest
+}
+
+// joinDocText joins two pieces of text with a space, skipping empty ones.
+func joinDocText(a, b string) string {
+	if a == "" {
+		return b
+	}
+	if b == "" {
+		return a
+	}
+	return a + " " + b
+}
+
+// flattenDoc joins the lines of a short doc part with spaces.
+func flattenDoc(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// dedentDoc removes the indentation shared by the non-blank lines of text
+// and trims blank lines around it.
+func dedentDoc(text string) string {
+	lines := strings.Split(text, "\n")
+	indent := -1
+	for _, line := range lines {
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed == "" {
+			continue
+		}
+		if n := len(line) - len(trimmed); indent < 0 || n < indent {
+			indent = n
+		}
+	}
+	for i, line := range lines {
+		if len(line) >= indent && indent > 0 {
+			lines[i] = line[indent:]
+		}
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
+
+var (
+	// inlineTag matches Javadoc and JSDoc inline tags ("{@link Foo#bar}").
+	inlineTag = regexp.MustCompile(`\{@(\w+)\s*([^}]*)\}`)
+
+	// htmlBreak matches HTML tags that start a new line or paragraph.
+	htmlBreak = regexp.MustCompile(`(?i)<(/?p|br|/?pre|/?ul|/?ol|/?dl|/?blockquote|/?h[1-6]|/?div|/?table|/?tr)(\s[^>]*)?/?>`)
+
+	// htmlListItem matches HTML list items.
+	htmlListItem = regexp.MustCompile(`(?i)<(li|dt|dd)(\s[^>]*)?>`)
+
+	// htmlInline matches the remaining HTML markup of Javadoc comments.
+	htmlInline = regexp.MustCompile(`(?i)</?(b|i|em|strong|code|tt|a|span|u|sup|sub|small|var|kbd|samp|cite|li|dt|dd|td|th|thead|tbody)(\s[^>]*)?/?>`)
+
+	// blankLines matches runs of blank lines.
+	blankLines = regexp.MustCompile(`\n{3,}`)
+
+	// htmlEntities are the HTML entities found in doc comments.
+	htmlEntities = strings.NewReplacer("&lt;", "<", "&gt;", ">", "&quot;", `"`, "&#39;", "'", "&apos;", "'", "&nbsp;", " ", "&amp;", "&")
+)
+
+// DocPlainText converts the HTML markup and inline tags of Javadoc-style
+// comments to plain text: "<p>" and "<br>" start new paragraphs and lines,
+// "<li>" becomes a "- " item, other tags are dropped, entities are decoded,
+// and "{@code x}" and "{@link Foo label}" become "x" and "label".
+func DocPlainText(text string) string {
+	if !strings.ContainsAny(text, "<&{") {
+		return text
+	}
+	text = inlineTag.ReplaceAllStringFunc(text, func(tag string) string {
+		m := inlineTag.FindStringSubmatch(tag)
+		name, arg := m[1], strings.TrimSpace(m[2])
+		switch name {
+		case "link", "linkplain", "linkcode":
+			// {@link Foo#bar label} and {@link Foo|label} show the label
+			if _, label, ok := strings.Cut(arg, "|"); ok {
+				return strings.TrimSpace(label)
+			}
+			if _, label, ok := strings.Cut(arg, " "); ok {
+				return strings.TrimSpace(label)
+			}
+			return arg
+		case "inheritDoc":
+			return ""
+		}
+		return arg
+	})
+	if strings.ContainsAny(text, "<&") {
+		text = htmlBreak.ReplaceAllStringFunc(text, func(tag string) string {
+			if strings.HasPrefix(strings.ToLower(tag), "<br") {
+				return "\n"
+			}
+			return "\n\n"
+		})
+		text = htmlListItem.ReplaceAllString(text, "\n- ")
+		text = htmlInline.ReplaceAllString(text, "")
+		text = htmlEntities.Replace(text)
+		lines := strings.Split(text, "\n")
+		for i, line := range lines {
+			lines[i] = strings.TrimRight(line, " \t")
+		}
+		text = strings.TrimSpace(blankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+	}
+	return text
+}
@@ -0,0 +1,197 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDoc(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want DocComment
+	}{
+		{
+			name: "plain",
+			doc:  "Scan walks the tree.\nIt returns all files.",
+			want: DocComment{Description: "Scan walks the tree.\nIt returns all files."},
+		},
+		{
+			name: "jsdoc",
+			doc: `Creates a user.
+
+@param {string} name - The user name,
+  shown in the UI.
+@param {number} [age=0] Optional age.
+@returns {Promise<User>} The created user.
+@throws {Error} When the name is taken.
+@deprecated Use createAccount instead.
+@example
+createUser("ada");
+@since 1.2`,
+			want: DocComment{
+				Description: "Creates a user.",
+				Params: []DocParam{
+					{Name: "name", Type: "string", Description: "The user name, shown in the UI."},
+					{Name: "age", Type: "number", Description: "Optional age."},
+				},
+				Returns:         "The created user.",
+				Throws:          []DocParam{{Name: "Error", Description: "When the name is taken."}},
+				Deprecated:      true,
+				DeprecationNote: "Use createAccount instead.",
+				Examples:        []string{"createUser(\"ada\");"},
+				Sections:        []DocSection{{Title: "since", Body: "1.2"}},
+			},
+		},
+		{
+			name: "javadoc html",
+			doc: `Returns the {@code name} of the <b>shape</b>.
+<p>
+See {@link Shape#area the area}.
+@param scale the scale &amp; unit
+@return the name
+@throws IOException if reading fails`,
+			want: DocComment{
+				Description: "Returns the name of the shape.\n\nSee the area.",
+				Params:      []DocParam{{Name: "scale", Description: "the scale & unit"}},
+				Returns:     "the name",
+				Throws:      []DocParam{{Name: "IOException", Description: "if reading fails"}},
+			},
+		},
+		{
+			name: "google docstring",
+			doc: `Fetch rows.
+
+Args:
+    table (str): Table name.
+    keys: Keys to fetch,
+        in order.
+
+Returns:
+    dict: The rows.
+
+Raises:
+    IOError: On failure.
+
+Later text.`,
+			want: DocComment{
+				Description: "Fetch rows.\n\nLater text.",
+				Params: []DocParam{
+					{Name: "table", Type: "str", Description: "Table name."},
+					{Name: "keys", Description: "Keys to fetch, in order."},
+				},
+				Returns: "dict: The rows.",
+				Throws:  []DocParam{{Name: "IOError", Description: "On failure."}},
+			},
+		},
+		{
+			name: "numpy docstring",
+			doc: `Add numbers.
+
+Parameters
+----------
+x : int
+    First.
+y : int
+    Second.
+
+Returns
+-------
+int
+    The sum.`,
+			want: DocComment{
+				Description: "Add numbers.",
+				Params: []DocParam{
+					{Name: "x", Type: "int", Description: "First."},
+					{Name: "y", Type: "int", Description: "Second."},
+				},
+				Returns: "int The sum.",
+			},
+		},
+		{
+			name: "sphinx",
+			doc: `Send a message.
+
+:param str to: The recipient.
+:param body: The text.
+:type body: str
+:returns: The message ID.
+:raises ValueError: If to is empty.`,
+			want: DocComment{
+				Description: "Send a message.",
+				Params: []DocParam{
+					{Name: "to", Type: "str", Description: "The recipient."},
+					{Name: "body", Type: "str", Description: "The text."},
+				},
+				Returns: "The message ID.",
+				Throws:  []DocParam{{Name: "ValueError", Description: "If to is empty."}},
+			},
+		},
+		{
+			name: "rust headings",
+			doc:  "Parses a value.\n\n# Arguments\n\n* `input` - The text.\n\n# Panics\n\nPanics on empty input.\n\n# Examples\n\n```\n# use x::parse;\nparse(\"1\");\n```",
+			want: DocComment{
+				Description: "Parses a value.",
+				Params:      []DocParam{{Name: "input", Description: "The text."}},
+				Examples:    []string{"```\n# use x::parse;\nparse(\"1\");\n```"},
+				Sections:    []DocSection{{Title: "Panics", Body: "Panics on empty input."}},
+			},
+		},
+		{
+			name: "go deprecated",
+			doc:  "Open opens a file.\n\nDeprecated: Use OpenFile.",
+			want: DocComment{
+				Description:     "Open opens a file.",
+				Deprecated:      true,
+				DeprecationNote: "Use OpenFile.",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseDoc(tt.doc)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseDoc():\n got %#v\nwant %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDocCommentSummary(t *testing.T) {
+	tests := []struct {
+		desc string
+		want string
+	}{
+		{desc: "Scan walks the tree. It returns files.", want: "Scan walks the tree."},
+		{desc: "Uses a cache, e.g. an LRU.\nMore.", want: "Uses a cache, e.g. an LRU."},
+		{desc: "Version 1.2 is\nsupported", want: "Version 1.2 is supported"},
+		{desc: "First paragraph\n\nSecond.", want: "First paragraph"},
+		{desc: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := (DocComment{Description: tt.desc}).Summary(); got != tt.want {
+			t.Errorf("Summary(%q) = %q, want %q", tt.desc, got, tt.want)
+		}
+	}
+}
+
+func TestDocPlainText(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "Returns a List<String>.", want: "Returns a List<String>."},
+		{in: "A <em>fast</em> map.<br>Thread-safe.", want: "A fast map.\nThread-safe."},
+		{in: "Options:<ul><li>one</li><li>two</li></ul>", want: "Options:\n\n- one\n- two"},
+		{in: "Calls {@link #run()} and {@linkplain Foo the foo}.", want: "Calls #run() and the foo."},
+		{in: "a &lt; b", want: "a < b"},
+	}
+
+	for _, tt := range tests {
+		if got := DocPlainText(tt.in); got != tt.want {
+			t.Errorf("DocPlainText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -2,7 +2,9 @@
 package parser
 
 import (
+	"context"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 )
@@ -27,11 +29,158 @@ type Signature struct {
 	// EndLine is the ending line number (1-indexed).
 	EndLine int
 
+	// Column is the starting byte column within Line (1-indexed).
+	Column int
+
+	// EndColumn is the byte column just past the last character on EndLine
+	// (1-indexed, exclusive).
+	EndColumn int
+
+	// StartByte is the byte offset of the declaration in the file (0-indexed).
+	StartByte int
+
+	// EndByte is the byte offset just past the end of the declaration.
+	EndByte int
+
 	// Language is the source language (e.g., "go", "typescript").
 	Language string
 
-	// Exported indicates whether the signature is exported/public.
+	// Visibility is the access level of the declaration, from its modifiers
+	// and position (e.g., "public", "private").
+	Visibility Visibility
+
+	// Exported indicates whether the signature is part of the public API:
+	// its Visibility is public or protected.
 	Exported bool
+
+	// Parent is the container path of the enclosing signatures in the same
+	// file (e.g., "Outer.Inner" for a method of an inner class). Empty for
+	// top-level symbols.
+	Parent string
+
+	// QualifiedName is the language-specific fully qualified name, including
+	// the package, namespace or module and the enclosing types (e.g.,
+	// "scanner.FileScanner.Scan" in Go, "crate::fs::read" in Rust). Empty
+	// when the parser does not qualify names.
+	QualifiedName string
+
+	// Params are the declared parameters of a function or method, in order.
+	Params []Parameter
+
+	// Returns are the declared return types. Go functions with multiple
+	// results have one entry per result.
+	Returns []string
+
+	// TypeParams are the generic type parameters (e.g., "[K comparable, V any]").
+	TypeParams []TypeParameter
+
+	// Receiver is the receiver of a Go method, nil otherwise.
+	Receiver *Parameter
+
+	// Modifiers are the declaration modifiers in source order (e.g., "static",
+	// "async", "abstract"). Visibility keywords and annotations are not included.
+	Modifiers []string
+
+	// Decorators are the decorators, annotations and attributes attached to
+	// the declaration, in source order (e.g., "@app.route", "@Override",
+	// "#[derive(Debug)]"). They are not part of Text.
+	Decorators []Decorator
+
+	// Deprecated indicates that the declaration, or a container of it, is
+	// marked deprecated by an annotation or attribute (e.g., "@Deprecated",
+	// "#[deprecated]", "[Obsolete]") or by its doc ("@deprecated", Go
+	// "Deprecated:").
+	Deprecated bool
+
+	// DeprecationNote is the message given with the deprecation, if any
+	// (e.g., "use NewClient instead").
+	DeprecationNote string
+}
+
+// Parameter is a function, method or receiver parameter.
+type Parameter struct {
+	// Name is the parameter name as written (e.g., "ctx", "$id"). Empty for
+	// unnamed parameters such as "int" in a C prototype.
+	Name string
+
+	// Type is the declared type, empty when the parameter is untyped.
+	Type string
+
+	// Default is the default value expression, if any.
+	Default string
+
+	// Variadic indicates a rest parameter ("...args", "*args", "**kwargs").
+	Variadic bool
+}
+
+// Decorator is a decorator, annotation or attribute of a declaration.
+type Decorator struct {
+	// Name is the decorator name without its marker (e.g., "app.route",
+	// "Override", "derive").
+	Name string
+
+	// Args is the argument text without the enclosing parentheses (e.g.,
+	// `"/users", methods=["GET"]`). Empty when there are no arguments.
+	Args string
+
+	// Text is the decorator as written, with whitespace runs collapsed
+	// (e.g., `@app.route("/users", methods=["GET"])`, "#[derive(Debug)]").
+	Text string
+}
+
+// TypeParameter is a generic type parameter.
+type TypeParameter struct {
+	// Name is the type parameter name (e.g., "T").
+	Name string
+
+	// Constraint is the bound or constraint (e.g., "comparable", "Display + Clone").
+	Constraint string
+
+	// Default is the default type, if any.
+	Default string
+}
+
+// Path returns the container path including the signature's own name
+// (e.g., "Outer.Inner.method").
+func (s Signature) Path() string {
+	if s.Parent == "" {
+		return s.Name
+	}
+	return s.Parent + "." + s.Name
+}
+
+// FullName returns QualifiedName, or Path when the signature is not qualified.
+func (s Signature) FullName() string {
+	if s.QualifiedName != "" {
+		return s.QualifiedName
+	}
+	return s.Path()
+}
+
+// SymbolID returns a stable identifier for sig in a file of the given
+// language at path (relative, slash-separated):
+// "<language>:<path>#<full name>:<kind>", e.g.
+// "java:src/Outer.java#com.example.Outer.Inner.run:method". It does not
+// depend on line numbers, so it survives edits elsewhere in the file.
+func SymbolID(language, path string, sig Signature) string {
+	return language + ":" + path + "#" + sig.FullName() + ":" + sig.Kind
+}
+
+// SymbolIDs returns the SymbolID of each signature of a file. Signatures that
+// would share an ID (e.g., overloads) get a "~N" suffix numbered in source
+// order from the second one on.
+func SymbolIDs(language, path string, sigs []Signature) []string {
+	ids := make([]string, len(sigs))
+	seen := make(map[string]int, len(sigs))
+	for i, sig := range sigs {
+		id := SymbolID(language, path, sig)
+		seen[id]++
+		if n := seen[id]; n > 1 {
+			id += "~" + strconv.Itoa(n)
+		}
+		ids[i] = id
+	}
+	return ids
 }
 
 // Node represents a node in the parsed AST.
@@ -63,6 +212,10 @@ type FunctionCall struct {
 	// Caller is the name of the enclosing function (empty if top-level).
 	Caller string
 
+	// QualifiedCaller is the qualified name of the enclosing function
+	// (see Signature.QualifiedName), empty if top-level or not qualified.
+	QualifiedCaller string
+
 	// Callee is the called function/method name.
 	Callee string
 
@@ -70,6 +223,38 @@ type FunctionCall struct {
 	Line int
 }
 
+// Diagnostic is a syntax error found while parsing a file: a span of source
+// the grammar could not parse, or a token it expected but did not find.
+// Signatures may be missing around it.
+type Diagnostic struct {
+	// Message describes the error (e.g., "syntax error", `missing ";"`).
+	Message string
+
+	// Line is the starting line number (1-indexed).
+	Line int
+
+	// Column is the starting byte column within Line (1-indexed).
+	Column int
+
+	// EndLine is the ending line number (1-indexed).
+	EndLine int
+
+	// EndColumn is the byte column just past the error on EndLine
+	// (1-indexed, exclusive). Equal to Column for missing tokens.
+	EndColumn int
+}
+
+// Location returns the range of d as "line:column-endLine:endColumn".
+func (d Diagnostic) Location() string {
+	return strconv.Itoa(d.Line) + ":" + strconv.Itoa(d.Column) + "-" +
+		strconv.Itoa(d.EndLine) + ":" + strconv.Itoa(d.EndColumn)
+}
+
+// String returns d as "line:column-endLine:endColumn: message".
+func (d Diagnostic) String() string {
+	return d.Location() + ": " + d.Message
+}
+
 // ParseResult contains the result of parsing a single file.
 type ParseResult struct {
 	// FilePath is the path to the parsed file.
@@ -87,6 +272,15 @@ type ParseResult struct {
 	// Calls is the list of function call references.
 	Calls []FunctionCall
 
+	// Summary is the first paragraph of the file's own documentation: its
+	// package comment, module docstring, inner crate doc or header comment.
+	// License headers are skipped. Empty when the file has none.
+	Summary string
+
+	// Diagnostics are the syntax errors of the file, in source order. The
+	// file was still parsed, but signatures may be missing around them.
+	Diagnostics []Diagnostic
+
 	// AST is the root node of the parsed AST (optional).
 	AST *Node
 
@@ -102,9 +296,18 @@ type Options struct {
 	// IncludeAST whether to include the full AST in the result.
 	IncludeAST bool
 
-	// IncludePrivate whether to include non-exported/private signatures.
+	// IncludePrivate whether to include signatures of every visibility,
+	// overriding Visibility.
 	IncludePrivate bool
 
+	// Visibility lists the visibility levels of the signatures to include.
+	// LanguageDefaultVisibility is used when empty.
+	Visibility []Visibility
+
+	// ExcludeDeprecated whether to leave out deprecated signatures (see
+	// Signature.Deprecated).
+	ExcludeDeprecated bool
+
 	// IncludeBody whether to include function/method bodies in the signature text.
 	// When false (default), only the signature line is extracted.
 	// When true, the full declaration including the body is extracted.
@@ -115,6 +318,20 @@ type Options struct {
 
 	// IncludeCalls whether to include function call references in the result.
 	IncludeCalls bool
+
+	// Module is the module path of the file derived from its location (e.g.,
+	// "pkg.sub.mod" for Python, "crate::fs" for Rust). It prefixes qualified
+	// names when the source itself declares no package or namespace.
+	Module string
+
+	// Context interrupts parsing when it is done (e.g., at a per-file
+	// deadline): Parse stops and returns its error. nil means parsing is
+	// never interrupted.
+	Context context.Context
+
+	// Queries are user-supplied queries replacing or extending the built-in
+	// ones of Language. nil keeps the built-in queries.
+	Queries *Queries
 }
 
 // Parser defines the interface for code parsers.
@@ -190,36 +407,41 @@ func GetParser(lang string) (Parser, bool) {
 // This is the canonical source of truth for extension-to-language mapping.
 // Immutable after package initialization; safe for concurrent reads.
 var languageMapping = map[string]string{
-	".go":    "go",
-	".ts":    "typescript",
-	".tsx":   "typescript",
-	".js":    "javascript",
-	".jsx":   "javascript",
-	".py":    "python",
-	".java":  "java",
-	".rs":    "rust",
-	".rb":    "ruby",
-	".php":   "php",
-	".c":     "c",
-	".cpp":   "cpp",
-	".h":     "cpp",
-	".hpp":   "cpp",
-	".cs":    "csharp",
-	".swift": "swift",
-	".kt":    "kotlin",
-	".kts":   "kotlin",
-	".lua":   "lua",
-	".sh":    "shell",
-	".bash":  "shell",
-	".zsh":   "shell",
-	".scala": "scala",
-	".sc":    "scala",
-	".ex":    "elixir",
-	".exs":   "elixir",
-	".sql":   "sql",
-	".yaml":  "yaml",
-	".yml":   "yaml",
-	".toml":  "toml",
+	".go":     "go",
+	".ts":     "typescript",
+	".tsx":    "tsx",
+	".js":     "javascript",
+	".jsx":    "jsx",
+	".py":     "python",
+	".java":   "java",
+	".rs":     "rust",
+	".rb":     "ruby",
+	".php":    "php",
+	".c":      "c",
+	".cpp":    "cpp",
+	".h":      "cpp",
+	".hpp":    "cpp",
+	".cs":     "csharp",
+	".swift":  "swift",
+	".kt":     "kotlin",
+	".kts":    "kotlin",
+	".lua":    "lua",
+	".sh":     "shell",
+	".bash":   "shell",
+	".zsh":    "shell",
+	".scala":  "scala",
+	".sc":     "scala",
+	".ex":     "elixir",
+	".exs":    "elixir",
+	".sql":    "sql",
+	".yaml":   "yaml",
+	".yml":    "yaml",
+	".toml":   "toml",
+	".vue":    "vue",
+	".svelte": "svelte",
+	".astro":  "astro",
+	".html":   "html",
+	".htm":    "html",
 }
 
 // LanguageMapping returns a copy of the canonical extension-to-language mapping.
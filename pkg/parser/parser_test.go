@@ -135,9 +135,9 @@ func TestDetectLanguage(t *testing.T) {
 	}{
 		{"main.go", "go"},
 		{"app.ts", "typescript"},
-		{"component.tsx", "typescript"},
+		{"component.tsx", "tsx"},
 		{"index.js", "javascript"},
-		{"App.jsx", "javascript"},
+		{"App.jsx", "jsx"},
 		{"script.py", "python"},
 		{"Main.java", "java"},
 		{"lib.rs", "rust"},
@@ -160,7 +160,11 @@ func TestDetectLanguage(t *testing.T) {
 		{"app.ex", "elixir"},
 		{"test.exs", "elixir"},
 		{"query.sql", "sql"},
-		{"App.JSX", "javascript"},
+		{"App.JSX", "jsx"},
+		{"Counter.vue", "vue"},
+		{"Button.svelte", "svelte"},
+		{"index.astro", "astro"},
+		{"index.htm", "html"},
 		{"README.md", ""},
 		{"config.json", ""},
 	}
@@ -174,3 +178,59 @@ func TestDetectLanguage(t *testing.T) {
 		})
 	}
 }
+
+func TestSignaturePath(t *testing.T) {
+	tests := []struct {
+		sig  Signature
+		want string
+	}{
+		{Signature{Name: "Scan"}, "Scan"},
+		{Signature{Name: "b", Parent: "Outer.Inner"}, "Outer.Inner.b"},
+	}
+	for _, tt := range tests {
+		if got := tt.sig.Path(); got != tt.want {
+			t.Errorf("Path() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestSignatureFullName(t *testing.T) {
+	tests := []struct {
+		sig  Signature
+		want string
+	}{
+		{Signature{Name: "b", Parent: "Outer.Inner"}, "Outer.Inner.b"},
+		{Signature{Name: "Scan", QualifiedName: "scanner.FileScanner.Scan"}, "scanner.FileScanner.Scan"},
+	}
+	for _, tt := range tests {
+		if got := tt.sig.FullName(); got != tt.want {
+			t.Errorf("FullName() = %q, want %q", got, tt.want)
+		}
+	}
+
+	sig := Signature{Name: "Scan", Kind: "method", QualifiedName: "scanner.FileScanner.Scan"}
+	if got, want := SymbolID("go", "scanner.go", sig), "go:scanner.go#scanner.FileScanner.Scan:method"; got != want {
+		t.Errorf("SymbolID = %q, want %q", got, want)
+	}
+}
+
+func TestSymbolIDs(t *testing.T) {
+	sigs := []Signature{
+		{Name: "Outer", Kind: "class"},
+		{Name: "run", Kind: "method", Parent: "Outer"},
+		{Name: "run", Kind: "method", Parent: "Outer"}, // overload
+		{Name: "run", Kind: "function"},
+	}
+	got := SymbolIDs("java", "src/Outer.java", sigs)
+	want := []string{
+		"java:src/Outer.java#Outer:class",
+		"java:src/Outer.java#Outer.run:method",
+		"java:src/Outer.java#Outer.run:method~2",
+		"java:src/Outer.java#run:function",
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("id %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
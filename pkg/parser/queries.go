@@ -0,0 +1,143 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// QueryFileExt is the extension of query override files.
+const QueryFileExt = ".scm"
+
+// queryFileKinds maps the suffix of a query file name, between the
+// language and QueryFileExt, to the query it overrides.
+var queryFileKinds = map[string]func(q *Queries) **QueryOverride{
+	"":         func(q *Queries) **QueryOverride { return &q.Signature },
+	".imports": func(q *Queries) **QueryOverride { return &q.Import },
+	".calls":   func(q *Queries) **QueryOverride { return &q.Call },
+}
+
+// QueryOverride is a user-supplied Tree-sitter query that replaces one of
+// the built-in queries of a language, or adds patterns to it.
+type QueryOverride struct {
+	// Path is the file the query was read from, for error messages.
+	Path string
+
+	// Source is the query source.
+	Source string
+
+	// Extend whether Source is appended to the built-in query instead of
+	// replacing it. A query file extends when one of its leading comment
+	// lines is "; extends".
+	Extend bool
+}
+
+// Queries are the query overrides of a language. A nil override keeps
+// the built-in query.
+type Queries struct {
+	// Signature overrides the signature query, read from "<lang>.scm".
+	Signature *QueryOverride
+
+	// Import overrides the import query, read from "<lang>.imports.scm".
+	Import *QueryOverride
+
+	// Call overrides the call query, read from "<lang>.calls.scm".
+	Call *QueryOverride
+}
+
+// IsLanguage reports whether lang is the name of a supported language
+// (e.g., "go", "tsx").
+func IsLanguage(lang string) bool {
+	for _, l := range languageMapping {
+		if l == lang {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadQueries reads the query override files of dir, keyed by language:
+// "<lang>.scm" for signatures, "<lang>.imports.scm" for imports and
+// "<lang>.calls.scm" for calls. Files with other extensions are ignored.
+// A missing dir holds no overrides.
+func LoadQueries(dir string) (map[string]*Queries, error) {
+	if dir == "" {
+		return nil, nil
+	}
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read query directory %q: %w", dir, err)
+	}
+
+	queries := make(map[string]*Queries)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, QueryFileExt) {
+			continue
+		}
+		path := filepath.Join(dir, name)
+
+		lang, suffix := strings.TrimSuffix(name, QueryFileExt), ""
+		if i := strings.IndexByte(lang, '.'); i >= 0 {
+			lang, suffix = lang[:i], lang[i:]
+		}
+		field, ok := queryFileKinds[suffix]
+		if !ok {
+			return nil, fmt.Errorf("query file %q: unknown query %q (expected %s)", path, strings.TrimPrefix(suffix, "."), queryFileNames(lang))
+		}
+		if !IsLanguage(lang) {
+			return nil, fmt.Errorf("query file %q: unknown language %q", path, lang)
+		}
+
+		source, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read query file %q: %w", path, err)
+		}
+		q := queries[lang]
+		if q == nil {
+			q = &Queries{}
+			queries[lang] = q
+		}
+		*field(q) = &QueryOverride{
+			Path:   path,
+			Source: string(source),
+			Extend: extendsQuery(string(source)),
+		}
+	}
+	return queries, nil
+}
+
+// queryFileNames lists the query file names of lang, for error messages.
+func queryFileNames(lang string) string {
+	names := make([]string, 0, len(queryFileKinds))
+	for suffix := range queryFileKinds {
+		names = append(names, lang+suffix+QueryFileExt)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// extendsQuery reports whether one of the comment lines leading source is
+// an "extends" directive (e.g., "; extends" or ";; extends"), as in
+// Neovim's query files.
+func extendsQuery(source string) bool {
+	for _, line := range strings.Split(source, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, ";") {
+			return false
+		}
+		if strings.TrimSpace(strings.TrimLeft(line, ";")) == "extends" {
+			return true
+		}
+	}
+	return false
+}
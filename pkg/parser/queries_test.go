@@ -0,0 +1,94 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadQueries(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.scm":                "(function_declaration name: (identifier) @name) @signature\n",
+		"go.calls.scm":          "; extends\n(call_expression function: (identifier) @callee)\n",
+		"python.imports.scm":    ";; Project imports\n;; extends\n(import_statement) @import_path\n",
+		"README.md":             "not a query",
+		"typescript.scm.backup": "ignored",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	queries, err := LoadQueries(dir)
+	if err != nil {
+		t.Fatalf("LoadQueries: %v", err)
+	}
+	if len(queries) != 2 {
+		t.Fatalf("expected queries for 2 languages, got %d: %v", len(queries), queries)
+	}
+
+	goq := queries["go"]
+	if goq == nil || goq.Signature == nil || goq.Import != nil || goq.Call == nil {
+		t.Fatalf("unexpected go queries: %+v", goq)
+	}
+	if goq.Signature.Extend || goq.Signature.Source != files["go.scm"] {
+		t.Errorf("go signature query: %+v", goq.Signature)
+	}
+	if !goq.Call.Extend || goq.Call.Path != filepath.Join(dir, "go.calls.scm") {
+		t.Errorf("go call query: %+v", goq.Call)
+	}
+	if pyq := queries["python"]; pyq == nil || pyq.Import == nil || !pyq.Import.Extend {
+		t.Errorf("unexpected python queries: %+v", pyq)
+	}
+}
+
+func TestLoadQueriesErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		wantErr string
+	}{
+		{"unknown language", "cobol.scm", `unknown language "cobol"`},
+		{"unknown query", "go.types.scm", `unknown query "types"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, tt.file), []byte("(identifier) @name"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := LoadQueries(dir)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	// A missing directory holds no overrides
+	queries, err := LoadQueries(filepath.Join(t.TempDir(), "missing"))
+	if err != nil || queries != nil {
+		t.Errorf("missing directory: got %v, %v", queries, err)
+	}
+}
+
+func TestExtendsQuery(t *testing.T) {
+	tests := []struct {
+		source string
+		want   bool
+	}{
+		{"; extends\n(identifier) @name", true},
+		{";; extends\n", true},
+		{"\n;; Custom patterns\n; extends\n(identifier) @name", true},
+		{"(identifier) @name\n; extends", false},
+		{"; extends these patterns\n", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := extendsQuery(tt.source); got != tt.want {
+			t.Errorf("extendsQuery(%q) = %v, want %v", tt.source, got, tt.want)
+		}
+	}
+}
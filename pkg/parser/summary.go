@@ -0,0 +1,47 @@
+package parser
+
+import (
+	"path"
+	"regexp"
+	"strings"
+)
+
+// licenseHeader matches the wording of license and copyright notices.
+var licenseHeader = regexp.MustCompile(`(?i)copyright|all rights reserved|spdx-license-identifier|licensed under|permission is hereby granted|free software foundation|\blicense(?:d)?\b.*\b(?:version|agreement|terms)\b|(?:mit|apache|bsd|gnu|mozilla public) license`)
+
+// IsLicenseHeader reports whether a comment is a license or copyright
+// notice rather than documentation.
+func IsLicenseHeader(text string) bool {
+	return licenseHeader.MatchString(text)
+}
+
+// packageDocFiles are the file names whose summary documents the package or
+// module of their directory, by language.
+var packageDocFiles = map[string][]string{
+	"go":         {"doc.go"},
+	"python":     {"__init__.py"},
+	"rust":       {"lib.rs", "main.rs", "mod.rs"},
+	"java":       {"package-info.java"},
+	"typescript": {"index.ts", "index.d.ts"},
+	"tsx":        {"index.tsx"},
+	"javascript": {"index.js", "index.mjs"},
+	"jsx":        {"index.jsx"},
+}
+
+// PackageDocRank ranks how well the summary of the file at filePath
+// documents its directory's package: 0 for a dedicated package doc file
+// (e.g., "doc.go", "__init__.py", "lib.rs"), 1 for other Go files, whose
+// package comments all document the package, and -1 for files whose
+// summary only documents the file itself.
+func PackageDocRank(lang, filePath string) int {
+	base := path.Base(strings.ReplaceAll(filePath, "\\", "/"))
+	for _, name := range packageDocFiles[lang] {
+		if base == name {
+			return 0
+		}
+	}
+	if lang == "go" && !strings.HasSuffix(base, "_test.go") {
+		return 1
+	}
+	return -1
+}
@@ -0,0 +1,52 @@
+package parser
+
+import "testing"
+
+func TestIsLicenseHeader(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{"Copyright 2024 The Authors. All rights reserved.", true},
+		{"SPDX-License-Identifier: Apache-2.0", true},
+		{"Licensed under the Apache License, Version 2.0 (the \"License\");", true},
+		{"This program is free software; see the Free Software Foundation.", true},
+		{"Package license parses license keys.", false},
+		{"Helpers for string handling.", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := IsLicenseHeader(tt.text); got != tt.want {
+				t.Errorf("IsLicenseHeader(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPackageDocRank(t *testing.T) {
+	tests := []struct {
+		lang string
+		path string
+		want int
+	}{
+		{"go", "pkg/foo/doc.go", 0},
+		{"go", "pkg/foo/foo.go", 1},
+		{"go", "pkg/foo/foo_test.go", -1},
+		{"python", "pkg/__init__.py", 0},
+		{"python", "pkg/util.py", -1},
+		{"rust", "src/lib.rs", 0},
+		{"rust", `src\fs\mod.rs`, 0},
+		{"java", "com/acme/package-info.java", 0},
+		{"typescript", "src/index.ts", 0},
+		{"typescript", "src/app.ts", -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := PackageDocRank(tt.lang, tt.path); got != tt.want {
+				t.Errorf("PackageDocRank(%q, %q) = %d, want %d", tt.lang, tt.path, got, tt.want)
+			}
+		})
+	}
+}
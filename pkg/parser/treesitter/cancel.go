@@ -0,0 +1,67 @@
+package treesitter
+
+import (
+	"context"
+	"fmt"
+
+	sitter "github.com/tree-sitter/go-tree-sitter"
+
+	"github.com/indigo-net/Brf.it/pkg/parser"
+)
+
+// newPooledParser creates a pooledParser whose progress callback stops
+// parsing once the Done channel of the current parse is closed.
+func newPooledParser() *pooledParser {
+	pp := &pooledParser{parser: sitter.NewParser()}
+	pp.progress = &sitter.ParseOptions{
+		ProgressCallback: func(sitter.ParseState) bool {
+			select {
+			case <-pp.done:
+				return true
+			default:
+				return false
+			}
+		},
+	}
+	return pp
+}
+
+// parse parses content, stopping early with the error of ctx when it is
+// done.
+func (pp *pooledParser) parse(ctx context.Context, content []byte) (*sitter.Tree, error) {
+	var options *sitter.ParseOptions
+	if pp.done = ctx.Done(); pp.done != nil {
+		options = pp.progress
+	}
+	defer func() { pp.done = nil }()
+
+	tree := pp.parser.ParseWithOptions(func(i int, _ sitter.Point) []byte {
+		if i < len(content) {
+			return content[i:]
+		}
+		return []byte{}
+	}, nil, options)
+	if tree == nil {
+		if err := ctx.Err(); err != nil {
+			// A stopped parser resumes the same document on its next
+			// parse unless reset
+			pp.parser.Reset()
+			return nil, interrupted(err)
+		}
+	}
+	return tree, nil
+}
+
+// parseContext returns the context interrupting the parse configured by
+// opts.
+func parseContext(opts *parser.Options) context.Context {
+	if opts.Context != nil {
+		return opts.Context
+	}
+	return context.Background()
+}
+
+// interrupted wraps the error of a done context that stopped parsing.
+func interrupted(err error) error {
+	return fmt.Errorf("parsing interrupted: %w", err)
+}
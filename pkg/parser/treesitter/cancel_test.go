@@ -0,0 +1,45 @@
+package treesitter
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/indigo-net/Brf.it/pkg/parser"
+)
+
+func TestParseContext(t *testing.T) {
+	p := NewTreeSitterParser()
+	defer p.Close()
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := p.Parse([]byte("package a\n\nfunc A() {}\n"), &parser.Options{Language: "go", Context: canceled})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+
+	// A large file is interrupted mid-parse at the deadline
+	big := "package a\n\n" + strings.Repeat("func f() { x := ((((((((1)))))))) + g(h(i(j(k)))) }\n", 200000)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = p.Parse([]byte(big), &parser.Options{Language: "go", Context: ctx})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("parse was not interrupted promptly: took %v", elapsed)
+	}
+
+	// The interrupted parser is reset for the next file
+	result, err := p.Parse([]byte("package a\n\nfunc A() {}\n"), &parser.Options{Language: "go", Context: context.Background()})
+	if err != nil {
+		t.Fatalf("Parse after interruption: %v", err)
+	}
+	if len(result.Signatures) != 1 || result.Signatures[0].Name != "A" || len(result.Diagnostics) != 0 {
+		t.Errorf("unexpected result after interruption: %+v", result)
+	}
+}
@@ -0,0 +1,537 @@
+package treesitter
+
+import (
+	"slices"
+	"strings"
+
+	sitter "github.com/tree-sitter/go-tree-sitter"
+
+	"github.com/indigo-net/Brf.it/pkg/parser"
+)
+
+// functionValueKinds are expression nodes that make a variable or field a
+// function (e.g., "const f = async (x) => ...").
+var functionValueKinds = map[string]bool{
+	"arrow_function":      true,
+	"function_expression": true,
+	"function":            true,
+	"generator_function":  true,
+	"func_literal":        true,
+	"lambda":              true,
+	"lambda_expression":   true,
+	"closure_expression":  true,
+}
+
+// paramListKinds are parameter lists of grammars that do not label them
+// with a "parameters" field.
+var paramListKinds = map[string]bool{
+	"function_value_parameters": true, // Kotlin
+}
+
+// variadicKinds are parameter (or parameter pattern) nodes of rest parameters.
+var variadicKinds = map[string]bool{
+	"variadic_parameter_declaration": true, // Go
+	"variadic_parameter":             true, // C, C++, PHP, Rust
+	"spread_parameter":               true, // Java
+	"rest_pattern":                   true, // TypeScript
+	"list_splat_pattern":             true, // Python *args
+	"dictionary_splat_pattern":       true, // Python **kwargs
+	"splat_parameter":                true, // Ruby *args
+	"hash_splat_parameter":           true, // Ruby **opts
+	"vararg_expression":              true, // Lua ...
+}
+
+// identifierKinds are leaf name nodes.
+var identifierKinds = map[string]bool{
+	"identifier":        true,
+	"type_identifier":   true,
+	"field_identifier":  true,
+	"simple_identifier": true,
+	"lifetime":          true,
+}
+
+// skipParamKinds are parameter list entries that are not parameters.
+var skipParamKinds = map[string]bool{
+	"comment":              true,
+	"line_comment":         true,
+	"block_comment":        true,
+	"positional_separator": true, // Python "/"
+	"keyword_separator":    true, // Python "*"
+}
+
+// modifierKeywords are keyword tokens reported as Signature.Modifiers.
+// Visibility keywords are left out on purpose.
+var modifierKeywords = map[string]bool{
+	"abstract":     true,
+	"async":        true,
+	"static":       true,
+	"final":        true,
+	"override":     true,
+	"virtual":      true,
+	"sealed":       true,
+	"open":         true,
+	"suspend":      true,
+	"inline":       true,
+	"unsafe":       true,
+	"extern":       true,
+	"readonly":     true,
+	"synchronized": true,
+	"native":       true,
+	"transient":    true,
+	"volatile":     true,
+	"strictfp":     true,
+	"partial":      true,
+	"declare":      true,
+	"mutating":     true,
+	"constexpr":    true,
+	"explicit":     true,
+	"data":         true,
+	"inner":        true,
+	"lateinit":     true,
+	"operator":     true,
+	"infix":        true,
+	"tailrec":      true,
+	"lazy":         true,
+}
+
+// modifierSkipKinds are subtrees searched for no modifiers: annotations and
+// decorators, and parameter lists ahead of the name (Go receivers, C++
+// template parameters).
+var modifierSkipKinds = map[string]bool{
+	"decorator":               true,
+	"annotation":              true,
+	"marker_annotation":       true,
+	"attribute_item":          true,
+	"attribute_list":          true,
+	"attribute":               true,
+	"parameter_list":          true,
+	"template_parameter_list": true,
+	"type_parameters":         true,
+}
+
+// decompose fills the structured fields of sig (parameters, return types,
+// type parameters, receiver and modifiers) from the syntax tree of its
+// declaration. sigNode is the captured declaration and nameNode its name.
+func decompose(sig *parser.Signature, lang string, sigNode, nameNode *sitter.Node, content []byte) {
+	fn := functionNode(sigNode, nameNode)
+	if fn != nil {
+		if list := paramList(fn); list != nil {
+			sig.Params = parameters(list, content)
+		}
+		sig.Returns = returnTypes(fn, content)
+		if recv := fn.ChildByFieldName("receiver"); recv != nil && lang == "go" {
+			if ps := parameters(recv, content); len(ps) == 1 {
+				sig.Receiver = &ps[0]
+			}
+		}
+	}
+	if list := typeParamList(sigNode, nameNode, fn); list != nil {
+		sig.TypeParams = typeParameters(list, content)
+	}
+	sig.Modifiers = modifiers(sigNode, nameNode, fn, content)
+}
+
+// within reports whether n lies inside outer.
+func within(n, outer *sitter.Node) bool {
+	return outer.StartByte() <= n.StartByte() && n.EndByte() <= outer.EndByte()
+}
+
+// functionNode returns the node holding the parameter list of the
+// declaration named by nameNode: the nearest enclosing node with parameters,
+// or the function assigned to a variable. It returns nil for declarations
+// that are not functions.
+func functionNode(sigNode, nameNode *sitter.Node) *sitter.Node {
+	for n := nameNode.Parent(); n != nil && within(n, sigNode); n = n.Parent() {
+		// C++ template parameters are type parameters, not function parameters
+		if n.Kind() != "template_declaration" && paramList(n) != nil {
+			return n
+		}
+		if v := n.ChildByFieldName("value"); v != nil && functionValueKinds[v.Kind()] {
+			return v
+		}
+	}
+	return nil
+}
+
+// paramList returns the parameter list of fn, or nil.
+func paramList(fn *sitter.Node) *sitter.Node {
+	if list := fn.ChildByFieldName("parameters"); list != nil {
+		return list
+	}
+	for i := uint(0); i < fn.NamedChildCount(); i++ {
+		if c := fn.NamedChild(i); c != nil && paramListKinds[c.Kind()] {
+			return c
+		}
+	}
+	return nil
+}
+
+// parameters decomposes the entries of a parameter list.
+func parameters(list *sitter.Node, content []byte) []parser.Parameter {
+	var params []parser.Parameter
+	for i := uint(0); i < list.NamedChildCount(); i++ {
+		c := list.NamedChild(i)
+		if c == nil || c.IsExtra() || skipParamKinds[c.Kind()] {
+			continue
+		}
+		params = append(params, parameter(c, content)...)
+	}
+	// C "(void)" declares no parameters
+	if len(params) == 1 && params[0].Name == "" && params[0].Type == "void" {
+		return nil
+	}
+	return params
+}
+
+// parameter decomposes one parameter list entry. Go declarations with
+// several names ("a, b int") yield one parameter per name.
+func parameter(n *sitter.Node, content []byte) []parser.Parameter {
+	p := parser.Parameter{Variadic: variadicKinds[n.Kind()]}
+
+	switch {
+	case n.Kind() == "self_parameter": // Rust "&mut self"
+		return []parser.Parameter{{Name: "self", Type: nodeText(n, content)}}
+	case n.NamedChildCount() == 0 || identifierKinds[n.Kind()]: // Python, Ruby, Lua, JavaScript
+		if text := nodeText(n, content); text != "..." {
+			p.Name = text
+		}
+		return []parser.Parameter{p}
+	}
+
+	if t := n.ChildByFieldName("type"); t != nil {
+		p.Type = typeText(t, content)
+	}
+	var def *sitter.Node
+	if def = n.ChildByFieldName("value"); def == nil {
+		def = n.ChildByFieldName("default_value")
+	}
+	if def != nil {
+		p.Default = nodeText(def, content)
+	}
+
+	names := fieldChildren(n, "name")
+	if len(names) == 0 {
+		names = fieldChildren(n, "pattern")
+	}
+	if len(names) == 0 {
+		if d := n.ChildByFieldName("declarator"); d != nil {
+			// C/C++: the name is nested in pointer, reference and array
+			// declarators, which belong to the type
+			name := declaratorName(d)
+			if name != nil {
+				names = []*sitter.Node{name}
+			}
+			p.Type = textAround(n, name, def, content)
+		}
+	}
+	if len(names) == 0 && n.Kind() == "spread_parameter" {
+		// Java "String... args": the type and a variable_declarator
+		for i := uint(0); i < n.NamedChildCount(); i++ {
+			c := n.NamedChild(i)
+			if c.Kind() == "variable_declarator" {
+				names = append(names, c)
+			} else if p.Type == "" {
+				p.Type = nodeText(c, content)
+			}
+		}
+	}
+	if len(names) == 0 {
+		// Python typed parameters: the name is the only unlabeled child
+		for i := uint(0); i < n.NamedChildCount(); i++ {
+			if n.FieldNameForNamedChild(uint32(i)) == "" {
+				names = append(names, n.NamedChild(i))
+				break
+			}
+		}
+	}
+
+	if len(names) == 0 {
+		return []parser.Parameter{p}
+	}
+	params := make([]parser.Parameter, 0, len(names))
+	for _, name := range names {
+		q := p
+		// "...rest", "*args", "String... rest": unwrap to the identifier
+		for name.NamedChildCount() > 0 && (variadicKinds[name.Kind()] || name.Kind() == "variable_declarator") {
+			q.Variadic = q.Variadic || variadicKinds[name.Kind()]
+			if inner := name.ChildByFieldName("name"); inner != nil {
+				name = inner
+			} else {
+				name = name.NamedChild(0)
+			}
+		}
+		q.Name = nodeText(name, content)
+		params = append(params, q)
+	}
+	return params
+}
+
+// declaratorName returns the identifier inside a C/C++ declarator, or nil
+// for abstract declarators without a name.
+func declaratorName(d *sitter.Node) *sitter.Node {
+	for {
+		if identifierKinds[d.Kind()] {
+			return d
+		}
+		if inner := d.ChildByFieldName("declarator"); inner != nil {
+			d = inner
+			continue
+		}
+		for i := uint(0); i < d.NamedChildCount(); i++ {
+			if c := d.NamedChild(i); identifierKinds[c.Kind()] {
+				return c
+			}
+		}
+		return nil
+	}
+}
+
+// textAround returns the text of n without the name and default value
+// (e.g., "const char *" for "const char *s").
+func textAround(n, name, def *sitter.Node, content []byte) string {
+	end := n.EndByte()
+	if def != nil {
+		end = def.StartByte()
+	}
+	text := string(content[n.StartByte():end])
+	if name != nil {
+		text = strings.TrimSpace(string(content[n.StartByte():name.StartByte()])) +
+			strings.TrimSpace(string(content[name.EndByte():end]))
+	}
+	text = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(text), "="))
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// returnTypes returns the declared return types of a function node.
+func returnTypes(fn *sitter.Node, content []byte) []string {
+	for _, field := range []string{"result", "return_type", "returns", "type"} {
+		r := fn.ChildByFieldName(field)
+		if r == nil {
+			continue
+		}
+		if r.Kind() == "parameter_list" {
+			// Go: "(n int, err error)"
+			var types []string
+			for _, p := range parameters(r, content) {
+				types = append(types, p.Type)
+			}
+			return types
+		}
+		return []string{typeText(r, content)}
+	}
+
+	// C/C++: the return type belongs to the declaration around the
+	// function declarator, plus any pointer or reference declarators
+	suffix := ""
+	n := fn
+	for strings.HasSuffix(n.Kind(), "declarator") && n.Parent() != nil {
+		switch n.Kind() {
+		case "pointer_declarator":
+			suffix += "*"
+		case "reference_declarator":
+			suffix += "&"
+		}
+		n = n.Parent()
+	}
+	if n != fn {
+		if t := n.ChildByFieldName("type"); t != nil {
+			if suffix != "" {
+				return []string{typeText(t, content) + " " + suffix}
+			}
+			return []string{typeText(t, content)}
+		}
+	}
+	return nil
+}
+
+// typeParamList returns the generic type parameter list of the declaration
+// named by nameNode, or nil.
+func typeParamList(sigNode, nameNode, fn *sitter.Node) *sitter.Node {
+	for n := nameNode.Parent(); n != nil && within(n, sigNode); n = n.Parent() {
+		if list := n.ChildByFieldName("type_parameters"); list != nil {
+			return list
+		}
+		if n.Kind() == "template_declaration" {
+			return n.ChildByFieldName("parameters")
+		}
+	}
+	if fn != nil {
+		if list := fn.ChildByFieldName("type_parameters"); list != nil {
+			return list
+		}
+	}
+	// C++ templates captured without their template_declaration
+	if parent := sigNode.Parent(); parent != nil && parent.Kind() == "template_declaration" {
+		return parent.ChildByFieldName("parameters")
+	}
+	return nil
+}
+
+// typeParameters decomposes the entries of a type parameter list.
+func typeParameters(list *sitter.Node, content []byte) []parser.TypeParameter {
+	var params []parser.TypeParameter
+	for i := uint(0); i < list.NamedChildCount(); i++ {
+		c := list.NamedChild(i)
+		if c == nil || c.IsExtra() || skipParamKinds[c.Kind()] {
+			continue
+		}
+		params = append(params, typeParameter(c, content)...)
+	}
+	return params
+}
+
+// typeParameter decomposes one type parameter. Go declarations with several
+// names ("K, V any") yield one type parameter per name.
+func typeParameter(n *sitter.Node, content []byte) []parser.TypeParameter {
+	if identifierKinds[n.Kind()] {
+		return []parser.TypeParameter{{Name: nodeText(n, content)}}
+	}
+
+	// Python "[T: int]": a type wrapping a constrained_type
+	if n.Kind() == "type" && n.NamedChildCount() == 1 {
+		if c := n.NamedChild(0); c.Kind() == "constrained_type" && c.NamedChildCount() == 2 {
+			return []parser.TypeParameter{{
+				Name:       nodeText(c.NamedChild(0), content),
+				Constraint: nodeText(c.NamedChild(1), content),
+			}}
+		}
+		return []parser.TypeParameter{{Name: nodeText(n, content)}}
+	}
+
+	var tp parser.TypeParameter
+	for _, field := range []string{"constraint", "bounds", "type"} {
+		if c := n.ChildByFieldName(field); c != nil {
+			tp.Constraint = typeText(c, content)
+			break
+		}
+	}
+	for _, field := range []string{"value", "default_type", "default_value"} {
+		if c := n.ChildByFieldName(field); c != nil {
+			tp.Default = strings.TrimSpace(strings.TrimPrefix(nodeText(c, content), "="))
+			break
+		}
+	}
+
+	names := fieldChildren(n, "name")
+	if len(names) == 0 {
+		names = fieldChildren(n, "left")
+	}
+	if len(names) == 0 {
+		if d := n.ChildByFieldName("declarator"); d != nil {
+			if name := declaratorName(d); name != nil {
+				names = []*sitter.Node{name}
+			}
+		}
+	}
+	for i := uint(0); i < n.NamedChildCount(); i++ {
+		c := n.NamedChild(i)
+		switch {
+		case len(names) == 0 && identifierKinds[c.Kind()]:
+			// Java and C++ "typename T": an unlabeled identifier
+			names = append(names, c)
+		case tp.Constraint == "" && (c.Kind() == "type_bound" || c.Kind() == "trait_bounds"):
+			tp.Constraint = typeText(c, content)
+		}
+	}
+
+	if len(names) == 0 {
+		tp.Name = nodeText(n, content)
+		return []parser.TypeParameter{tp}
+	}
+	params := make([]parser.TypeParameter, 0, len(names))
+	for _, name := range names {
+		q := tp
+		q.Name = nodeText(name, content)
+		params = append(params, q)
+	}
+	return params
+}
+
+// modifiers collects the modifier keywords of a declaration: those ahead of
+// its name, those of an assigned function (e.g., "async" in
+// "const f = async () => ..."), and C++ "override"/"final" specifiers.
+func modifiers(sigNode, nameNode, fn *sitter.Node, content []byte) []string {
+	mods := collectModifiers(sigNode, nameNode.StartByte(), content, false, nil)
+	if fn == nil {
+		return mods
+	}
+	if fn.StartByte() > nameNode.StartByte() {
+		limit := fn.EndByte()
+		if list := paramList(fn); list != nil {
+			limit = list.StartByte()
+		}
+		mods = collectModifiers(fn, limit, content, false, mods)
+	}
+	for i := uint(0); i < fn.NamedChildCount(); i++ {
+		if c := fn.NamedChild(i); c.Kind() == "virtual_specifier" {
+			mods = appendModifier(mods, nodeText(c, content))
+		}
+	}
+	return mods
+}
+
+// collectModifiers appends the modifier keyword tokens of n that start
+// before limit. Inside a Rust function_modifiers node every keyword counts
+// (e.g., "const" in "const fn").
+func collectModifiers(n *sitter.Node, limit uint, content []byte, all bool, mods []string) []string {
+	for i := uint(0); i < n.ChildCount(); i++ {
+		c := n.Child(i)
+		if c == nil || c.StartByte() >= limit {
+			break
+		}
+		if modifierSkipKinds[c.Kind()] {
+			continue
+		}
+		if c.ChildCount() > 0 {
+			mods = collectModifiers(c, limit, content, all || c.Kind() == "function_modifiers", mods)
+			continue
+		}
+		// Keywords are anonymous nodes; identifiers such as a type named
+		// "data" are named
+		if c.IsNamed() {
+			continue
+		}
+		if tok := nodeText(c, content); modifierKeywords[tok] || (all && tok != "") {
+			mods = appendModifier(mods, tok)
+		}
+	}
+	return mods
+}
+
+// appendModifier appends mod unless it is already present.
+func appendModifier(mods []string, mod string) []string {
+	if slices.Contains(mods, mod) {
+		return mods
+	}
+	return append(mods, mod)
+}
+
+// fieldChildren returns the children of n labeled with field.
+func fieldChildren(n *sitter.Node, field string) []*sitter.Node {
+	var children []*sitter.Node
+	for i := uint(0); i < n.ChildCount(); i++ {
+		if n.FieldNameForChild(uint32(i)) == field {
+			children = append(children, n.Child(i))
+		}
+	}
+	return children
+}
+
+// nodeText returns the source text of n with whitespace runs collapsed.
+func nodeText(n *sitter.Node, content []byte) string {
+	start, end := n.StartByte(), n.EndByte()
+	if end > uint(len(content)) || start > end {
+		return ""
+	}
+	return strings.Join(strings.Fields(string(content[start:end])), " ")
+}
+
+// typeText returns the text of a type node without the leading ":", "->"
+// or "extends" of annotations and bounds (e.g., "string" for ": string").
+func typeText(n *sitter.Node, content []byte) string {
+	text := nodeText(n, content)
+	for _, prefix := range []string{":", "->", "extends "} {
+		text = strings.TrimSpace(strings.TrimPrefix(text, prefix))
+	}
+	return text
+}
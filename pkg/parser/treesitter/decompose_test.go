@@ -0,0 +1,184 @@
+package treesitter
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/indigo-net/Brf.it/pkg/parser"
+)
+
+// describeStructure renders the structured fields of sig compactly:
+// "params | returns | type params | receiver | modifiers".
+func describeStructure(sig parser.Signature) string {
+	param := func(p parser.Parameter) string {
+		s := p.Name
+		if p.Variadic {
+			s = "..." + s
+		}
+		if p.Type != "" {
+			s += ":" + p.Type
+		}
+		if p.Default != "" {
+			s += "=" + p.Default
+		}
+		return s
+	}
+	var params, tparams []string
+	for _, p := range sig.Params {
+		params = append(params, param(p))
+	}
+	for _, tp := range sig.TypeParams {
+		tparams = append(tparams, param(parser.Parameter{Name: tp.Name, Type: tp.Constraint, Default: tp.Default}))
+	}
+	recv := ""
+	if sig.Receiver != nil {
+		recv = param(*sig.Receiver)
+	}
+	return fmt.Sprintf("%s | %s | %s | %s | %s",
+		strings.Join(params, ", "), strings.Join(sig.Returns, ", "), strings.Join(tparams, ", "),
+		recv, strings.Join(sig.Modifiers, " "))
+}
+
+func TestSignatureStructure(t *testing.T) {
+	tests := []struct {
+		name string
+		lang string
+		src  string
+		want map[string]string // name -> describeStructure
+	}{
+		{
+			name: "go",
+			lang: "go",
+			src: `package a
+
+func (s *FileScanner) Scan(ctx context.Context, a, b int, opts ...Option) (n int, err error) { return 0, nil }
+
+func Map[K comparable, V any](m map[K]V) []K { return nil }
+
+type List[T any] struct{}
+`,
+			want: map[string]string{
+				"Scan": "ctx:context.Context, a:int, b:int, ...opts:Option | int, error |  | s:*FileScanner | ",
+				"Map":  "m:map[K]V | []K | K:comparable, V:any |  | ",
+				"List": " |  | T:any |  | ",
+			},
+		},
+		{
+			name: "typescript",
+			lang: "typescript",
+			src: `function f<T extends Base = Def>(a: string, b?: number, c = 3, ...rest: T[]): Promise<void> {}
+class C {
+  static async m(x: number): string { return ""; }
+}
+const g = async (x: number): Promise<X> => x;
+`,
+			want: map[string]string{
+				"f": "a:string, b:number, c=3, ...rest:T[] | Promise<void> | T:Base=Def |  | ",
+				"m": "x:number | string |  |  | static async",
+				"g": "x:number | Promise<X> |  |  | async",
+			},
+		},
+		{
+			name: "python",
+			lang: "python",
+			src: `class A:
+    @staticmethod
+    async def m(self, a, b: int, c=1, d: str = "x", *args, e, **kw) -> List[int]:
+        pass
+`,
+			want: map[string]string{
+				"m": `self, a, b:int, c=1, d:str="x", ...args, e, ...kw | List[int] |  |  | async`,
+			},
+		},
+		{
+			name: "java",
+			lang: "java",
+			src: `public abstract class A<T extends Comparable<T>> {
+    @Override
+    public static final <K> List<K> m(int a, String... rest) throws IOException { return null; }
+}
+`,
+			want: map[string]string{
+				"A": " |  | T:Comparable<T> |  | abstract",
+				"m": "a:int, ...rest:String | List<K> | K |  | static final",
+			},
+		},
+		{
+			name: "c",
+			lang: "c",
+			src: `static const char *f(const char *s, int n[], ...) { return s; }
+int g(void);
+`,
+			want: map[string]string{
+				"f": "s:const char *, n:int[], ... | char * |  |  | static",
+				"g": " | int |  |  | ",
+			},
+		},
+		{
+			name: "cpp",
+			lang: "cpp",
+			src: `template <typename T, int N = 3>
+std::vector<T> convert(const T& x, int y = 2) { return {}; }
+`,
+			want: map[string]string{
+				"convert": "x:const T&, y:int=2 | std::vector<T> | T, N:int=3 |  | ",
+			},
+		},
+		{
+			name: "rust",
+			lang: "rust",
+			src: `pub async unsafe fn f<'a, T: Display + Clone>(&mut self, x: &'a T) -> Result<(), E> { todo!() }
+pub const fn g() {}
+`,
+			want: map[string]string{
+				"f": "self:&mut self, x:&'a T | Result<(), E> | 'a, T:Display + Clone |  | async unsafe",
+				"g": " |  |  |  | const",
+			},
+		},
+		{
+			name: "php",
+			lang: "php",
+			src: `<?php
+abstract class A {
+    final public static function m(?int $a, string ...$rest): ?string {}
+}
+`,
+			want: map[string]string{
+				"A": " |  |  |  | abstract",
+				"m": "$a:?int, ...$rest:string | ?string |  |  | final static",
+			},
+		},
+		{
+			name: "ruby",
+			lang: "ruby",
+			src: `def m(a, b = 1, *args, c:, **opts, &blk)
+end
+`,
+			want: map[string]string{
+				"m": "a, b=1, ...args, c, ...opts, blk |  |  |  | ",
+			},
+		},
+	}
+
+	p := NewTreeSitterParser()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := p.Parse([]byte(tt.src), &parser.Options{Language: tt.lang, IncludePrivate: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make(map[string]string)
+			for _, sig := range result.Signatures {
+				if _, ok := got[sig.Name]; !ok {
+					got[sig.Name] = describeStructure(sig)
+				}
+			}
+			for name, want := range tt.want {
+				if g, ok := got[name]; !ok || g != want {
+					t.Errorf("%s:\n got %q (found %v)\nwant %q", name, g, ok, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package treesitter
+
+import (
+	"strings"
+
+	sitter "github.com/tree-sitter/go-tree-sitter"
+
+	"github.com/indigo-net/Brf.it/pkg/parser"
+)
+
+// decoratorKinds are the nodes of a single decorator, annotation or
+// attribute, or of a bracketed attribute list (e.g., C# "[A, B]", PHP
+// "#[A]", C++ "[[nodiscard]]").
+var decoratorKinds = map[string]bool{
+	"decorator":             true,
+	"annotation":            true,
+	"marker_annotation":     true,
+	"attribute_item":        true,
+	"attribute_list":        true,
+	"attribute_declaration": true,
+}
+
+// decoratorListKinds are decorator nodes that may hold several attributes.
+var decoratorListKinds = map[string]bool{
+	"attribute_list":        true,
+	"attribute_declaration": true,
+}
+
+// decoratorScanSkipKinds are subtrees ahead of a declaration's name that
+// hold no decorators of the declaration itself (Go receivers, C++ template
+// parameters).
+var decoratorScanSkipKinds = map[string]bool{
+	"parameter_list":          true,
+	"template_parameter_list": true,
+	"type_parameters":         true,
+}
+
+// decorators collects the decorators, annotations and attributes of a
+// declaration: those preceding sigNode as siblings (Python decorated
+// definitions, Rust attribute items, TypeScript class members) and those
+// inside it ahead of its name (Java modifiers, PHP and C# attribute lists).
+// It also returns the end of the decorators that open sigNode, so callers
+// can drop them from the signature text; it is 0 when there are none.
+func decorators(lang string, sigNode, nameNode *sitter.Node, content []byte) ([]parser.Decorator, uint) {
+	var nodes []*sitter.Node
+	for s := sigNode.PrevSibling(); s != nil; s = s.PrevSibling() {
+		if isDecoratorNode(lang, s) {
+			nodes = append([]*sitter.Node{s}, nodes...)
+			continue
+		}
+		// Keywords such as "export" and comments may sit between a
+		// declaration and its decorators
+		if !s.IsNamed() || isCommentNode(s) {
+			continue
+		}
+		break
+	}
+
+	inner := collectDecoratorNodes(lang, sigNode, nameNode.StartByte(), nil)
+	prefixEnd := uint(0)
+	pos := sigNode.StartByte()
+	for _, n := range inner {
+		if strings.TrimSpace(string(content[pos:n.StartByte()])) != "" {
+			break
+		}
+		pos = n.EndByte()
+		prefixEnd = pos
+	}
+	nodes = append(nodes, inner...)
+
+	var decs []parser.Decorator
+	for _, n := range nodes {
+		decs = append(decs, decoratorItems(n, content)...)
+	}
+	return decs, prefixEnd
+}
+
+// isDecoratorNode reports whether n is a decorator node. Swift attributes
+// ("@objc") share their kind with other languages' attribute expressions.
+func isDecoratorNode(lang string, n *sitter.Node) bool {
+	return decoratorKinds[n.Kind()] || (lang == "swift" && n.Kind() == "attribute")
+}
+
+// isCommentNode reports whether n is a comment (e.g., "comment",
+// "line_comment", "block_comment").
+func isCommentNode(n *sitter.Node) bool {
+	return strings.Contains(n.Kind(), "comment")
+}
+
+// collectDecoratorNodes appends the decorator nodes of n that start before
+// limit, in source order.
+func collectDecoratorNodes(lang string, n *sitter.Node, limit uint, nodes []*sitter.Node) []*sitter.Node {
+	for i := uint(0); i < n.NamedChildCount(); i++ {
+		c := n.NamedChild(i)
+		if c == nil || c.StartByte() >= limit {
+			break
+		}
+		switch {
+		case isDecoratorNode(lang, c):
+			nodes = append(nodes, c)
+		case decoratorScanSkipKinds[c.Kind()]:
+		default:
+			nodes = collectDecoratorNodes(lang, c, limit, nodes)
+		}
+	}
+	return nodes
+}
+
+// decoratorItems splits decorator node n into its decorators. Attribute
+// lists yield one decorator per attribute, each wrapped in the list's
+// brackets (e.g., "[HttpGet]" and "[Authorize]" for "[HttpGet, Authorize]").
+func decoratorItems(n *sitter.Node, content []byte) []parser.Decorator {
+	text := nodeText(n, content)
+	if !decoratorListKinds[n.Kind()] {
+		return []parser.Decorator{newDecorator(text)}
+	}
+	attrs := attributeNodes(n, nil)
+	if len(attrs) == 0 {
+		return []parser.Decorator{newDecorator(text)}
+	}
+	open := text[:len(text)-len(strings.TrimLeft(text, "#@["))]
+	closing := text[len(strings.TrimRight(text, "]")):]
+	decs := make([]parser.Decorator, 0, len(attrs))
+	for _, a := range attrs {
+		decs = append(decs, newDecorator(open+nodeText(a, content)+closing))
+	}
+	return decs
+}
+
+// attributeNodes appends the outermost "attribute" nodes below n.
+func attributeNodes(n *sitter.Node, attrs []*sitter.Node) []*sitter.Node {
+	for i := uint(0); i < n.NamedChildCount(); i++ {
+		c := n.NamedChild(i)
+		if c.Kind() == "attribute" {
+			attrs = append(attrs, c)
+			continue
+		}
+		attrs = attributeNodes(c, attrs)
+	}
+	return attrs
+}
+
+// newDecorator builds a Decorator from its source text, splitting off the
+// name and the arguments (e.g., "app.route" and `"/users"` for
+// `@app.route("/users")`, "path" and `"x.rs"` for `#[path = "x.rs"]`).
+func newDecorator(text string) parser.Decorator {
+	body := text
+	if strings.HasPrefix(body, "@") {
+		body = body[1:]
+	} else {
+		body = strings.TrimPrefix(body, "#")
+		for strings.HasPrefix(body, "[") && strings.HasSuffix(body, "]") {
+			body = body[1 : len(body)-1]
+		}
+	}
+	body = strings.TrimSpace(body)
+
+	dec := parser.Decorator{Name: body, Text: text}
+	if i := strings.IndexByte(body, '('); i > 0 && strings.HasSuffix(body, ")") {
+		dec.Name = strings.TrimSpace(body[:i])
+		dec.Args = strings.TrimSpace(body[i+1 : len(body)-1])
+	} else if i := strings.IndexByte(body, '='); i > 0 {
+		dec.Name = strings.TrimSpace(body[:i])
+		dec.Args = strings.TrimSpace(body[i+1:])
+	}
+	return dec
+}
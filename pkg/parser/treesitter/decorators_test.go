@@ -0,0 +1,179 @@
+package treesitter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/indigo-net/Brf.it/pkg/parser"
+)
+
+func TestSignatureDecorators(t *testing.T) {
+	tests := []struct {
+		name     string
+		lang     string
+		src      string
+		want     map[string]string // name -> decorator texts joined by " "
+		wantText map[string]string // name -> signature text
+	}{
+		{
+			name: "python",
+			lang: "python",
+			src: `@app.route("/users", methods=["GET"])
+@login_required
+def list_users():
+    pass
+
+@dataclass(frozen=True)
+class P:
+    @property
+    def x(self):
+        return 1
+
+def plain():
+    pass
+`,
+			want: map[string]string{
+				"list_users": `@app.route("/users", methods=["GET"]) @login_required`,
+				"P":          "@dataclass(frozen=True)",
+				"x":          "@property",
+				"plain":      "",
+			},
+			wantText: map[string]string{"list_users": "def list_users()"},
+		},
+		{
+			name: "java",
+			lang: "java",
+			src: `@Entity
+@Table(name = "users")
+public class User {
+    @Override
+    private String toString() { return ""; }
+}
+`,
+			want: map[string]string{
+				"User":     `@Entity @Table(name = "users")`,
+				"toString": "@Override",
+			},
+			wantText: map[string]string{"User": "public class User", "toString": "private String toString()"},
+		},
+		{
+			name: "rust",
+			lang: "rust",
+			src: `#[derive(Debug, Clone)]
+#[cfg(test)]
+pub struct S { x: i32 }
+
+/// Docs.
+#[inline]
+pub fn f() {}
+
+pub fn g() {}
+`,
+			want: map[string]string{
+				"S": "#[derive(Debug, Clone)] #[cfg(test)]",
+				"f": "#[inline]",
+				"g": "",
+			},
+		},
+		{
+			name: "typescript",
+			lang: "typescript",
+			src: `@Component({selector: "app"})
+export class C {
+  @Input() name: string;
+  @HostListener("click")
+  onClick(e: Event): void {}
+}
+`,
+			want: map[string]string{
+				"C":       `@Component({selector: "app"})`,
+				"onClick": `@HostListener("click")`,
+			},
+			wantText: map[string]string{"C": "class C"},
+		},
+		{
+			name: "php",
+			lang: "php",
+			src: `<?php
+#[Route("/x"), Pure]
+class A {
+    #[Pure]
+    public function f() {}
+}
+`,
+			want: map[string]string{
+				"A": `#[Route("/x")] #[Pure]`,
+				"f": "#[Pure]",
+			},
+			wantText: map[string]string{"A": "class A", "f": "public function f()"},
+		},
+	}
+
+	p := NewTreeSitterParser()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := p.Parse([]byte(tt.src), &parser.Options{Language: tt.lang, IncludePrivate: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make(map[string]string)
+			text := make(map[string]string)
+			for _, sig := range result.Signatures {
+				var decs []string
+				for _, dec := range sig.Decorators {
+					decs = append(decs, dec.Text)
+				}
+				got[sig.Name] = strings.Join(decs, " ")
+				text[sig.Name] = sig.Text
+			}
+			for name, want := range tt.want {
+				if g, ok := got[name]; !ok || g != want {
+					t.Errorf("%s: decorators = %q (found %v), want %q", name, g, ok, want)
+				}
+			}
+			for name, want := range tt.wantText {
+				if text[name] != want {
+					t.Errorf("%s: text = %q, want %q", name, text[name], want)
+				}
+			}
+		})
+	}
+}
+
+func TestAnnotatedJavaMethodVisibility(t *testing.T) {
+	src := `public class A {
+    @Override
+    private void hidden() {}
+}
+`
+	p := NewTreeSitterParser()
+	result, err := p.Parse([]byte(src), &parser.Options{Language: "java"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, sig := range result.Signatures {
+		if sig.Name == "hidden" {
+			t.Errorf("private annotated method should not be exported: %+v", sig)
+		}
+	}
+}
+
+func TestNewDecorator(t *testing.T) {
+	tests := []struct {
+		text string
+		want parser.Decorator
+	}{
+		{`@app.route("/users", methods=["GET"])`, parser.Decorator{Name: "app.route", Args: `"/users", methods=["GET"]`}},
+		{"@Override", parser.Decorator{Name: "Override"}},
+		{"#[derive(Debug, Clone)]", parser.Decorator{Name: "derive", Args: "Debug, Clone"}},
+		{`#[path = "x.rs"]`, parser.Decorator{Name: "path", Args: `"x.rs"`}},
+		{`[HttpGet("x")]`, parser.Decorator{Name: "HttpGet", Args: `"x"`}},
+		{"[[nodiscard]]", parser.Decorator{Name: "nodiscard"}},
+	}
+	for _, tt := range tests {
+		tt.want.Text = tt.text
+		if got := newDecorator(tt.text); got != tt.want {
+			t.Errorf("newDecorator(%q) = %+v, want %+v", tt.text, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package treesitter
+
+import (
+	"regexp"
+	"strings"
+
+	sitter "github.com/tree-sitter/go-tree-sitter"
+
+	"github.com/indigo-net/Brf.it/pkg/parser"
+)
+
+var (
+	// namedDeprecationNote matches the message argument of a deprecation
+	// attribute (Rust note = "...", Swift message: "...", Python
+	// details="...").
+	namedDeprecationNote = regexp.MustCompile(`\b(?:note|message|reason|details)\s*[=:]\s*"([^"]*)"`)
+
+	// positionalDeprecationNote matches a leading string argument (Kotlin
+	// @Deprecated("..."), C# [Obsolete("...")], Python @deprecated("...")).
+	positionalDeprecationNote = regexp.MustCompile(`^\s*[rRuU]?(?:"([^"]*)"|'([^']*)')`)
+
+	// assignedDeprecationNote matches the Rust #[deprecated = "..."] form.
+	assignedDeprecationNote = regexp.MustCompile(`=\s*"([^"]*)"\s*\]$`)
+)
+
+// deprecation reports whether a declaration is deprecated, and the note
+// given with the deprecation. It looks at deprecation decorators, annotations
+// and attributes (@Deprecated, #[deprecated], [Obsolete], Swift
+// @available(*, deprecated), Python @deprecated), at the doc ("@deprecated",
+// Go "Deprecated:" paragraphs, Sphinx ".. deprecated::"), and at Python
+// functions that call warnings.warn with a DeprecationWarning.
+func deprecation(lang string, sig *parser.Signature, sigNode *sitter.Node, content []byte) (bool, string) {
+	for _, dec := range sig.Decorators {
+		if isDeprecationDecorator(lang, dec) {
+			return true, deprecationNote(dec)
+		}
+	}
+	if sig.Doc != "" {
+		if d := parser.ParseDoc(sig.Doc); d.Deprecated {
+			return true, d.DeprecationNote
+		}
+	}
+	if lang == "python" && sigNode != nil {
+		return pythonDeprecationWarning(sigNode, content)
+	}
+	return false, ""
+}
+
+// isDeprecationDecorator reports whether dec marks its declaration
+// deprecated.
+func isDeprecationDecorator(lang string, dec parser.Decorator) bool {
+	name := dec.Name
+	if i := strings.LastIndexAny(name, `.\:`); i >= 0 {
+		name = name[i+1:]
+	}
+	name = strings.TrimSuffix(strings.ToLower(name), "attribute")
+	switch {
+	case name == "deprecated", name == "obsolete":
+		return true
+	case lang == "swift" && name == "available":
+		// @available(*, deprecated, message: "...")
+		for _, arg := range strings.Split(dec.Args, ",") {
+			if strings.TrimSpace(arg) == "deprecated" || strings.HasPrefix(strings.TrimSpace(arg), "deprecated:") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// deprecationNote returns the message given to a deprecation decorator.
+func deprecationNote(dec parser.Decorator) string {
+	if m := namedDeprecationNote.FindStringSubmatch(dec.Args); m != nil {
+		return m[1]
+	}
+	if m := positionalDeprecationNote.FindStringSubmatch(dec.Args); m != nil {
+		return m[1] + m[2]
+	}
+	if m := assignedDeprecationNote.FindStringSubmatch(dec.Text); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// pythonDeprecationWarning reports whether a Python function body calls
+// warnings.warn with a DeprecationWarning or PendingDeprecationWarning, and
+// the warning message when it is a plain string.
+func pythonDeprecationWarning(sigNode *sitter.Node, content []byte) (bool, string) {
+	body := sigNode.ChildByFieldName("body")
+	if sigNode.Kind() != "function_definition" || body == nil {
+		return false, ""
+	}
+	for i := uint(0); i < body.NamedChildCount(); i++ {
+		stmt := body.NamedChild(i)
+		if stmt.Kind() != "expression_statement" || stmt.NamedChildCount() == 0 {
+			continue
+		}
+		call := stmt.NamedChild(0)
+		if call.Kind() != "call" {
+			continue
+		}
+		fn := call.ChildByFieldName("function")
+		args := call.ChildByFieldName("arguments")
+		if fn == nil || args == nil {
+			continue
+		}
+		if name := nodeText(fn, content); name != "warnings.warn" && name != "warn" {
+			continue
+		}
+		if text := nodeText(args, content); strings.Contains(text, "DeprecationWarning") {
+			note := ""
+			if args.NamedChildCount() > 0 && args.NamedChild(0).Kind() == "string" {
+				note = cleanDocString(sourceText(args.NamedChild(0), content))
+			}
+			return true, note
+		}
+	}
+	return false, ""
+}
+
+// inheritDeprecation marks the members of deprecated containers deprecated.
+// parents holds the container index of each signature, -1 for top-level
+// ones.
+func inheritDeprecation(signatures []parser.Signature, parents []int) {
+	var deprecated func(i int) bool
+	deprecated = func(i int) bool {
+		if signatures[i].Deprecated {
+			return true
+		}
+		if p := parents[i]; p >= 0 && deprecated(p) {
+			signatures[i].Deprecated = true
+		}
+		return signatures[i].Deprecated
+	}
+	for i := range signatures {
+		deprecated(i)
+	}
+}
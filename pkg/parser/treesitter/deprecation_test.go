@@ -0,0 +1,167 @@
+package treesitter
+
+import (
+	"testing"
+
+	"github.com/indigo-net/Brf.it/pkg/parser"
+)
+
+func TestDeprecation(t *testing.T) {
+	type dep struct {
+		deprecated bool
+		note       string
+	}
+	tests := []struct {
+		name string
+		lang string
+		src  string
+		want map[string]dep
+	}{
+		{
+			name: "go",
+			lang: "go",
+			src: `package a
+
+// Old does it the old way.
+//
+// Deprecated: Use New instead.
+func Old() {}
+
+// New does it.
+func New() {}
+`,
+			want: map[string]dep{"Old": {true, "Use New instead."}, "New": {}},
+		},
+		{
+			name: "typescript jsdoc",
+			lang: "typescript",
+			src: `/**
+ * Fetches.
+ * @deprecated use load()
+ */
+export function fetch() {}
+
+export function load() {}
+`,
+			want: map[string]dep{"fetch": {true, "use load()"}, "load": {}},
+		},
+		{
+			name: "java annotation and members",
+			lang: "java",
+			src: `public class A {
+    @Deprecated(since = "9")
+    public void old() {}
+
+    public void cur() {}
+}
+
+@Deprecated
+public class B {
+    public void m() {}
+}
+`,
+			want: map[string]dep{"old": {true, ""}, "cur": {}, "B": {true, ""}, "m": {true, ""}},
+		},
+		{
+			name: "rust attribute",
+			lang: "rust",
+			src: `#[deprecated(since = "1.2", note = "use bar")]
+pub fn foo() {}
+
+#[deprecated = "gone"]
+pub fn baz() {}
+
+pub fn bar() {}
+`,
+			want: map[string]dep{"foo": {true, "use bar"}, "baz": {true, "gone"}, "bar": {}},
+		},
+		{
+			name: "CSharp obsolete",
+			lang: "csharp",
+			src: `public class A {
+    [Obsolete("Use Run")]
+    public void Go() {}
+
+    [System.ObsoleteAttribute]
+    public void Stop() {}
+
+    public void Run() {}
+}
+`,
+			want: map[string]dep{"Go": {true, "Use Run"}, "Stop": {true, ""}, "Run": {}},
+		},
+		{
+			name: "python",
+			lang: "python",
+			src: `import warnings
+from typing_extensions import deprecated
+
+@deprecated("use g")
+def f():
+    pass
+
+def h():
+    """Does h."""
+    warnings.warn("h is deprecated", DeprecationWarning, stacklevel=2)
+
+def g():
+    warnings.warn("careful", UserWarning)
+`,
+			want: map[string]dep{"f": {true, "use g"}, "h": {true, "h is deprecated"}, "g": {}},
+		},
+		{
+			name: "Kotlin annotation",
+			lang: "kotlin",
+			src: `@Deprecated("Use bar", ReplaceWith("bar()"))
+fun foo() {}
+
+fun bar() {}
+`,
+			want: map[string]dep{"foo": {true, "Use bar"}, "bar": {}},
+		},
+	}
+
+	p := NewTreeSitterParser()
+	defer p.Close()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := p.Parse([]byte(tt.src), &parser.Options{Language: tt.lang, IncludePrivate: true})
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			got := make(map[string]dep)
+			for _, sig := range result.Signatures {
+				if _, ok := tt.want[sig.Name]; ok {
+					got[sig.Name] = dep{sig.Deprecated, sig.DeprecationNote}
+				}
+			}
+			for name, want := range tt.want {
+				if g, ok := got[name]; !ok {
+					t.Errorf("%s: signature not found", name)
+				} else if g != want {
+					t.Errorf("%s: got %+v, want %+v", name, g, want)
+				}
+			}
+		})
+	}
+}
+
+func TestExcludeDeprecated(t *testing.T) {
+	src := `package a
+
+// Deprecated: Use New.
+func Old() {}
+
+// New does it.
+func New() {}
+`
+	p := NewTreeSitterParser()
+	defer p.Close()
+	result, err := p.Parse([]byte(src), &parser.Options{Language: "go", ExcludeDeprecated: true})
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(result.Signatures) != 1 || result.Signatures[0].Name != "New" {
+		t.Errorf("expected only New, got %+v", result.Signatures)
+	}
+}
@@ -0,0 +1,54 @@
+package treesitter
+
+import (
+	"strconv"
+
+	sitter "github.com/tree-sitter/go-tree-sitter"
+
+	"github.com/indigo-net/Brf.it/pkg/parser"
+)
+
+// maxDiagnostics caps the syntax errors reported per file. A file in the
+// wrong language or a truncated one can have thousands.
+const maxDiagnostics = 50
+
+// syntaxDiagnostics returns the syntax errors of a parsed file: its ERROR
+// nodes, whose contents are not searched further, and MISSING nodes the
+// parser inserted to recover.
+func syntaxDiagnostics(root *sitter.Node) []parser.Diagnostic {
+	if !root.HasError() {
+		return nil
+	}
+	var diags []parser.Diagnostic
+	var walk func(n *sitter.Node)
+	walk = func(n *sitter.Node) {
+		if len(diags) >= maxDiagnostics || !n.HasError() {
+			return
+		}
+		switch {
+		case n.IsError():
+			diags = append(diags, diagnostic(n, "syntax error"))
+			return
+		case n.IsMissing():
+			diags = append(diags, diagnostic(n, "missing "+strconv.Quote(n.Kind())))
+			return
+		}
+		for i := uint(0); i < n.ChildCount(); i++ {
+			walk(n.Child(i))
+		}
+	}
+	walk(root)
+	return diags
+}
+
+// diagnostic returns a diagnostic spanning n.
+func diagnostic(n *sitter.Node, message string) parser.Diagnostic {
+	start, end := n.StartPosition(), n.EndPosition()
+	return parser.Diagnostic{
+		Message:   message,
+		Line:      int(start.Row) + 1,
+		Column:    int(start.Column) + 1,
+		EndLine:   int(end.Row) + 1,
+		EndColumn: int(end.Column) + 1,
+	}
+}
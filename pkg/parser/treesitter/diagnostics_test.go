@@ -0,0 +1,54 @@
+package treesitter
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/indigo-net/Brf.it/pkg/parser"
+)
+
+func TestSyntaxDiagnostics(t *testing.T) {
+	tests := []struct {
+		name string
+		lang string
+		src  string
+		want []string
+	}{
+		{
+			name: "valid",
+			lang: "go",
+			src:  "package a\n\nfunc A() {}\n",
+			want: nil,
+		},
+		{
+			name: "error node",
+			lang: "go",
+			src:  "package a\n\nfunc A() {\n\tx := )\n}\n\nfunc B() {}\n",
+			want: []string{"4:2-4:8: syntax error"},
+		},
+		{
+			name: "missing token",
+			lang: "typescript",
+			src:  "export function f(a: number {\n}\n",
+			want: []string{`1:28-1:28: missing ")"`},
+		},
+	}
+
+	p := NewTreeSitterParser()
+	defer p.Close()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := p.Parse([]byte(tt.src), &parser.Options{Language: tt.lang})
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			var got []string
+			for _, d := range result.Diagnostics {
+				got = append(got, d.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,348 @@
+package treesitter
+
+import (
+	"regexp"
+	"strings"
+
+	sitter "github.com/tree-sitter/go-tree-sitter"
+)
+
+// docWrapperKinds are nodes that wrap a declaration without being a
+// container of their own (e.g., a TypeScript export statement around a
+// function, a Python decorated definition), so the declaration's doc
+// comment may precede the wrapper.
+var docWrapperKinds = map[string]bool{
+	"export_statement":     true, // TypeScript/JavaScript
+	"ambient_declaration":  true, // TypeScript "declare"
+	"lexical_declaration":  true, // TypeScript/JavaScript "const"/"let"
+	"variable_declaration": true, // JavaScript "var"
+	"decorated_definition": true, // Python
+	"template_declaration": true, // C++
+	"var_declaration":      true, // Go
+	"const_declaration":    true, // Go
+	"var_spec_list":        true, // Go "var (...)"
+	"body_statement":       true, // Ruby class bodies
+	"statement":            true, // SQL
+}
+
+// elixirDocAttributes are the Elixir module attributes that hold the doc of
+// the definition following them.
+var elixirDocAttributes = []string{"@doc", "@typedoc"}
+
+// elixirSkipAttributes are Elixir module attributes that may sit between a
+// doc attribute and its definition.
+var elixirSkipAttributes = []string{"@spec", "@impl", "@deprecated", "@dialyzer", "@since"}
+
+// goDirective matches Go directive comments (e.g., "//go:generate",
+// "//nolint:errcheck"), which are not part of the doc.
+var goDirective = regexp.MustCompile(`^//[a-z0-9_]+:\S`)
+
+// docComment returns the documentation of the declaration at sigNode,
+// found in the syntax tree: Python docstrings, Elixir "@doc" attributes,
+// and otherwise the comments right before the declaration. Decorators,
+// annotations and attributes may sit between the comments and the
+// declaration. It returns "" when the declaration has no doc.
+func docComment(lang string, sigNode *sitter.Node, content []byte) string {
+	switch lang {
+	case "python":
+		if doc := pythonDocstring(sigNode, content); doc != "" {
+			return doc
+		}
+	case "elixir":
+		if doc := elixirDoc(sigNode, content); doc != "" {
+			return doc
+		}
+	}
+
+	doc := wrappedComments(lang, sigNode, content)
+	if lang == "rust" {
+		// Inner docs ("//! ...") at the top of a module body document the module
+		if inner := rustInnerDoc(sigNode, content); inner != "" {
+			if doc == "" {
+				return inner
+			}
+			return doc + "\n" + inner
+		}
+	}
+	return doc
+}
+
+// wrappedComments returns the comments preceding sigNode or, when there
+// are none and sigNode opens a wrapper, those preceding the wrapper.
+func wrappedComments(lang string, sigNode *sitter.Node, content []byte) string {
+	for n := sigNode; n != nil; n = n.Parent() {
+		if doc := precedingComments(lang, n, content); doc != "" {
+			return doc
+		}
+		if p := n.Parent(); p == nil || !docWrapperKinds[p.Kind()] || !opensNode(n) {
+			break
+		}
+	}
+	return ""
+}
+
+// opensNode reports whether no named node other than comments, decorators
+// and template parameters precedes n within its parent.
+func opensNode(n *sitter.Node) bool {
+	for s := n.PrevNamedSibling(); s != nil; s = s.PrevNamedSibling() {
+		if !isCommentNode(s) && !decoratorKinds[s.Kind()] && s.Kind() != "template_parameter_list" {
+			return false
+		}
+	}
+	return true
+}
+
+// precedingComments joins the block of comments ahead of anchor, skipping
+// decorators in between. Consecutive comments must be on adjacent lines.
+// A blank line between the block and the declaration ends the doc, unless
+// the block ends with a dedicated doc comment ("///", "/** */", LuaDoc
+// "---"). Comments trailing code on the previous line are not docs. In
+// Rust only outer doc comments count; other comments are skipped.
+func precedingComments(lang string, anchor *sitter.Node, content []byte) string {
+	var block []string
+	next := anchor
+	for s := anchor.PrevSibling(); s != nil; s = s.PrevSibling() {
+		if isDecoratorNode(lang, s) {
+			if len(block) > 0 {
+				break
+			}
+			next = s
+			continue
+		}
+		if !isCommentNode(s) {
+			break
+		}
+		text := sourceText(s, content)
+		if isTrailingComment(s) || strings.HasPrefix(text, "#!") {
+			break
+		}
+		if lang == "rust" && !isRustOuterDoc(text) {
+			if len(block) > 0 {
+				break
+			}
+			next = s
+			continue
+		}
+		gap := int(next.StartPosition().Row) - lastRow(s)
+		if gap > 1 && (len(block) > 0 || !isDocMarkerComment(text)) {
+			break
+		}
+		block = append(block, text)
+		next = s
+	}
+	if len(block) == 0 {
+		return ""
+	}
+
+	lines := make([]string, 0, len(block))
+	for i := len(block) - 1; i >= 0; i-- {
+		text := block[i]
+		if lang == "go" && goDirective.MatchString(text) {
+			continue
+		}
+		lines = append(lines, cleanComment(text))
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// isTrailingComment reports whether comment n follows code on its line
+// (e.g., "x = 1 // note"), so it belongs to that code.
+func isTrailingComment(n *sitter.Node) bool {
+	prev := n.PrevSibling()
+	return prev != nil && !isCommentNode(prev) && lastRow(prev) == int(n.StartPosition().Row)
+}
+
+// lastRow returns the last row that n covers. Line comments of some
+// grammars include the newline and end at column 0 of the next row.
+func lastRow(n *sitter.Node) int {
+	end := n.EndPosition()
+	if end.Column == 0 && end.Row > n.StartPosition().Row {
+		return int(end.Row) - 1
+	}
+	return int(end.Row)
+}
+
+// isDocMarkerComment reports whether a comment uses dedicated doc syntax
+// ("///", "/** */", "---"), which marks it as documentation even when a
+// blank line separates it from the declaration.
+func isDocMarkerComment(text string) bool {
+	switch {
+	case strings.HasPrefix(text, "///"), strings.HasPrefix(text, "---"):
+		return !strings.HasPrefix(text, "////") && !strings.HasPrefix(text, "----")
+	case strings.HasPrefix(text, "/**"):
+		return !strings.HasPrefix(text, "/**/") && !strings.HasPrefix(text, "/***")
+	}
+	return false
+}
+
+// isRustOuterDoc reports whether a Rust comment is an outer doc comment
+// ("/// ..." or "/** ... */").
+func isRustOuterDoc(text string) bool {
+	return (strings.HasPrefix(text, "///") && !strings.HasPrefix(text, "////")) ||
+		(strings.HasPrefix(text, "/**") && !strings.HasPrefix(text, "/***") && !strings.HasPrefix(text, "/**/"))
+}
+
+// rustInnerDoc returns the inner doc comments ("//! ...", "/*! ... */")
+// that open the body of a Rust module.
+func rustInnerDoc(sigNode *sitter.Node, content []byte) string {
+	if sigNode.Kind() != "mod_item" {
+		return ""
+	}
+	body := sigNode.ChildByFieldName("body")
+	if body == nil {
+		return ""
+	}
+	return rustInnerDocComments(body, content)
+}
+
+// rustInnerDocComments joins the inner doc comments among the comments
+// that open container (a module body or source file). Other comments, such
+// as license headers, are skipped.
+func rustInnerDocComments(container *sitter.Node, content []byte) string {
+	var lines []string
+	for i := uint(0); i < container.NamedChildCount(); i++ {
+		c := container.NamedChild(i)
+		if !isCommentNode(c) {
+			break
+		}
+		if text := sourceText(c, content); strings.HasPrefix(text, "//!") || strings.HasPrefix(text, "/*!") {
+			lines = append(lines, cleanComment(text))
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// pythonDocstring returns the docstring of a Python function or class: a
+// string literal as the first statement of its body.
+func pythonDocstring(sigNode *sitter.Node, content []byte) string {
+	body := sigNode.ChildByFieldName("body")
+	if body == nil || body.NamedChildCount() == 0 {
+		return ""
+	}
+	stmt := body.NamedChild(0)
+	if stmt.Kind() != "expression_statement" || stmt.NamedChildCount() != 1 {
+		return ""
+	}
+	str := stmt.NamedChild(0)
+	if str.Kind() != "string" {
+		return ""
+	}
+	return cleanDocString(sourceText(str, content))
+}
+
+// elixirDoc returns the doc of an Elixir definition: the "@doc" or
+// "@typedoc" attribute before it, or the "@moduledoc" attribute that opens
+// a module's do block. "@doc false" hides a definition's doc.
+func elixirDoc(sigNode *sitter.Node, content []byte) string {
+	text := sourceText(sigNode, content)
+	if strings.HasPrefix(text, "defmodule") {
+		for i := uint(0); i < sigNode.NamedChildCount(); i++ {
+			block := sigNode.NamedChild(i)
+			if block.Kind() != "do_block" {
+				continue
+			}
+			for j := uint(0); j < block.NamedChildCount(); j++ {
+				attr := block.NamedChild(j)
+				if attr.Kind() != "unary_operator" {
+					continue
+				}
+				if doc, ok := elixirAttributeDoc(sourceText(attr, content), "@moduledoc"); ok {
+					return doc
+				}
+			}
+		}
+		return ""
+	}
+
+	for s := sigNode.PrevNamedSibling(); s != nil; s = s.PrevNamedSibling() {
+		if s.Kind() != "unary_operator" {
+			break
+		}
+		attr := sourceText(s, content)
+		for _, name := range elixirDocAttributes {
+			if doc, ok := elixirAttributeDoc(attr, name); ok {
+				return doc
+			}
+		}
+		if !hasAnyPrefix(attr, elixirSkipAttributes) {
+			break
+		}
+	}
+	return ""
+}
+
+// elixirAttributeDoc returns the doc string of module attribute text when
+// it is the attribute name (e.g., `@doc "Adds."`), and whether it is.
+func elixirAttributeDoc(text, name string) (string, bool) {
+	rest, ok := strings.CutPrefix(text, name)
+	if !ok || (rest != "" && rest[0] != ' ' && rest[0] != '\n' && rest[0] != '(') {
+		return "", false
+	}
+	rest = strings.TrimSpace(rest)
+	rest = strings.TrimSuffix(strings.TrimPrefix(rest, "("), ")")
+	if rest == "false" || rest == "nil" {
+		return "", true
+	}
+	// Sigils such as ~S"""...""" hold the doc verbatim
+	if len(rest) > 2 && rest[0] == '~' {
+		rest = rest[2:]
+	}
+	return cleanDocString(rest), true
+}
+
+// hasAnyPrefix reports whether s starts with any of prefixes.
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// cleanDocString strips the quotes of a string literal holding a doc
+// (Python docstrings, Elixir doc attributes) and removes the indentation
+// shared by its continuation lines.
+func cleanDocString(text string) string {
+	text = strings.TrimLeft(text, "rRuUbBfF")
+	for _, q := range []string{`"""`, `'''`, `"`, `'`} {
+		if len(text) >= 2*len(q) && strings.HasPrefix(text, q) && strings.HasSuffix(text, q) {
+			text = text[len(q) : len(text)-len(q)]
+			break
+		}
+	}
+	return dedent(text)
+}
+
+// dedent trims a multi-line doc: the indentation common to all lines after
+// the first is removed, as are leading and trailing blank lines.
+func dedent(text string) string {
+	lines := strings.Split(text, "\n")
+	indent := -1
+	for _, line := range lines[1:] {
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed == "" {
+			continue
+		}
+		if n := len(line) - len(trimmed); indent < 0 || n < indent {
+			indent = n
+		}
+	}
+	lines[0] = strings.TrimSpace(lines[0])
+	for i := 1; i < len(lines); i++ {
+		if len(lines[i]) >= indent && indent > 0 {
+			lines[i] = lines[i][indent:]
+		}
+		lines[i] = strings.TrimRight(lines[i], " \t")
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
+
+// sourceText returns the source text of n as written.
+func sourceText(n *sitter.Node, content []byte) string {
+	start, end := n.StartByte(), n.EndByte()
+	if end > uint(len(content)) || start > end {
+		return ""
+	}
+	return strings.TrimRight(string(content[start:end]), "\r\n")
+}
@@ -0,0 +1,220 @@
+package treesitter
+
+import (
+	"testing"
+
+	"github.com/indigo-net/Brf.it/pkg/parser"
+)
+
+func TestSignatureDocs(t *testing.T) {
+	tests := []struct {
+		name string
+		lang string
+		src  string
+		want map[string]string // name -> doc
+	}{
+		{
+			name: "go",
+			lang: "go",
+			src: `package a
+
+// Adjacent documents Adjacent.
+// It spans two lines.
+//
+//go:generate stringer
+func Adjacent() {}
+
+// Separated is not attached.
+
+func Separated() {}
+
+var (
+	// A documents A.
+	A = 1
+	B = 2 // trailing comment of B
+	C = 3
+)
+
+// X documents X.
+var X = 1
+`,
+			want: map[string]string{
+				"Adjacent":  "Adjacent documents Adjacent.\nIt spans two lines.",
+				"Separated": "",
+				"A":         "A documents A.",
+				"B":         "",
+				"C":         "",
+				"X":         "X documents X.",
+			},
+		},
+		{
+			name: "rust",
+			lang: "rust",
+			src: `/// Adds two numbers.
+#[inline]
+#[must_use]
+pub fn add() {}
+
+// A plain comment.
+pub fn plain() {}
+
+/// Outer docs.
+pub mod m {
+    //! Inner docs.
+}
+
+/** Block docs. */
+
+pub fn block() {}
+`,
+			want: map[string]string{
+				"add":   "Adds two numbers.",
+				"plain": "",
+				"m":     "Outer docs.\nInner docs.",
+				"block": "Block docs.",
+			},
+		},
+		{
+			name: "python",
+			lang: "python",
+			src: `# Comment of decorated.
+@decorator
+def decorated():
+    pass
+
+def docstring():
+    """Summary line.
+
+    Details.
+    """
+
+class Model:
+    '''Model docs.'''
+`,
+			want: map[string]string{
+				"decorated": "Comment of decorated.",
+				"docstring": "Summary line.\n\nDetails.",
+				"Model":     "Model docs.",
+			},
+		},
+		{
+			name: "typescript",
+			lang: "typescript",
+			src: `/**
+ * Creates a user.
+ * @param name the name
+ */
+
+export function createUser(name: string) {}
+
+// Not attached across a blank line.
+
+export function helper() {}
+
+class Service {
+  // Runs the service.
+  @Log()
+  run() {}
+}
+`,
+			want: map[string]string{
+				"createUser": "Creates a user.\n@param name the name",
+				"helper":     "",
+				"run":        "Runs the service.",
+			},
+		},
+		{
+			name: "java",
+			lang: "java",
+			src: `public class A {
+    /** Returns the name. */
+    @Override
+    public String name() { return ""; }
+}
+`,
+			want: map[string]string{
+				"name": "Returns the name.",
+			},
+		},
+		{
+			name: "cpp",
+			lang: "cpp",
+			src: `/// Identity.
+template <typename T>
+T id(T x) { return x; }
+`,
+			want: map[string]string{
+				"id": "Identity.",
+			},
+		},
+		{
+			name: "ruby",
+			lang: "ruby",
+			src: `class A
+  # Greets.
+  def greet
+  end
+end
+`,
+			want: map[string]string{
+				"greet": "Greets.",
+			},
+		},
+		{
+			name: "lua",
+			lang: "lua",
+			src: `--- Formats a value.
+-- @param v the value
+local function format(v) end
+`,
+			want: map[string]string{
+				"format": "Formats a value.\n@param v the value",
+			},
+		},
+	}
+
+	p := NewTreeSitterParser()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := p.Parse([]byte(tt.src), &parser.Options{Language: tt.lang, IncludePrivate: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make(map[string]string)
+			for _, sig := range result.Signatures {
+				if _, ok := got[sig.Name]; !ok {
+					got[sig.Name] = sig.Doc
+				}
+			}
+			for name, want := range tt.want {
+				if g, ok := got[name]; !ok || g != want {
+					t.Errorf("%s:\n got %q (found %v)\nwant %q", name, g, ok, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCleanComment(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "// Line.", want: "Line."},
+		{in: "/// Doc.", want: "Doc."},
+		{in: "//! Inner.", want: "Inner."},
+		{in: "# Hash.", want: "Hash."},
+		{in: "## Hashes.", want: "Hashes."},
+		{in: "--- LuaDoc.", want: "LuaDoc."},
+		{in: "-- Lua.", want: "Lua."},
+		{in: "--[[ Lua block. ]]", want: "Lua block."},
+		{in: "/* Block. */", want: "Block."},
+		{in: "/**\n * First.\n * Second.\n */", want: "First.\nSecond."},
+	}
+
+	for _, tt := range tests {
+		if got := cleanComment(tt.in); got != tt.want {
+			t.Errorf("cleanComment(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -1,16 +1,16 @@
 package languages
 
-import "strings"
-
-// hasVisibilityPrefix checks if the first line of sigText starts with the given modifier.
-// This avoids false positives from matching modifiers inside function bodies.
-func hasVisibilityPrefix(sigText, modifier string) bool {
-	first := sigText
-	if idx := strings.IndexByte(sigText, '\n'); idx >= 0 {
-		first = sigText[:idx]
-	}
-	return strings.HasPrefix(strings.TrimSpace(first), modifier+" ") ||
-		strings.Contains(first, " "+modifier+" ")
+import (
+	"slices"
+	"strings"
+
+	"github.com/indigo-net/Brf.it/pkg/parser"
+)
+
+// hasModifier reports whether head, the signature text ahead of the
+// declared name, contains modifier as a separate word.
+func hasModifier(head, modifier string) bool {
+	return slices.Contains(strings.Fields(head), modifier)
 }
 
 // BaseQuery provides default implementations for common LanguageQuery methods.
@@ -37,8 +37,9 @@ func (BaseQuery) CallQuery() []byte {
 	return nil
 }
 
-// IsExported returns true for any non-empty name by default.
-// Languages with explicit visibility rules should override this method.
-func (BaseQuery) IsExported(name, _ string) bool {
-	return len(name) > 0
+// Visibility returns "" by default: the declaration states no visibility,
+// so the parser applies the language default for its position.
+// Languages with visibility modifiers or naming rules override this method.
+func (BaseQuery) Visibility(_, _ string) parser.Visibility {
+	return ""
 }
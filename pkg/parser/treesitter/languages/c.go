@@ -2,10 +2,10 @@
 package languages
 
 import (
-	"strings"
-
 	sitter "github.com/tree-sitter/go-tree-sitter"
 	tree_sitter_c "github.com/tree-sitter/tree-sitter-c/bindings/go"
+
+	"github.com/indigo-net/Brf.it/pkg/parser"
 )
 
 // CQuery implements LanguageQuery for C language.
@@ -59,12 +59,13 @@ func (q *CQuery) CallQuery() []byte {
 	return []byte(cCallQueryPattern)
 }
 
-// IsExported returns true if the C signature is not file-local (static).
-func (q *CQuery) IsExported(name, sigText string) bool {
-	if len(name) == 0 {
-		return false
+// Visibility returns file for file-local (static) C declarations, or ""
+// for all others (public).
+func (q *CQuery) Visibility(_, head string) parser.Visibility {
+	if hasModifier(head, "static") {
+		return parser.VisibilityFile
 	}
-	return !strings.HasPrefix(sigText, "static ")
+	return ""
 }
 
 // cCallQueryPattern is the Tree-sitter query for extracting C function calls.
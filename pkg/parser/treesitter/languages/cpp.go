@@ -2,10 +2,10 @@
 package languages
 
 import (
-	"strings"
-
 	sitter "github.com/tree-sitter/go-tree-sitter"
 	tree_sitter_cpp "github.com/tree-sitter/tree-sitter-cpp/bindings/go"
+
+	"github.com/indigo-net/Brf.it/pkg/parser"
 )
 
 // CppQuery implements LanguageQuery for C++ language.
@@ -57,12 +57,11 @@ func (q *CppQuery) ImportQuery() []byte {
 	return []byte(cppImportQueryPattern)
 }
 
-// IsExported returns true if the C++ signature is not file-local (static).
-func (q *CppQuery) IsExported(name, sigText string) bool {
-	if len(name) == 0 {
-		return false
-	}
-	return !strings.HasPrefix(sigText, "static ")
+// Visibility returns "": C++ visibility comes from the access section of a
+// class member or from "static" at namespace scope, which the parser
+// resolves from the syntax tree ("static" on a member is not visibility).
+func (q *CppQuery) Visibility(_, _ string) parser.Visibility {
+	return ""
 }
 
 // cppImportQueryPattern is the Tree-sitter query for extracting C++ #include directives.
@@ -2,8 +2,10 @@
 package languages
 
 import (
-	tree_sitter_c_sharp "github.com/indigo-net/Brf.it/pkg/parser/treesitter/grammars/csharp"
 	sitter "github.com/tree-sitter/go-tree-sitter"
+
+	"github.com/indigo-net/Brf.it/pkg/parser"
+	tree_sitter_c_sharp "github.com/indigo-net/Brf.it/pkg/parser/treesitter/grammars/csharp"
 )
 
 // CSharpQuery implements LanguageQuery for C# language.
@@ -63,12 +65,24 @@ func (q *CSharpQuery) ImportQuery() []byte {
 	return []byte(csharpImportQueryPattern)
 }
 
-// IsExported returns true if the C# signature does not start with a private modifier.
-func (q *CSharpQuery) IsExported(name, sigText string) bool {
-	if len(name) == 0 {
-		return false
+// Visibility returns the access level of a C# declaration, or "" when it
+// has none; the default depends on whether it is a member or a top-level
+// type. "protected internal" counts as protected and "private protected" as
+// private.
+func (q *CSharpQuery) Visibility(_, head string) parser.Visibility {
+	switch {
+	case hasModifier(head, "public"):
+		return parser.VisibilityPublic
+	case hasModifier(head, "private"):
+		return parser.VisibilityPrivate
+	case hasModifier(head, "protected"):
+		return parser.VisibilityProtected
+	case hasModifier(head, "internal"):
+		return parser.VisibilityInternal
+	case hasModifier(head, "file"):
+		return parser.VisibilityFile
 	}
-	return !hasVisibilityPrefix(sigText, "private") && !hasVisibilityPrefix(sigText, "internal")
+	return ""
 }
 
 // csharpImportQueryPattern is the Tree-sitter query for extracting C# using directives.
@@ -4,8 +4,10 @@ package languages
 import (
 	"strings"
 
-	tree_sitter_elixir "github.com/indigo-net/Brf.it/pkg/parser/treesitter/grammars/elixir"
 	sitter "github.com/tree-sitter/go-tree-sitter"
+
+	"github.com/indigo-net/Brf.it/pkg/parser"
+	tree_sitter_elixir "github.com/indigo-net/Brf.it/pkg/parser/treesitter/grammars/elixir"
 )
 
 // ElixirQuery implements LanguageQuery for Elixir language.
@@ -50,12 +52,14 @@ func (q *ElixirQuery) ImportQuery() []byte {
 	return []byte(elixirImportQueryPattern)
 }
 
-// IsExported returns true if the Elixir definition uses def (not defp).
-func (q *ElixirQuery) IsExported(name, sigText string) bool {
-	if len(name) == 0 {
-		return false
+// Visibility returns private for defp, defmacrop and defguardp definitions
+// and public for all others.
+func (q *ElixirQuery) Visibility(_, head string) parser.Visibility {
+	switch strings.TrimSpace(head) {
+	case "defp", "defmacrop", "defguardp":
+		return parser.VisibilityPrivate
 	}
-	return !strings.HasPrefix(sigText, "defp ")
+	return parser.VisibilityPublic
 }
 
 // elixirImportQueryPattern is the Tree-sitter query for extracting Elixir
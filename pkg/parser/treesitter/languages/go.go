@@ -4,6 +4,8 @@ package languages
 import (
 	sitter "github.com/tree-sitter/go-tree-sitter"
 	tree_sitter_go "github.com/tree-sitter/tree-sitter-go/bindings/go"
+
+	"github.com/indigo-net/Brf.it/pkg/parser"
 )
 
 // Capture name constants (must match treesitter package constants).
@@ -64,12 +66,13 @@ func (q *GoQuery) CallQuery() []byte {
 	return []byte(goCallQueryPattern)
 }
 
-// IsExported returns true if the Go identifier starts with an uppercase letter.
-func (q *GoQuery) IsExported(name, _ string) bool {
-	if len(name) == 0 {
-		return false
+// Visibility returns public for Go identifiers starting with an uppercase
+// letter and package for all others.
+func (q *GoQuery) Visibility(name, _ string) parser.Visibility {
+	if len(name) > 0 && name[0] >= 'A' && name[0] <= 'Z' {
+		return parser.VisibilityPublic
 	}
-	return name[0] >= 'A' && name[0] <= 'Z'
+	return parser.VisibilityPackage
 }
 
 // goCallQueryPattern is the Tree-sitter query for extracting Go function calls.
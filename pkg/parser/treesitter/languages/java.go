@@ -4,6 +4,8 @@ package languages
 import (
 	sitter "github.com/tree-sitter/go-tree-sitter"
 	tree_sitter_java "github.com/tree-sitter/tree-sitter-java/bindings/go"
+
+	"github.com/indigo-net/Brf.it/pkg/parser"
 )
 
 // JavaQuery implements LanguageQuery for Java language.
@@ -57,12 +59,19 @@ func (q *JavaQuery) CallQuery() []byte {
 	return []byte(javaCallQueryPattern)
 }
 
-// IsExported returns true if the Java signature does not start with a private modifier.
-func (q *JavaQuery) IsExported(name, sigText string) bool {
-	if len(name) == 0 {
-		return false
+// Visibility returns the access modifier of a Java declaration, or "" for
+// package-private declarations and interface members, whose visibility
+// depends on their position.
+func (q *JavaQuery) Visibility(_, head string) parser.Visibility {
+	switch {
+	case hasModifier(head, "public"):
+		return parser.VisibilityPublic
+	case hasModifier(head, "protected"):
+		return parser.VisibilityProtected
+	case hasModifier(head, "private"):
+		return parser.VisibilityPrivate
 	}
-	return !hasVisibilityPrefix(sigText, "private")
+	return ""
 }
 
 // javaCallQueryPattern is the Tree-sitter query for extracting Java method invocations.
@@ -2,8 +2,10 @@
 package languages
 
 import (
-	tree_sitter_kotlin "github.com/indigo-net/Brf.it/pkg/parser/treesitter/grammars/kotlin"
 	sitter "github.com/tree-sitter/go-tree-sitter"
+
+	"github.com/indigo-net/Brf.it/pkg/parser"
+	tree_sitter_kotlin "github.com/indigo-net/Brf.it/pkg/parser/treesitter/grammars/kotlin"
 )
 
 // KotlinQuery implements LanguageQuery for Kotlin language.
@@ -52,12 +54,20 @@ func (q *KotlinQuery) ImportQuery() []byte {
 	return []byte(kotlinImportQueryPattern)
 }
 
-// IsExported returns true if the Kotlin signature does not start with a private modifier.
-func (q *KotlinQuery) IsExported(name, sigText string) bool {
-	if len(name) == 0 {
-		return false
+// Visibility returns the visibility modifier of a Kotlin declaration, or ""
+// when it has none (public by default).
+func (q *KotlinQuery) Visibility(_, head string) parser.Visibility {
+	switch {
+	case hasModifier(head, "public"):
+		return parser.VisibilityPublic
+	case hasModifier(head, "protected"):
+		return parser.VisibilityProtected
+	case hasModifier(head, "internal"):
+		return parser.VisibilityInternal
+	case hasModifier(head, "private"):
+		return parser.VisibilityPrivate
 	}
-	return !hasVisibilityPrefix(sigText, "private") && !hasVisibilityPrefix(sigText, "internal")
+	return ""
 }
 
 // kotlinImportQueryPattern is the Tree-sitter query for extracting Kotlin import statements.
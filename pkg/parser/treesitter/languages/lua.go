@@ -2,8 +2,10 @@
 package languages
 
 import (
-	tree_sitter_lua "github.com/indigo-net/Brf.it/pkg/parser/treesitter/grammars/lua"
 	sitter "github.com/tree-sitter/go-tree-sitter"
+
+	"github.com/indigo-net/Brf.it/pkg/parser"
+	tree_sitter_lua "github.com/indigo-net/Brf.it/pkg/parser/treesitter/grammars/lua"
 )
 
 // LuaQuery implements LanguageQuery for Lua language.
@@ -21,6 +23,15 @@ func NewLuaQuery() *LuaQuery {
 	}
 }
 
+// Visibility returns file for local functions and variables, or "" for
+// all others (public).
+func (q *LuaQuery) Visibility(_, head string) parser.Visibility {
+	if hasModifier(head, "local") {
+		return parser.VisibilityFile
+	}
+	return ""
+}
+
 // Language returns the Lua Tree-sitter language.
 func (q *LuaQuery) Language() *sitter.Language {
 	return q.language
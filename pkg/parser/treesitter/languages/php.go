@@ -4,6 +4,8 @@ package languages
 import (
 	sitter "github.com/tree-sitter/go-tree-sitter"
 	tree_sitter_php "github.com/tree-sitter/tree-sitter-php/bindings/go"
+
+	"github.com/indigo-net/Brf.it/pkg/parser"
 )
 
 // PHPQuery implements LanguageQuery for PHP language.
@@ -53,12 +55,18 @@ func (q *PHPQuery) ImportQuery() []byte {
 	return []byte(phpImportQueryPattern)
 }
 
-// IsExported returns true if the PHP signature does not start with a private modifier.
-func (q *PHPQuery) IsExported(name, sigText string) bool {
-	if len(name) == 0 {
-		return false
+// Visibility returns the visibility modifier of a PHP declaration, or ""
+// when it has none (public by default).
+func (q *PHPQuery) Visibility(_, head string) parser.Visibility {
+	switch {
+	case hasModifier(head, "public"):
+		return parser.VisibilityPublic
+	case hasModifier(head, "protected"):
+		return parser.VisibilityProtected
+	case hasModifier(head, "private"):
+		return parser.VisibilityPrivate
 	}
-	return !hasVisibilityPrefix(sigText, "private")
+	return ""
 }
 
 // phpImportQueryPattern is the Tree-sitter query for extracting PHP use/include statements.
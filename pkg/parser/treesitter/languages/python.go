@@ -1,8 +1,12 @@
 package languages
 
 import (
+	"strings"
+
 	sitter "github.com/tree-sitter/go-tree-sitter"
 	tree_sitter_python "github.com/tree-sitter/tree-sitter-python/bindings/go"
+
+	"github.com/indigo-net/Brf.it/pkg/parser"
 )
 
 // PythonQuery implements LanguageQuery for Python language.
@@ -52,13 +56,15 @@ func (q *PythonQuery) CallQuery() []byte {
 	return []byte(pythonCallQueryPattern)
 }
 
-// IsExported returns true if the Python name does not start with underscore.
-// Single underscore prefix (_name) is a convention for internal/private symbols.
-func (q *PythonQuery) IsExported(name, _ string) bool {
-	if len(name) == 0 {
-		return false
+// Visibility follows the Python naming convention: a leading underscore
+// (_name, __name) marks a private symbol, while dunder names such as
+// __init__ are public.
+func (q *PythonQuery) Visibility(name, _ string) parser.Visibility {
+	dunder := len(name) > 4 && strings.HasPrefix(name, "__") && strings.HasSuffix(name, "__")
+	if strings.HasPrefix(name, "_") && !dunder {
+		return parser.VisibilityPrivate
 	}
-	return name[0] != '_'
+	return parser.VisibilityPublic
 }
 
 // pythonCallQueryPattern is the Tree-sitter query for extracting Python function calls.
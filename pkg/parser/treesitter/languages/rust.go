@@ -2,8 +2,12 @@
 package languages
 
 import (
+	"strings"
+
 	sitter "github.com/tree-sitter/go-tree-sitter"
 	tree_sitter_rust "github.com/tree-sitter/tree-sitter-rust/bindings/go"
+
+	"github.com/indigo-net/Brf.it/pkg/parser"
 )
 
 // RustQuery implements LanguageQuery for Rust language.
@@ -63,12 +67,24 @@ func (q *RustQuery) CallQuery() []byte {
 	return []byte(rustCallQueryPattern)
 }
 
-// IsExported returns true for Rust items. In Rust, items without `pub` are
-// still crate-visible, which is exported enough for project-level analysis.
-// Only items explicitly marked with restricted visibility (e.g., inside impl
-// blocks) would be truly private, but that requires AST context beyond sigText.
-func (q *RustQuery) IsExported(name, _ string) bool {
-	return len(name) > 0
+// Visibility returns the visibility of a Rust item from its "pub" modifier:
+// "pub" is public, "pub(crate)" internal, "pub(super)" and "pub(in path)"
+// package, and "pub(self)" private. It returns "" for items without one,
+// whose visibility depends on their position (e.g., trait members).
+func (q *RustQuery) Visibility(_, head string) parser.Visibility {
+	for _, word := range strings.Fields(head) {
+		switch {
+		case word == "pub":
+			return parser.VisibilityPublic
+		case word == "pub(crate)":
+			return parser.VisibilityInternal
+		case word == "pub(self)":
+			return parser.VisibilityPrivate
+		case strings.HasPrefix(word, "pub("):
+			return parser.VisibilityPackage
+		}
+	}
+	return ""
 }
 
 // rustCallQueryPattern is the Tree-sitter query for extracting Rust function calls.
@@ -2,8 +2,12 @@
 package languages
 
 import (
-	tree_sitter_scala "github.com/indigo-net/Brf.it/pkg/parser/treesitter/grammars/scala"
+	"strings"
+
 	sitter "github.com/tree-sitter/go-tree-sitter"
+
+	"github.com/indigo-net/Brf.it/pkg/parser"
+	tree_sitter_scala "github.com/indigo-net/Brf.it/pkg/parser/treesitter/grammars/scala"
 )
 
 // ScalaQuery implements LanguageQuery for Scala language.
@@ -56,12 +60,21 @@ func (q *ScalaQuery) ImportQuery() []byte {
 	return []byte(scalaImportQueryPattern)
 }
 
-// IsExported returns true if the Scala signature does not start with a private modifier.
-func (q *ScalaQuery) IsExported(name, sigText string) bool {
-	if len(name) == 0 {
-		return false
+// Visibility returns the access modifier of a Scala declaration, or "" when
+// it has none (public by default). A qualified "private[pkg]" is package
+// visibility, while "private[this]" stays private.
+func (q *ScalaQuery) Visibility(_, head string) parser.Visibility {
+	for _, word := range strings.Fields(head) {
+		switch {
+		case word == "private" || word == "private[this]":
+			return parser.VisibilityPrivate
+		case strings.HasPrefix(word, "private["):
+			return parser.VisibilityPackage
+		case strings.HasPrefix(word, "protected"):
+			return parser.VisibilityProtected
+		}
 	}
-	return !hasVisibilityPrefix(sigText, "private")
+	return ""
 }
 
 // scalaImportQueryPattern is the Tree-sitter query for extracting Scala import statements.
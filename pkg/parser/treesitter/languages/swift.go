@@ -2,8 +2,10 @@
 package languages
 
 import (
-	tree_sitter_swift "github.com/indigo-net/Brf.it/pkg/parser/treesitter/grammars/swift"
 	sitter "github.com/tree-sitter/go-tree-sitter"
+
+	"github.com/indigo-net/Brf.it/pkg/parser"
+	tree_sitter_swift "github.com/indigo-net/Brf.it/pkg/parser/treesitter/grammars/swift"
 )
 
 // SwiftQuery implements LanguageQuery for Swift language.
@@ -54,12 +56,21 @@ func (q *SwiftQuery) ImportQuery() []byte {
 	return []byte(swiftImportQueryPattern)
 }
 
-// IsExported returns true if the Swift signature does not start with a private modifier.
-func (q *SwiftQuery) IsExported(name, sigText string) bool {
-	if len(name) == 0 {
-		return false
+// Visibility returns the access level of a Swift declaration ("open" counts
+// as public, "fileprivate" as file), or "" when it has none (internal by
+// default).
+func (q *SwiftQuery) Visibility(_, head string) parser.Visibility {
+	switch {
+	case hasModifier(head, "open"), hasModifier(head, "public"):
+		return parser.VisibilityPublic
+	case hasModifier(head, "internal"):
+		return parser.VisibilityInternal
+	case hasModifier(head, "fileprivate"):
+		return parser.VisibilityFile
+	case hasModifier(head, "private"):
+		return parser.VisibilityPrivate
 	}
-	return !hasVisibilityPrefix(sigText, "private") && !hasVisibilityPrefix(sigText, "fileprivate")
+	return ""
 }
 
 // swiftImportQueryPattern is the Tree-sitter query for extracting Swift import statements.
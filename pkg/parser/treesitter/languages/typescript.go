@@ -1,8 +1,12 @@
 package languages
 
 import (
+	"strings"
+
 	sitter "github.com/tree-sitter/go-tree-sitter"
 	tree_sitter_typescript "github.com/tree-sitter/tree-sitter-typescript/bindings/go"
+
+	"github.com/indigo-net/Brf.it/pkg/parser"
 )
 
 // TypeScriptQuery implements LanguageQuery for TypeScript language.
@@ -20,6 +24,34 @@ func NewTypeScriptQuery() *TypeScriptQuery {
 	}
 }
 
+// NewTSXQuery creates a TypeScript language query for TSX, the TypeScript
+// grammar extended with JSX. It parses .tsx and .jsx files, whose JSX
+// elements are errors in the plain TypeScript grammar.
+func NewTSXQuery() *TypeScriptQuery {
+	return &TypeScriptQuery{
+		language: sitter.NewLanguage(tree_sitter_typescript.LanguageTSX()),
+		query:    []byte(typeScriptQueryPattern),
+	}
+}
+
+// Visibility returns public for exported and ambient ("declare")
+// declarations, the accessibility modifier of class members, and private
+// for "#name" members. It returns "" otherwise: the parser also checks for
+// an enclosing export statement.
+func (q *TypeScriptQuery) Visibility(name, head string) parser.Visibility {
+	switch {
+	case hasModifier(head, "export"), hasModifier(head, "declare"):
+		return parser.VisibilityPublic
+	case hasModifier(head, "private"), strings.HasPrefix(name, "#"):
+		return parser.VisibilityPrivate
+	case hasModifier(head, "protected"):
+		return parser.VisibilityProtected
+	case hasModifier(head, "public"):
+		return parser.VisibilityPublic
+	}
+	return ""
+}
+
 // Language returns the TypeScript Tree-sitter language.
 func (q *TypeScriptQuery) Language() *sitter.Language {
 	return q.language
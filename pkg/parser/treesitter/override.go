@@ -0,0 +1,55 @@
+package treesitter
+
+import (
+	"github.com/indigo-net/Brf.it/pkg/parser"
+)
+
+// overriddenQuery is a LanguageQuery whose queries are replaced or extended
+// by user-supplied ones (see parser.LoadQueries).
+type overriddenQuery struct {
+	LanguageQuery
+	queries *parser.Queries
+}
+
+// Query returns the signature query, overridden by queries.Signature.
+func (q *overriddenQuery) Query() []byte {
+	return mergeQuery(q.LanguageQuery.Query(), q.queries.Signature)
+}
+
+// ImportQuery returns the import query, overridden by queries.Import.
+func (q *overriddenQuery) ImportQuery() []byte {
+	return mergeQuery(q.LanguageQuery.ImportQuery(), q.queries.Import)
+}
+
+// CallQuery returns the call query, overridden by queries.Call.
+func (q *overriddenQuery) CallQuery() []byte {
+	return mergeQuery(q.LanguageQuery.CallQuery(), q.queries.Call)
+}
+
+// override returns the user-supplied query of type typ, or nil.
+func (q *overriddenQuery) override(typ queryType) *parser.QueryOverride {
+	switch typ {
+	case queryTypeSignature:
+		return q.queries.Signature
+	case queryTypeImport:
+		return q.queries.Import
+	case queryTypeCall:
+		return q.queries.Call
+	}
+	return nil
+}
+
+// mergeQuery returns the built-in query replaced by o, or with the patterns
+// of o appended when o extends it.
+func mergeQuery(builtin []byte, o *parser.QueryOverride) []byte {
+	if o == nil {
+		return builtin
+	}
+	if !o.Extend || len(builtin) == 0 {
+		return []byte(o.Source)
+	}
+	merged := make([]byte, 0, len(builtin)+1+len(o.Source))
+	merged = append(merged, builtin...)
+	merged = append(merged, '\n')
+	return append(merged, o.Source...)
+}
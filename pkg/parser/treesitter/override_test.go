@@ -0,0 +1,111 @@
+package treesitter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/indigo-net/Brf.it/pkg/parser"
+)
+
+func TestQueryOverrides(t *testing.T) {
+	src := []byte(`package a
+
+type T struct {
+	X int
+}
+
+func A() {}
+`)
+	tests := []struct {
+		name      string
+		queries   *parser.Queries
+		wantNames []string
+	}{
+		{
+			name:      "built-in",
+			wantNames: []string{"T", "A"},
+		},
+		{
+			name: "replaced",
+			queries: &parser.Queries{Signature: &parser.QueryOverride{
+				Path:   "go.scm",
+				Source: "(function_declaration name: (identifier) @name) @signature @kind",
+			}},
+			wantNames: []string{"A"},
+		},
+		{
+			name: "extended",
+			queries: &parser.Queries{Signature: &parser.QueryOverride{
+				Path:   "go.scm",
+				Source: "; extends\n(field_declaration name: (field_identifier) @name) @signature @kind",
+				Extend: true,
+			}},
+			wantNames: []string{"T", "X", "A"},
+		},
+	}
+
+	p := NewTreeSitterParser()
+	defer p.Close()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := p.Parse(src, &parser.Options{Language: "go", IncludePrivate: true, Queries: tt.queries})
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			var names []string
+			for _, sig := range result.Signatures {
+				names = append(names, sig.Name)
+			}
+			if strings.Join(names, ",") != strings.Join(tt.wantNames, ",") {
+				t.Errorf("expected signatures %v, got %v", tt.wantNames, names)
+			}
+		})
+	}
+
+	// An invalid query names its file
+	_, err := p.Parse(src, &parser.Options{Language: "go", Queries: &parser.Queries{
+		Signature: &parser.QueryOverride{Path: ".brfit/queries/go.scm", Source: "(no_such_node) @name"},
+	}})
+	if err == nil || !strings.Contains(err.Error(), ".brfit/queries/go.scm") {
+		t.Errorf("expected error naming the query file, got %v", err)
+	}
+}
+
+func TestQueryOverrideCacheReplacesEditedFile(t *testing.T) {
+	src := []byte("package a\n\nfunc A() {}\n\nfunc B() {}\n")
+	p := NewTreeSitterParser()
+	defer p.Close()
+
+	// Each edit of the query file replaces its cache entry
+	for i, query := range []string{
+		"(function_declaration name: (identifier) @name) @signature @kind",
+		"((function_declaration name: (identifier) @name) @signature @kind (#eq? @name \"A\"))",
+		"((function_declaration name: (identifier) @name) @signature @kind (#eq? @name \"B\"))",
+	} {
+		queries := &parser.Queries{Signature: &parser.QueryOverride{Path: ".brfit/queries/go.scm", Source: query}}
+		if _, err := p.Parse(src, &parser.Options{Language: "go", Queries: queries}); err != nil {
+			t.Fatalf("edit %d: Parse: %v", i, err)
+		}
+	}
+
+	entries := 0
+	p.compiledQueries.Range(func(key, _ any) bool {
+		if k := key.(queryCacheKey); k.typ == queryTypeSignature && k.override != "" {
+			entries++
+		}
+		return true
+	})
+	if entries != 1 {
+		t.Errorf("expected 1 cached override query, got %d", entries)
+	}
+
+	// Replaced queries are closed by the next Parse
+	if _, err := p.Parse(src, &parser.Options{Language: "go"}); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	p.retiredMu.Lock()
+	defer p.retiredMu.Unlock()
+	if len(p.retired) != 0 {
+		t.Errorf("expected retired queries to be closed, %d left", len(p.retired))
+	}
+}
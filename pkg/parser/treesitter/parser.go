@@ -3,6 +3,7 @@ package treesitter
 import (
 	"bytes"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 
@@ -40,22 +41,42 @@ var elixirDefPrefixes = [][]byte{
 }
 
 // supportedLangs is the list of languages with Tree-sitter parsers available.
-const supportedLangs = "go, typescript, tsx, javascript, jsx, python, c, java, cpp, rust, swift, kotlin, csharp, lua, shell, php, ruby, scala, elixir, sql, yaml, toml"
+const supportedLangs = "go, typescript, tsx, javascript, jsx, python, c, java, cpp, rust, swift, kotlin, csharp, lua, shell, php, ruby, scala, elixir, sql, yaml, toml, vue, svelte, astro, html"
 
 // queryCacheKey combines language and query type for cache lookup.
+// Queries overridden by the user are also keyed by the path of their query
+// file, so each file has a single entry however often it is edited.
 type queryCacheKey struct {
-	lang string
-	typ  queryType
+	lang     string
+	typ      queryType
+	override string
+}
+
+// cachedQuery is a compiled query with the override it was compiled from,
+// if any. An entry whose override source differs from the current one is
+// stale and gets replaced.
+type cachedQuery struct {
+	query  *sitter.Query
+	source string
+	extend bool
+}
+
+// matches reports whether c was compiled from the given override source.
+func (c *cachedQuery) matches(source string, extend bool) bool {
+	return c.source == source && c.extend == extend
 }
 
 // TreeSitterParser implements parser.Parser using Tree-sitter.
 type TreeSitterParser struct {
 	queries         map[string]LanguageQuery
-	compiledQueries sync.Map // map[queryCacheKey]*sitter.Query
+	compiledQueries sync.Map // map[queryCacheKey]*cachedQuery
 	parserPool      sync.Pool
 	cursorPool      sync.Pool
 	mu              sync.RWMutex // guards query lifetime around Close
 	closed          bool
+
+	retiredMu sync.Mutex
+	retired   []*sitter.Query // replaced queries that a Parse may still use
 }
 
 // pooledParser wraps a sitter.Parser with the last language set,
@@ -63,6 +84,15 @@ type TreeSitterParser struct {
 type pooledParser struct {
 	parser   *sitter.Parser
 	lastLang *sitter.Language
+
+	// done is the Done channel of the context of the current parse, nil
+	// when it cannot be interrupted.
+	done <-chan struct{}
+
+	// progress holds the progress callback checking done. It is created
+	// once per parser, as go-tree-sitter retains every ParseOptions it is
+	// given.
+	progress *sitter.ParseOptions
 }
 
 // NewTreeSitterParser creates a new Tree-sitter based parser.
@@ -71,9 +101,9 @@ func NewTreeSitterParser() *TreeSitterParser {
 		queries: map[string]LanguageQuery{
 			"go":         languages.NewGoQuery(),
 			"typescript": languages.NewTypeScriptQuery(),
-			"tsx":        languages.NewTypeScriptQuery(), // TSX uses TypeScript grammar
+			"tsx":        languages.NewTSXQuery(),
 			"javascript": languages.NewTypeScriptQuery(), // JS uses TypeScript grammar (subset)
-			"jsx":        languages.NewTypeScriptQuery(), // JSX uses TypeScript grammar
+			"jsx":        languages.NewTSXQuery(),        // JSX uses the TSX grammar (superset)
 			"python":     languages.NewPythonQuery(),
 			"c":          languages.NewCQuery(),
 			"java":       languages.NewJavaQuery(),
@@ -91,11 +121,16 @@ func NewTreeSitterParser() *TreeSitterParser {
 			"sql":        languages.NewSQLQuery(),
 			"yaml":       languages.NewYAMLQuery(),
 			"toml":       languages.NewTOMLQuery(),
+			// Script blocks of markup files (see scriptHosts)
+			"vue":    languages.NewTypeScriptQuery(),
+			"svelte": languages.NewTypeScriptQuery(),
+			"astro":  languages.NewTypeScriptQuery(),
+			"html":   languages.NewTypeScriptQuery(),
 		},
 	}
 	p.parserPool = sync.Pool{
 		New: func() any {
-			return &pooledParser{parser: sitter.NewParser()}
+			return newPooledParser()
 		},
 	}
 	p.cursorPool = sync.Pool{
@@ -116,12 +151,45 @@ func (p *TreeSitterParser) Close() {
 	defer p.mu.Unlock()
 	p.closed = true
 	p.compiledQueries.Range(func(key, value any) bool {
-		if q, ok := value.(*sitter.Query); ok {
-			q.Close()
+		if c, ok := value.(*cachedQuery); ok {
+			c.query.Close()
 		}
 		p.compiledQueries.Delete(key)
 		return true
 	})
+	p.retiredMu.Lock()
+	defer p.retiredMu.Unlock()
+	for _, q := range p.retired {
+		q.Close()
+	}
+	p.retired = nil
+}
+
+// retireQuery schedules q, replaced in the cache, to be closed once no
+// Parse can be using it.
+func (p *TreeSitterParser) retireQuery(q *sitter.Query) {
+	p.retiredMu.Lock()
+	defer p.retiredMu.Unlock()
+	p.retired = append(p.retired, q)
+}
+
+// closeRetiredQueries closes the queries replaced in the cache. It waits for
+// the Parse calls in progress, which may still use them, so callers must not
+// hold p.mu.
+func (p *TreeSitterParser) closeRetiredQueries() {
+	p.retiredMu.Lock()
+	retired := p.retired
+	p.retired = nil
+	p.retiredMu.Unlock()
+	if len(retired) == 0 {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, q := range retired {
+		q.Close()
+	}
 }
 
 // getOrCreateQuery returns a cached query or creates and caches a new one.
@@ -129,10 +197,19 @@ func (p *TreeSitterParser) Close() {
 // Callers must hold p.mu.RLock() to ensure queries are not freed by Close().
 func (p *TreeSitterParser) getOrCreateQuery(lang string, langQuery LanguageQuery, typ queryType) (*sitter.Query, error) {
 	key := queryCacheKey{lang: lang, typ: typ}
+	var override *parser.QueryOverride
+	var source string
+	var extend bool
+	if q, ok := langQuery.(*overriddenQuery); ok {
+		if override = q.override(typ); override != nil {
+			key.override = override.Path
+			source, extend = override.Source, override.Extend
+		}
+	}
 
 	// Fast path: check cache (sync.Map is inherently thread-safe)
-	if cached, ok := p.compiledQueries.Load(key); ok {
-		return cached.(*sitter.Query), nil
+	if cached, ok := p.compiledQueries.Load(key); ok && cached.(*cachedQuery).matches(source, extend) {
+		return cached.(*cachedQuery).query, nil
 	}
 
 	// Slow path: create query and attempt to store it
@@ -149,22 +226,38 @@ func (p *TreeSitterParser) getOrCreateQuery(lang string, langQuery LanguageQuery
 
 	query, err := sitter.NewQuery(langQuery.Language(), queryStr)
 	if err != nil {
+		if override != nil {
+			return nil, fmt.Errorf("%s: %w", override.Path, err)
+		}
 		return nil, err
 	}
 
 	// LoadOrStore ensures only one query per key is retained.
 	// If another goroutine won the race, close our duplicate and use theirs.
-	actual, loaded := p.compiledQueries.LoadOrStore(key, query)
-	if loaded {
-		query.Close()
-		return actual.(*sitter.Query), nil
+	// An entry compiled from an older version of the query file is replaced,
+	// and closed once the Parse calls that may use it are done.
+	entry := &cachedQuery{query: query, source: source, extend: extend}
+	for {
+		actual, loaded := p.compiledQueries.LoadOrStore(key, entry)
+		if !loaded {
+			return query, nil
+		}
+		cached := actual.(*cachedQuery)
+		if cached.matches(source, extend) {
+			query.Close()
+			return cached.query, nil
+		}
+		if p.compiledQueries.CompareAndSwap(key, cached, entry) {
+			p.retireQuery(cached.query)
+			return query, nil
+		}
 	}
-	return query, nil
 }
 
 // Parse parses the given content and returns extracted signatures.
 // Parse holds a read lock to ensure Close() cannot free queries mid-parse.
 func (p *TreeSitterParser) Parse(content []byte, opts *parser.Options) (result *parser.ParseResult, err error) {
+	p.closeRetiredQueries()
 	p.mu.RLock()
 	defer p.mu.RUnlock()
 
@@ -182,6 +275,10 @@ func (p *TreeSitterParser) Parse(content []byte, opts *parser.Options) (result *
 	if opts == nil {
 		opts = &parser.Options{}
 	}
+	ctx := parseContext(opts)
+	if err := ctx.Err(); err != nil {
+		return nil, interrupted(err)
+	}
 
 	// Determine language
 	lang := opts.Language
@@ -195,6 +292,20 @@ func (p *TreeSitterParser) Parse(content []byte, opts *parser.Options) (result *
 		return nil, fmt.Errorf("unsupported language %q. Available parsers: %s", lang, supportedLangs)
 	}
 
+	// Markup files are parsed as the code of their script blocks
+	host := ""
+	if scriptHosts[lang] {
+		host = lang
+		content, lang = scriptSource(host, content)
+		scriptOpts := *opts
+		scriptOpts.Language = lang
+		opts = &scriptOpts
+		query = p.queries[lang]
+	}
+	if opts.Queries != nil {
+		query = &overriddenQuery{LanguageQuery: query, queries: opts.Queries}
+	}
+
 	// Get parser from pool
 	pp := p.parserPool.Get().(*pooledParser)
 	defer p.parserPool.Put(pp)
@@ -209,7 +320,10 @@ func (p *TreeSitterParser) Parse(content []byte, opts *parser.Options) (result *
 	}
 
 	// Parse content (no conversion needed - already []byte)
-	tree := pp.parser.Parse(content, nil)
+	tree, err := pp.parse(ctx, content)
+	if err != nil {
+		return nil, err
+	}
 	if tree == nil {
 		return nil, fmt.Errorf("failed to parse content for language %q (content may be malformed or parser error occurred)", lang)
 	}
@@ -220,6 +334,12 @@ func (p *TreeSitterParser) Parse(content []byte, opts *parser.Options) (result *
 	if err != nil {
 		return nil, fmt.Errorf("signature extraction failed: %w", err)
 	}
+	if host != "" {
+		signatures = componentAPI(host, signatures, tree.RootNode(), content)
+		for i := range signatures {
+			signatures[i].Language = host
+		}
+	}
 
 	// Extract imports if requested
 	var rawImports []string
@@ -239,22 +359,33 @@ func (p *TreeSitterParser) Parse(content []byte, opts *parser.Options) (result *
 		}
 	}
 
-	// Filter private symbols after call extraction (calls need all signatures for caller attribution)
-	if !opts.IncludePrivate {
+	// Filter by visibility and deprecation after call extraction (calls
+	// need all signatures for caller attribution)
+	if !opts.IncludePrivate || opts.ExcludeDeprecated {
 		filtered := signatures[:0]
 		for _, sig := range signatures {
-			if sig.Exported {
-				filtered = append(filtered, sig)
+			if !opts.IncludePrivate && !parser.IncludesVisibility(opts.Language, opts.Visibility, sig.Visibility) {
+				continue
 			}
+			if opts.ExcludeDeprecated && sig.Deprecated {
+				continue
+			}
+			filtered = append(filtered, sig)
 		}
 		signatures = filtered
 	}
 
+	resultLang := lang
+	if host != "" {
+		resultLang = host
+	}
 	return &parser.ParseResult{
-		Language:   lang,
-		Signatures: signatures,
-		RawImports: rawImports,
-		Calls:      calls,
+		Language:    resultLang,
+		Signatures:  signatures,
+		RawImports:  rawImports,
+		Calls:       calls,
+		Summary:     fileSummary(lang, tree.RootNode(), content),
+		Diagnostics: syntaxDiagnostics(tree.RootNode()),
 	}, nil
 }
 
@@ -287,6 +418,7 @@ func (p *TreeSitterParser) extractSignatures(
 	qc := p.cursorPool.Get().(*sitter.QueryCursor)
 	defer p.cursorPool.Put(qc)
 
+	ctx := parseContext(opts)
 	matches := qc.Matches(query, root, content)
 
 	// Process matches
@@ -304,7 +436,18 @@ func (p *TreeSitterParser) extractSignatures(
 	}
 	seen := make(map[dedupKey]bool)
 
+	// Non-exported declarations of ES modules are file-local
+	module := moduleExports(root, content)
+
+	// Byte ranges of the signature nodes, parallel to signatures, used to
+	// nest members under their enclosing declarations.
+	spans := make([]byteSpan, 0, 32)
+
 	for {
+		// The query runs as matches are read: stop between matches
+		if err := ctx.Err(); err != nil {
+			return nil, interrupted(err)
+		}
 		match := matches.Next()
 		if match == nil {
 			break
@@ -312,7 +455,8 @@ func (p *TreeSitterParser) extractSignatures(
 
 		sig := parser.Signature{}
 		sigColumn := 0
-		var kindNode *sitter.Node
+		var span byteSpan
+		var kindNode, nameNode, sigNode *sitter.Node
 
 		for _, capture := range match.Captures {
 			if int(capture.Index) >= len(captureNames) {
@@ -335,11 +479,17 @@ func (p *TreeSitterParser) extractSignatures(
 			switch name {
 			case CaptureName:
 				sig.Name = string(raw)
+				nameNode = &node
 			case CaptureSignature:
 				sig.Text = string(bytes.TrimSpace(raw))
+				sigNode = &node
 				sig.Line = int(node.StartPosition().Row) + 1
 				sigColumn = int(node.StartPosition().Column)
 				sig.EndLine = int(node.EndPosition().Row) + 1
+				sig.Column = sigColumn + 1
+				sig.EndColumn = int(node.EndPosition().Column) + 1
+				sig.StartByte, sig.EndByte = int(start), int(end)
+				span = byteSpan{start, end}
 			case CaptureDoc:
 				if len(raw) > 0 {
 					sig.Doc = cleanComment(string(raw))
@@ -492,55 +642,144 @@ func (p *TreeSitterParser) extractSignatures(
 			}
 			seen[dk] = true
 
-			sig.Exported = langQuery.IsExported(sig.Name, sig.Text)
+			if sigNode != nil {
+				textStart := sigNode.StartByte()
+				if nameNode != nil {
+					var prefixEnd uint
+					sig.Decorators, prefixEnd = decorators(opts.Language, sigNode, nameNode, content)
+					// Leading annotations inside the declaration (Java, PHP, C#)
+					// are reported as decorators, not as part of the text
+					if prefixEnd > 0 {
+						sig.Text = string(bytes.TrimSpace(content[prefixEnd:sigNode.EndByte()]))
+						textStart = prefixEnd
+					}
+				}
+				head := declarationHead(sig.Text, nameNode, textStart, content)
+				sig.Visibility = visibility(opts.Language, langQuery, &sig, sigNode, head, module, content)
+				if sig.Doc == "" {
+					sig.Doc = docComment(opts.Language, sigNode, content)
+				}
+				sig.Deprecated, sig.DeprecationNote = deprecation(opts.Language, &sig, sigNode, content)
+			}
+
+			if nameNode != nil && sigNode != nil {
+				decompose(&sig, opts.Language, sigNode, nameNode, content)
+			}
 
 			// Strip body if IncludeBody is false (default)
 			if !opts.IncludeBody {
 				sig.Text = stripBody(sig.Text, sig.Kind, opts.Language)
 			}
 
+			if nameNode != nil && sigNode != nil {
+				reactComponent(opts.Language, &sig, sigNode, nameNode, content, opts.IncludeBody)
+			}
+
 			sig.Language = opts.Language
 			signatures = append(signatures, sig)
+			spans = append(spans, span)
 		}
 	}
 
+	parents := assignParents(signatures, spans)
+	clampVisibility(signatures, parents)
+	inheritDeprecation(signatures, parents)
+	qualifyNames(signatures, parents, spans, fileScopes(opts.Language, root, content), opts)
 	return signatures, nil
 }
 
-// cleanComment removes comment markers from the text.
-func cleanComment(text string) string {
-	// LuaDoc (--- prefix) — check before -- to avoid partial match
-	if strings.HasPrefix(text, "---") {
-		return strings.TrimSpace(strings.TrimPrefix(text, "---"))
+// byteSpan is the [start, end) byte range of a signature node.
+type byteSpan struct {
+	start, end uint
+}
+
+// contains reports whether s strictly encloses o.
+func (s byteSpan) contains(o byteSpan) bool {
+	return s.start <= o.start && o.end <= s.end && (s.end-s.start) > (o.end-o.start)
+}
+
+// assignParents sets Signature.Parent from the nesting of the signature
+// nodes: a signature's parent is the narrowest other signature whose node
+// encloses it (e.g., the class around a method). It returns the index of
+// each signature's parent, or -1 for top-level signatures.
+func assignParents(signatures []parser.Signature, spans []byteSpan) []int {
+	parents := make([]int, len(signatures))
+	order := make([]int, len(signatures))
+	for i := range order {
+		order[i] = i
 	}
+	// Outer nodes first: by start ascending, then by end descending
+	sort.SliceStable(order, func(a, b int) bool {
+		sa, sb := spans[order[a]], spans[order[b]]
+		if sa.start != sb.start {
+			return sa.start < sb.start
+		}
+		return sa.end > sb.end
+	})
+
+	var stack []int
+	for _, i := range order {
+		for len(stack) > 0 && !spans[stack[len(stack)-1]].contains(spans[i]) {
+			stack = stack[:len(stack)-1]
+		}
+		parents[i] = -1
+		if len(stack) > 0 {
+			top := stack[len(stack)-1]
+			outer := &signatures[top]
+			if outer.Name == signatures[i].Name && outer.Line == signatures[i].Line {
+				// Wrapper of the same declaration (e.g., a TypeScript export
+				// statement around a function), not a container
+				signatures[i].Parent = outer.Parent
+				parents[i] = parents[top]
+			} else {
+				signatures[i].Parent = outer.Path()
+				parents[i] = top
+			}
+		}
+		stack = append(stack, i)
+	}
+	return parents
+}
+
+// cleanComment removes comment markers from the text: line comment
+// prefixes ("//", "///", "//!", "#", "--", "---") and block comment
+// delimiters ("/* */", "/** */", "--[[ ]]"), including the leading "*" of
+// each line of a block comment.
+func cleanComment(text string) string {
+	text = strings.TrimSpace(text)
+
 	// Lua block comment --[[ ... ]]
 	if strings.HasPrefix(text, "--[[") {
 		inner := strings.TrimPrefix(text, "--[[")
 		inner = strings.TrimSuffix(inner, "]]")
-		return strings.TrimSpace(inner)
-	}
-	// Lua single-line (-- prefix)
-	if strings.HasPrefix(text, "--") {
-		return strings.TrimSpace(strings.TrimPrefix(text, "--"))
-	}
-
-	// Elixir single-line (# prefix)
-	if strings.HasPrefix(text, "#") {
-		return strings.TrimSpace(strings.TrimPrefix(text, "#"))
-	}
-
-	// Remove // prefix for single-line comments
-	if strings.HasPrefix(text, "//") {
-		return strings.TrimSpace(strings.TrimPrefix(text, "//"))
+		return dedent(inner)
 	}
 
-	// Remove /* */ for multi-line comments
+	// Block comments /* */, /** */ and /*! */
 	if strings.HasPrefix(text, "/*") && strings.HasSuffix(text, "*/") {
-		inner := strings.TrimPrefix(text, "/*")
-		inner = strings.TrimSuffix(inner, "*/")
-		return strings.TrimSpace(inner)
+		inner := strings.TrimSuffix(strings.TrimPrefix(text, "/*"), "*/")
+		inner = strings.TrimLeft(inner, "*!")
+		lines := strings.Split(inner, "\n")
+		for i, line := range lines {
+			line = strings.TrimSpace(line)
+			if i > 0 && strings.HasPrefix(line, "*") {
+				line = strings.TrimPrefix(strings.TrimPrefix(line, "*"), " ")
+			}
+			lines[i] = line
+		}
+		return strings.Trim(strings.Join(lines, "\n"), "\n ")
 	}
 
+	// Line comments: LuaDoc (---) before Lua (--), doc (/// and //!) before //
+	for _, prefix := range []string{"---", "--", "///", "//!", "//", "#"} {
+		if strings.HasPrefix(text, prefix) {
+			text = strings.TrimPrefix(text, prefix)
+			if prefix == "#" {
+				text = strings.TrimLeft(text, "#")
+			}
+			break
+		}
+	}
 	return strings.TrimSpace(text)
 }
 
@@ -1665,6 +1904,7 @@ func (p *TreeSitterParser) extractImports(
 	qc := p.cursorPool.Get().(*sitter.QueryCursor)
 	defer p.cursorPool.Put(qc)
 
+	ctx := parseContext(opts)
 	matches := qc.Matches(query, root, content)
 	captureNames := query.CaptureNames()
 
@@ -1672,6 +1912,9 @@ func (p *TreeSitterParser) extractImports(
 	seenPositions := make(map[uint]bool)
 
 	for {
+		if err := ctx.Err(); err != nil {
+			return nil, interrupted(err)
+		}
 		match := matches.Next()
 		if match == nil {
 			break
@@ -1775,10 +2018,14 @@ func (p *TreeSitterParser) extractCalls(
 	qc := p.cursorPool.Get().(*sitter.QueryCursor)
 	defer p.cursorPool.Put(qc)
 
+	ctx := parseContext(opts)
 	matches := qc.Matches(query, root, content)
 	captureNames := query.CaptureNames()
 
 	for {
+		if err := ctx.Err(); err != nil {
+			return nil, interrupted(err)
+		}
 		match := matches.Next()
 		if match == nil {
 			break
@@ -1809,13 +2056,15 @@ func (p *TreeSitterParser) extractCalls(
 		}
 
 		// Find enclosing function
-		caller := findEnclosingFunction(signatures, callLine)
-
-		calls = append(calls, parser.FunctionCall{
-			Caller: caller,
+		call := parser.FunctionCall{
 			Callee: callee,
 			Line:   callLine,
-		})
+		}
+		if i := findEnclosingSignature(signatures, callLine); i >= 0 {
+			call.Caller = signatures[i].Name
+			call.QualifiedCaller = signatures[i].QualifiedName
+		}
+		calls = append(calls, call)
 	}
 
 	return calls, nil
@@ -1826,10 +2075,19 @@ func (p *TreeSitterParser) extractCalls(
 // overlap (e.g., a class containing a method), the narrowest range wins.
 // Returns empty string if the call is at top-level (not inside any function).
 func findEnclosingFunction(signatures []parser.Signature, line int) string {
-	bestName := ""
+	if i := findEnclosingSignature(signatures, line); i >= 0 {
+		return signatures[i].Name
+	}
+	return ""
+}
+
+// findEnclosingSignature is like findEnclosingFunction but returns the index
+// of the signature, or -1 at top-level.
+func findEnclosingSignature(signatures []parser.Signature, line int) int {
+	best := -1
 	bestSpan := int(^uint(0) >> 1) // max int
 
-	for _, sig := range signatures {
+	for i, sig := range signatures {
 		// Skip signatures with invalid EndLine (e.g., parse errors where
 		// EndLine is 0); they cannot reliably enclose any line.
 		if sig.EndLine == 0 {
@@ -1839,11 +2097,11 @@ func findEnclosingFunction(signatures []parser.Signature, line int) string {
 			span := sig.EndLine - sig.Line
 			if span < bestSpan {
 				bestSpan = span
-				bestName = sig.Name
+				best = i
 			}
 		}
 	}
-	return bestName
+	return best
 }
 
 // removeBlankLines removes empty lines from the import text.
@@ -727,7 +727,7 @@ export function add(a: number, b: number): number {
 }
 `
 
-	result, err := p.Parse([]byte(code), &parser.Options{Language: "typescript"})
+	result, err := p.Parse([]byte(code), &parser.Options{Language: "typescript", IncludePrivate: true})
 	if err != nil {
 		t.Fatalf("Parse returned error: %v", err)
 	}
@@ -1096,7 +1096,7 @@ static mut MUTABLE_STATIC: i32 = 0;
 pub fn main() {}
 `
 
-	result, err := p.Parse([]byte(code), &parser.Options{Language: "rust"})
+	result, err := p.Parse([]byte(code), &parser.Options{Language: "rust", IncludePrivate: true})
 	if err != nil {
 		t.Fatalf("Parse returned error: %v", err)
 	}
@@ -1129,7 +1129,7 @@ func TestRustMacroExtraction(t *testing.T) {
 pub fn main() {}
 `
 
-	result, err := p.Parse([]byte(code), &parser.Options{Language: "rust"})
+	result, err := p.Parse([]byte(code), &parser.Options{Language: "rust", IncludePrivate: true})
 	if err != nil {
 		t.Fatalf("Parse returned error: %v", err)
 	}
@@ -2027,7 +2027,7 @@ local callback = function(err, result)
 end
 `
 
-	result, err := p.Parse([]byte(code), &parser.Options{Language: "lua"})
+	result, err := p.Parse([]byte(code), &parser.Options{Language: "lua", IncludePrivate: true})
 	if err != nil {
 		t.Fatalf("failed to parse Lua: %v", err)
 	}
@@ -3333,3 +3333,118 @@ func TestCloseConcurrentWithParse(t *testing.T) {
 	p.Close()
 	wg.Wait()
 }
+
+func TestSignatureParents(t *testing.T) {
+	tests := []struct {
+		name string
+		lang string
+		src  string
+		want map[string]string // name -> Parent
+	}{
+		{
+			name: "java nested classes",
+			lang: "java",
+			src: `public class Outer {
+    public void a() {}
+    public static class Inner {
+        public void b() {}
+    }
+}
+`,
+			want: map[string]string{"Outer": "", "a": "Outer", "Inner": "Outer", "b": "Outer.Inner"},
+		},
+		{
+			name: "python nested functions",
+			lang: "python",
+			src: `class A:
+    def m(self):
+        def inner():
+            pass
+
+def f():
+    pass
+`,
+			want: map[string]string{"A": "", "m": "A", "inner": "A.m", "f": ""},
+		},
+		{
+			name: "typescript export wrapper is not a container",
+			lang: "typescript",
+			src: `export class Foo {
+  bar(): void {}
+}
+export function top() { function nested() {} }
+`,
+			want: map[string]string{"Foo": "", "bar": "Foo", "top": "", "nested": "top"},
+		},
+		{
+			name: "go methods stay top-level",
+			lang: "go",
+			src: `package a
+
+type S struct{}
+
+func (s *S) M() {}
+`,
+			want: map[string]string{"S": "", "M": ""},
+		},
+	}
+
+	p := NewTreeSitterParser()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := p.Parse([]byte(tt.src), &parser.Options{Language: tt.lang, IncludePrivate: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make(map[string]string)
+			for _, sig := range result.Signatures {
+				got[sig.Name] = sig.Parent
+			}
+			for name, parent := range tt.want {
+				if p, ok := got[name]; !ok || p != parent {
+					t.Errorf("%s: parent = %q (found %v), want %q", name, p, ok, parent)
+				}
+			}
+		})
+	}
+}
+
+func TestAssignParentsIdenticalSpans(t *testing.T) {
+	// Go grouped type declarations share one node; neither is the other's parent
+	sigs := []parser.Signature{{Name: "A", Line: 1}, {Name: "B", Line: 1}, {Name: "C", Line: 2}}
+	assignParents(sigs, []byteSpan{{0, 20}, {0, 20}, {5, 10}})
+	if sigs[0].Parent != "" || sigs[1].Parent != "" || sigs[2].Parent != "B" {
+		t.Errorf("unexpected parents: %q, %q, %q", sigs[0].Parent, sigs[1].Parent, sigs[2].Parent)
+	}
+}
+
+func TestSignatureLocations(t *testing.T) {
+	src := "package a\n\n// S is a struct.\ntype S struct {\n\tX int\n}\n\nfunc (s *S) Run() {\n\tprintln()\n}\n"
+	p := NewTreeSitterParser()
+	result, err := p.Parse([]byte(src), &parser.Options{Language: "go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]struct {
+		line, column, endLine, endColumn int
+		prefix                           string
+	}{
+		"S":   {4, 1, 6, 2, "type S struct {"},
+		"Run": {8, 1, 10, 2, "func (s *S) Run() {"},
+	}
+	for _, sig := range result.Signatures {
+		w, ok := want[sig.Name]
+		if !ok {
+			continue
+		}
+		if sig.Line != w.line || sig.Column != w.column || sig.EndLine != w.endLine || sig.EndColumn != w.endColumn {
+			t.Errorf("%s: range %d:%d-%d:%d, want %d:%d-%d:%d", sig.Name,
+				sig.Line, sig.Column, sig.EndLine, sig.EndColumn, w.line, w.column, w.endLine, w.endColumn)
+		}
+		code := src[sig.StartByte:sig.EndByte]
+		if !strings.HasPrefix(code, w.prefix) || !strings.HasSuffix(code, "}") {
+			t.Errorf("%s: bytes %d-%d cover %q", sig.Name, sig.StartByte, sig.EndByte, code)
+		}
+	}
+}
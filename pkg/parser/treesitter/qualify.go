@@ -0,0 +1,153 @@
+package treesitter
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	sitter "github.com/tree-sitter/go-tree-sitter"
+
+	"github.com/indigo-net/Brf.it/pkg/parser"
+)
+
+// scopeDecl is a package or namespace declaration whose name qualifies the
+// signatures starting within span.
+type scopeDecl struct {
+	name string
+	span byteSpan
+}
+
+// scopeNodeKinds maps languages to the top-level node type that declares the
+// file's package or namespace. Namespaces with a body that are extracted as
+// signatures (C# blocks, C++) qualify names through Signature.Parent instead.
+var scopeNodeKinds = map[string]string{
+	"go":     "package_clause",
+	"java":   "package_declaration",
+	"kotlin": "package_header",
+	"scala":  "package_clause",
+	"csharp": "file_scoped_namespace_declaration",
+	"php":    "namespace_definition",
+}
+
+// scopeNamePattern extracts the declared name after the package/namespace keyword.
+var scopeNamePattern = regexp.MustCompile(`^(?:package|namespace)\s+([\w.\\]+)`)
+
+// fileScopes returns the package/namespace declarations at the top level of
+// the file. A declaration without a body applies up to the next declaration
+// or the end of the file; one with a body (PHP, Scala) applies to the body.
+func fileScopes(lang string, root *sitter.Node, content []byte) []scopeDecl {
+	kind, ok := scopeNodeKinds[lang]
+	if !ok {
+		return nil
+	}
+	var scopes []scopeDecl
+	for i := uint(0); i < root.NamedChildCount(); i++ {
+		node := root.NamedChild(i)
+		if node == nil || node.Kind() != kind {
+			continue
+		}
+		start, end := node.StartByte(), node.EndByte()
+		if end > uint(len(content)) || start > end {
+			continue
+		}
+		text := content[start:end]
+		m := scopeNamePattern.FindSubmatch(text)
+		if m == nil {
+			continue
+		}
+		// The previous body-less declaration ends where this one starts
+		if n := len(scopes); n > 0 && scopes[n-1].span.end == uint(len(content)) {
+			scopes[n-1].span.end = start
+		}
+		if !bytes.Contains(text, []byte("{")) {
+			end = uint(len(content))
+		}
+		scopes = append(scopes, scopeDecl{name: string(m[1]), span: byteSpan{start, end}})
+	}
+	return scopes
+}
+
+// nameSeparators returns the separator between the file scope and the first
+// name, and between nested names, in the language's own notation.
+func nameSeparators(lang string) (scopeSep, sep string) {
+	switch lang {
+	case "cpp", "rust", "ruby":
+		return "::", "::"
+	case "php":
+		return `\`, "::"
+	default:
+		return ".", "."
+	}
+}
+
+// qualifyNames sets Signature.QualifiedName from the file scope (package,
+// namespace or Options.Module), the enclosing signatures and, for Go methods,
+// the receiver type.
+func qualifyNames(signatures []parser.Signature, parents []int, spans []byteSpan, scopes []scopeDecl, opts *parser.Options) {
+	scopeSep, sep := nameSeparators(opts.Language)
+
+	var qualify func(i int) string
+	qualify = func(i int) string {
+		sig := &signatures[i]
+		if sig.QualifiedName != "" {
+			return sig.QualifiedName
+		}
+		if p := parents[i]; p >= 0 {
+			sig.QualifiedName = qualify(p) + sep + sig.Name
+			return sig.QualifiedName
+		}
+
+		name := sig.Name
+		if opts.Language == "go" {
+			if recv := goReceiverType(sig.Text); recv != "" {
+				name = recv + sep + name
+			}
+		}
+
+		prefix := opts.Module
+		for _, scope := range scopes {
+			if scope.span.start <= spans[i].start && spans[i].start < scope.span.end {
+				prefix = scope.name
+				if scope.span.start == spans[i].start {
+					prefix = "" // the namespace declaration itself (C#)
+				}
+			}
+		}
+		if prefix != "" {
+			name = prefix + scopeSep + name
+		}
+		sig.QualifiedName = name
+		return name
+	}
+
+	for i := range signatures {
+		qualify(i)
+	}
+}
+
+// goReceiverType returns the receiver type name of a Go method signature
+// (e.g., "FileScanner" for "func (s *FileScanner) Scan()", "List" for
+// "func (l *List[T]) Len() int"), or "" for functions.
+func goReceiverType(text string) string {
+	rest, ok := strings.CutPrefix(text, "func")
+	if !ok {
+		return ""
+	}
+	rest = strings.TrimSpace(rest)
+	if !strings.HasPrefix(rest, "(") {
+		return ""
+	}
+	end := strings.IndexByte(rest, ')')
+	if end < 0 {
+		return ""
+	}
+	recv := rest[1:end]
+	if i := strings.IndexByte(recv, '['); i >= 0 {
+		recv = recv[:i]
+	}
+	fields := strings.Fields(recv)
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.TrimLeft(fields[len(fields)-1], "*")
+}
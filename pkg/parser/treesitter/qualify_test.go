@@ -0,0 +1,210 @@
+package treesitter
+
+import (
+	"testing"
+
+	"github.com/indigo-net/Brf.it/pkg/parser"
+)
+
+func TestQualifiedNames(t *testing.T) {
+	tests := []struct {
+		name   string
+		lang   string
+		module string
+		src    string
+		want   map[string]string // name -> QualifiedName
+	}{
+		{
+			name: "go package and receivers",
+			lang: "go",
+			src: `package scanner
+
+type FileScanner struct{}
+
+func NewFileScanner() *FileScanner { return nil }
+
+func (s *FileScanner) Scan() error { return nil }
+
+func (l List[T]) Len() int { return 0 }
+`,
+			want: map[string]string{
+				"FileScanner":    "scanner.FileScanner",
+				"NewFileScanner": "scanner.NewFileScanner",
+				"Scan":           "scanner.FileScanner.Scan",
+				"Len":            "scanner.List.Len",
+			},
+		},
+		{
+			name: "java package and nested classes",
+			lang: "java",
+			src: `package com.example;
+
+public class Outer {
+    public void a() {}
+    public static class Inner {
+        public void b() {}
+    }
+}
+`,
+			want: map[string]string{
+				"Outer": "com.example.Outer",
+				"a":     "com.example.Outer.a",
+				"b":     "com.example.Outer.Inner.b",
+			},
+		},
+		{
+			name: "php namespace statement",
+			lang: "php",
+			src: `<?php
+namespace App\Models;
+
+class User {
+    public function save() {}
+}
+`,
+			want: map[string]string{"User": `App\Models\User`, "save": `App\Models\User::save`},
+		},
+		{
+			name: "php namespace blocks",
+			lang: "php",
+			src: `<?php
+namespace A {
+    function f() {}
+}
+namespace B {
+    function g() {}
+}
+`,
+			want: map[string]string{"f": `A\f`, "g": `B\g`},
+		},
+		{
+			name: "cpp namespace",
+			lang: "cpp",
+			src: `namespace ns {
+class C {
+public:
+    void m();
+};
+}
+`,
+			want: map[string]string{"C": "ns::C", "m": "ns::C::m"},
+		},
+		{
+			name: "ruby modules",
+			lang: "ruby",
+			src: `module M
+  class C
+    def m
+    end
+  end
+end
+`,
+			want: map[string]string{"C": "M::C", "m": "M::C::m"},
+		},
+		{
+			name:   "python module",
+			lang:   "python",
+			module: "pkg.util",
+			src: `class A:
+    def m(self):
+        pass
+
+def f():
+    pass
+`,
+			want: map[string]string{"A": "pkg.util.A", "m": "pkg.util.A.m", "f": "pkg.util.f"},
+		},
+		{
+			name:   "rust module",
+			lang:   "rust",
+			module: "crate::fs",
+			src: `pub fn read() {}
+
+pub mod inner {
+    pub fn write() {}
+}
+`,
+			want: map[string]string{"read": "crate::fs::read", "write": "crate::fs::inner::write"},
+		},
+		{
+			name: "no scope",
+			lang: "typescript",
+			src:  "export function f(): void {}\n",
+			want: map[string]string{"f": "f"},
+		},
+	}
+
+	p := NewTreeSitterParser()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := p.Parse([]byte(tt.src), &parser.Options{
+				Language:       tt.lang,
+				IncludePrivate: true,
+				Module:         tt.module,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make(map[string]string)
+			for _, sig := range result.Signatures {
+				got[sig.Name] = sig.QualifiedName
+			}
+			for name, qualified := range tt.want {
+				if q, ok := got[name]; !ok || q != qualified {
+					t.Errorf("%s: qualified name = %q (found %v), want %q", name, q, ok, qualified)
+				}
+			}
+		})
+	}
+}
+
+func TestQualifiedCaller(t *testing.T) {
+	src := `package a
+
+type A struct{}
+
+func (x A) Parse() { helper() }
+
+type B struct{}
+
+func (x B) Parse() { helper() }
+
+func helper() {}
+`
+	p := NewTreeSitterParser()
+	result, err := p.Parse([]byte(src), &parser.Options{Language: "go", IncludeCalls: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var callers []string
+	for _, call := range result.Calls {
+		if call.Callee == "helper" {
+			if call.Caller != "Parse" {
+				t.Errorf("Caller = %q, want Parse", call.Caller)
+			}
+			callers = append(callers, call.QualifiedCaller)
+		}
+	}
+	if len(callers) != 2 || callers[0] != "a.A.Parse" || callers[1] != "a.B.Parse" {
+		t.Errorf("qualified callers = %v, want [a.A.Parse a.B.Parse]", callers)
+	}
+}
+
+func TestGoReceiverType(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"func (s *FileScanner) Scan() error", "FileScanner"},
+		{"func (FileScanner) Name() string", "FileScanner"},
+		{"func (l *List[T]) Len() int", "List"},
+		{"func (m Map[K, V]) Get(k K) V", "Map"},
+		{"func Scan() error", ""},
+		{"type T struct", ""},
+	}
+	for _, tt := range tests {
+		if got := goReceiverType(tt.text); got != tt.want {
+			t.Errorf("goReceiverType(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
@@ -3,6 +3,8 @@ package treesitter
 
 import (
 	sitter "github.com/tree-sitter/go-tree-sitter"
+
+	"github.com/indigo-net/Brf.it/pkg/parser"
 )
 
 // LanguageQuery defines the interface for language-specific Tree-sitter queries.
@@ -27,13 +29,17 @@ type LanguageQuery interface {
 	// KindMapping maps Tree-sitter node types to Signature kinds.
 	KindMapping() map[string]string
 
-	// IsExported reports whether the symbol identified by name and sigText
-	// is public/exported in the given language. sigText is the full
-	// signature text which may contain visibility modifiers.
-	IsExported(name, sigText string) bool
+	// Visibility returns the visibility that the declaration named name
+	// states itself, through its modifiers or naming convention. head is
+	// the signature text ahead of the name, where modifiers appear. It
+	// returns "" when the declaration states none; the parser then applies
+	// the language default for the declaration's position.
+	Visibility(name, head string) parser.Visibility
 }
 
-// Capture names used across all language queries.
+// Capture names used across all language queries. Doc comments are
+// attached from the syntax tree around each declaration; CaptureDoc only
+// matters in patterns that capture a doc within the declaration's match.
 const (
 	CaptureName      = "name"
 	CaptureSignature = "signature"
@@ -0,0 +1,168 @@
+package treesitter
+
+import (
+	"strings"
+	"unicode"
+
+	sitter "github.com/tree-sitter/go-tree-sitter"
+
+	"github.com/indigo-net/Brf.it/pkg/parser"
+)
+
+// reactWrappers are the React functions that wrap a function component
+// (e.g., "forwardRef(...)", "React.memo(...)"), with the index of the props
+// type among their type arguments.
+var reactWrappers = map[string]int{
+	"forwardRef": 1, // forwardRef<Ref, Props>
+	"memo":       0, // memo<Props>
+}
+
+// reactComponentTypes are the types declaring a function component, whose
+// first type argument is the props type (e.g., "React.FC<Props>").
+var reactComponentTypes = map[string]bool{
+	"FC":                    true,
+	"FunctionComponent":     true,
+	"VFC":                   true,
+	"VoidFunctionComponent": true,
+}
+
+// jsxKinds are the JSX nodes that make a function a component.
+var jsxKinds = map[string]bool{
+	"jsx_element":              true,
+	"jsx_self_closing_element": true,
+	"jsx_fragment":             true,
+}
+
+// reactComponent reports a React function component of a .tsx or .jsx file
+// as kind "component": a capitalized function, or function assigned to a
+// variable, that returns JSX or is wrapped in forwardRef or memo. Its
+// Params are those of the wrapped function, with the props type taken from
+// the wrapper type arguments or a React.FC annotation when the props
+// parameter has none, and the wrappers are added to its Modifiers. Without
+// the body, the text of a wrapped component ends at the wrapped function's
+// parameters (e.g., "const Input = forwardRef((props, ref))").
+func reactComponent(lang string, sig *parser.Signature, sigNode, nameNode *sitter.Node, content []byte, includeBody bool) {
+	if lang != "tsx" && lang != "jsx" || !isComponentName(sig.Name) {
+		return
+	}
+	fn, propsType := nameNode.Parent(), ""
+	if fn == nil || !within(fn, sigNode) {
+		return
+	}
+	if fn.Kind() == "variable_declarator" {
+		if t := fn.ChildByFieldName("type"); t != nil {
+			propsType = componentTypeProps(t, content)
+		}
+		fn = fn.ChildByFieldName("value")
+	}
+
+	var wrappers []string
+	for fn != nil && fn.Kind() == "call_expression" {
+		name, idx, ok := reactWrapper(fn, content)
+		if !ok {
+			return
+		}
+		wrappers = append(wrappers, name)
+		if args := fn.ChildByFieldName("type_arguments"); args != nil && propsType == "" {
+			if idx < int(args.NamedChildCount()) {
+				propsType = nodeText(args.NamedChild(uint(idx)), content)
+			}
+		}
+		fn = firstArgument(fn)
+	}
+	if fn == nil || !functionValueKinds[fn.Kind()] && fn.Kind() != "function_declaration" {
+		return
+	}
+	if len(wrappers) == 0 && !containsJSX(fn.ChildByFieldName("body")) {
+		return
+	}
+
+	sig.Kind = "component"
+	sig.Params = nil
+	if list := paramList(fn); list != nil {
+		sig.Params = parameters(list, content)
+	} else if p := fn.ChildByFieldName("parameter"); p != nil {
+		// Arrow function with a single unparenthesized parameter
+		sig.Params = []parser.Parameter{{Name: nodeText(p, content)}}
+	}
+	if len(sig.Params) > 0 && sig.Params[0].Type == "" {
+		sig.Params[0].Type = propsType
+	}
+	sig.Returns = returnTypes(fn, content)
+	sig.Modifiers = append(sig.Modifiers, wrappers...)
+
+	if len(wrappers) > 0 && !includeBody {
+		if body := fn.ChildByFieldName("body"); body != nil && sigNode.StartByte() < body.StartByte() {
+			head := strings.TrimSpace(string(content[sigNode.StartByte():body.StartByte()]))
+			head = strings.TrimSpace(strings.TrimSuffix(head, "=>"))
+			sig.Text = head + strings.Repeat(")", len(wrappers))
+		}
+	}
+}
+
+// isComponentName reports whether name is capitalized, as React requires
+// of components.
+func isComponentName(name string) bool {
+	for _, r := range name {
+		return unicode.IsUpper(r)
+	}
+	return false
+}
+
+// reactWrapper returns the name of the React wrapper called by call, and
+// the index of the props type among its type arguments.
+func reactWrapper(call *sitter.Node, content []byte) (string, int, bool) {
+	fn := call.ChildByFieldName("function")
+	if fn == nil {
+		return "", 0, false
+	}
+	name := nodeText(fn, content)
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		if name[:i] != "React" {
+			return "", 0, false
+		}
+		name = name[i+1:]
+	}
+	idx, ok := reactWrappers[name]
+	return name, idx, ok
+}
+
+// firstArgument returns the first argument of a call, or nil.
+func firstArgument(call *sitter.Node) *sitter.Node {
+	args := call.ChildByFieldName("arguments")
+	if args == nil || args.NamedChildCount() == 0 {
+		return nil
+	}
+	return args.NamedChild(0)
+}
+
+// componentTypeProps returns the props type of a React.FC style type
+// annotation (e.g., "Props" for ": React.FC<Props>"), or "".
+func componentTypeProps(annotation *sitter.Node, content []byte) string {
+	text := strings.TrimSpace(strings.TrimPrefix(nodeText(annotation, content), ":"))
+	name, args, ok := strings.Cut(text, "<")
+	if !ok || !strings.HasSuffix(args, ">") {
+		return ""
+	}
+	name = strings.TrimPrefix(strings.TrimSpace(name), "React.")
+	if !reactComponentTypes[name] {
+		return ""
+	}
+	return strings.TrimSpace(strings.TrimSuffix(args, ">"))
+}
+
+// containsJSX reports whether the subtree of n holds a JSX element.
+func containsJSX(n *sitter.Node) bool {
+	if n == nil {
+		return false
+	}
+	if jsxKinds[n.Kind()] {
+		return true
+	}
+	for i := uint(0); i < n.NamedChildCount(); i++ {
+		if containsJSX(n.NamedChild(i)) {
+			return true
+		}
+	}
+	return false
+}
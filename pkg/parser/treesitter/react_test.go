@@ -0,0 +1,125 @@
+package treesitter
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/indigo-net/Brf.it/pkg/parser"
+)
+
+func TestReactComponent(t *testing.T) {
+	type component struct {
+		kind      string
+		text      string
+		params    []parser.Parameter
+		modifiers []string
+	}
+	tests := []struct {
+		name string
+		lang string
+		src  string
+		want map[string]component
+	}{
+		{
+			name: "function components",
+			lang: "tsx",
+			src: `export function Button({ label }: ButtonProps) {
+  return <button>{label}</button>;
+}
+
+const Card = ({ title }: { title: string }) => <div>{title}</div>;
+
+export function useThing(x: number) { return x * 2; }
+
+function helper() { return <span />; }
+`,
+			want: map[string]component{
+				"Button": {"component", "export function Button({ label }: ButtonProps)",
+					[]parser.Parameter{{Name: "{ label }", Type: "ButtonProps"}}, nil},
+				"Card": {"component", "const Card = ({ title }: { title: string })",
+					[]parser.Parameter{{Name: "{ title }", Type: "{ title: string }"}}, nil},
+				"useThing": {"export", "export function useThing(x: number)",
+					[]parser.Parameter{{Name: "x", Type: "number"}}, nil},
+				"helper": {"function", "function helper()", nil, nil},
+			},
+		},
+		{
+			name: "wrappers",
+			lang: "tsx",
+			src: `export const Input = forwardRef<HTMLInputElement, InputProps>((props, ref) => (
+  <input ref={ref} {...props} />
+));
+
+export const List = React.memo(function List({ items }: ListProps) {
+  return null;
+});
+
+export const Both = memo(forwardRef<HTMLDivElement, BothProps>((props, ref) => null));
+`,
+			want: map[string]component{
+				"Input": {"component", "export const Input = forwardRef<HTMLInputElement, InputProps>((props, ref))",
+					[]parser.Parameter{{Name: "props", Type: "InputProps"}, {Name: "ref"}}, []string{"forwardRef"}},
+				"List": {"component", "export const List = React.memo(function List({ items }: ListProps))",
+					[]parser.Parameter{{Name: "{ items }", Type: "ListProps"}}, []string{"memo"}},
+				"Both": {"component", "export const Both = memo(forwardRef<HTMLDivElement, BothProps>((props, ref)))",
+					[]parser.Parameter{{Name: "props", Type: "BothProps"}, {Name: "ref"}}, []string{"memo", "forwardRef"}},
+			},
+		},
+		{
+			name: "FC annotation",
+			lang: "tsx",
+			src:  `const Header: React.FC<HeaderProps> = ({ title }) => <h1>{title}</h1>;`,
+			want: map[string]component{
+				"Header": {"component", "const Header: React.FC<HeaderProps> = ({ title })",
+					[]parser.Parameter{{Name: "{ title }", Type: "HeaderProps"}}, nil},
+			},
+		},
+		{
+			name: "jsx",
+			lang: "jsx",
+			src: `export default function App({ user }) {
+  return <><Header user={user} /></>;
+}
+`,
+			want: map[string]component{
+				"App": {"component", "export default function App({ user })",
+					[]parser.Parameter{{Name: "{ user }"}}, nil},
+			},
+		},
+		{
+			name: "typescript has no components",
+			lang: "typescript",
+			src:  `export function Button(props: ButtonProps) { return null; }`,
+			want: map[string]component{
+				"Button": {"export", "export function Button(props: ButtonProps)",
+					[]parser.Parameter{{Name: "props", Type: "ButtonProps"}}, nil},
+			},
+		},
+	}
+
+	p := NewTreeSitterParser()
+	defer p.Close()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := p.Parse([]byte(tt.src), &parser.Options{Language: tt.lang, IncludePrivate: true})
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			got := make(map[string]component)
+			for _, sig := range result.Signatures {
+				if _, ok := tt.want[sig.Name]; ok {
+					if _, dup := got[sig.Name]; !dup {
+						got[sig.Name] = component{sig.Kind, sig.Text, sig.Params, sig.Modifiers}
+					}
+				}
+			}
+			for name, want := range tt.want {
+				if g, ok := got[name]; !ok {
+					t.Errorf("%s: signature not found", name)
+				} else if !reflect.DeepEqual(g, want) {
+					t.Errorf("%s: got %+v, want %+v", name, g, want)
+				}
+			}
+		})
+	}
+}
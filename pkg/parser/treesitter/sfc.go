@@ -0,0 +1,592 @@
+package treesitter
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+
+	sitter "github.com/tree-sitter/go-tree-sitter"
+
+	"github.com/indigo-net/Brf.it/pkg/parser"
+)
+
+// scriptHosts are the languages whose code lives in script blocks of a
+// markup file: single-file components (Vue, Svelte), Astro pages and HTML.
+var scriptHosts = map[string]bool{
+	"vue":    true,
+	"svelte": true,
+	"astro":  true,
+	"html":   true,
+}
+
+// scriptTypes maps the type attribute values of a script block holding
+// code to its language, "" when the block's lang attribute decides.
+var scriptTypes = map[string]string{
+	"":                       "",
+	"module":                 "",
+	"text/javascript":        "",
+	"application/javascript": "",
+	"text/ecmascript":        "",
+	"application/ecmascript": "",
+	"text/typescript":        "typescript",
+	"application/typescript": "typescript",
+	"text/babel":             "jsx",
+	"text/jsx":               "jsx",
+}
+
+// scriptLangs maps the lang attribute values of a script block to its
+// language, "" for the host's default.
+var scriptLangs = map[string]string{
+	"":           "",
+	"js":         "javascript",
+	"javascript": "javascript",
+	"ts":         "typescript",
+	"typescript": "typescript",
+	"jsx":        "jsx",
+	"tsx":        "tsx",
+}
+
+// scriptBlock is the code of a script block (or Astro frontmatter).
+type scriptBlock struct {
+	// start and end are the byte range of the code in the file.
+	start, end int
+
+	// lang is the language of the code: "javascript", "typescript",
+	// "jsx" or "tsx".
+	lang string
+}
+
+// scriptSource returns the code of the script blocks of a host file, and
+// the language to parse it as. Everything outside the blocks is blanked
+// out, keeping line breaks, so positions in the code are those of the
+// original file.
+func scriptSource(host string, content []byte) ([]byte, string) {
+	blocks := scriptBlocks(host, content)
+	masked := make([]byte, len(content))
+	for i, c := range content {
+		if c == '\n' || c == '\r' {
+			masked[i] = c
+		} else {
+			masked[i] = ' '
+		}
+	}
+	for _, b := range blocks {
+		copy(masked[b.start:b.end], content[b.start:b.end])
+	}
+	return masked, scriptLanguage(blocks)
+}
+
+// scriptLanguage returns the language to parse blocks as together: TSX
+// when they mix TypeScript and JSX.
+func scriptLanguage(blocks []scriptBlock) string {
+	var ts, jsx bool
+	for _, b := range blocks {
+		switch b.lang {
+		case "typescript":
+			ts = true
+		case "jsx":
+			jsx = true
+		case "tsx":
+			ts, jsx = true, true
+		}
+	}
+	switch {
+	case ts && jsx:
+		return "tsx"
+	case jsx:
+		return "jsx"
+	case ts:
+		return "typescript"
+	}
+	return "javascript"
+}
+
+// scriptBlocks returns the code blocks of a host file: the inline
+// <script> elements holding JavaScript or TypeScript and, in Astro, the
+// frontmatter. Scripts inside HTML comments are skipped. Astro code is
+// TypeScript by default, other hosts' JavaScript.
+func scriptBlocks(host string, content []byte) []scriptBlock {
+	var blocks []scriptBlock
+	defaultLang, i := "javascript", 0
+	if host == "astro" {
+		defaultLang = "typescript"
+		if start, end, ok := frontmatter(content); ok {
+			blocks = append(blocks, scriptBlock{start: start, end: end, lang: defaultLang})
+			i = end
+		}
+	}
+
+	for i < len(content) {
+		lt := bytes.IndexByte(content[i:], '<')
+		if lt < 0 {
+			break
+		}
+		i += lt
+		if bytes.HasPrefix(content[i:], []byte("<!--")) {
+			end := bytes.Index(content[i+4:], []byte("-->"))
+			if end < 0 {
+				break
+			}
+			i += 4 + end + 3
+			continue
+		}
+		if !isScriptTag(content[i:]) {
+			i++
+			continue
+		}
+
+		attrs, open, selfClosing := tagAttributes(content, i+len("<script"))
+		if selfClosing {
+			i = open
+			continue
+		}
+		end := indexFold(content[open:], "</script")
+		if end < 0 {
+			end = len(content) - open
+		}
+		i = open + end
+
+		lang, ok := blockLanguage(attrs)
+		if !ok || attrs["src"] != "" {
+			continue
+		}
+		if lang == "" {
+			lang = defaultLang
+		}
+		blocks = append(blocks, scriptBlock{start: open, end: open + end, lang: lang})
+	}
+	return blocks
+}
+
+// blockLanguage returns the language of a script block from its type and
+// lang attributes ("" for the host's default), or false when it does not
+// hold code (e.g., type="application/json").
+func blockLanguage(attrs map[string]string) (string, bool) {
+	typed, ok := scriptTypes[strings.ToLower(attrs["type"])]
+	if !ok {
+		return "", false
+	}
+	lang, ok := scriptLangs[strings.ToLower(attrs["lang"])]
+	if !ok {
+		return "", false
+	}
+	if lang == "" {
+		lang = typed
+	}
+	return lang, true
+}
+
+// isScriptTag reports whether b starts with a <script> start tag, in any case.
+func isScriptTag(b []byte) bool {
+	const tag = "<script"
+	if len(b) <= len(tag) || !bytes.EqualFold(b[:len(tag)], []byte(tag)) {
+		return false
+	}
+	switch b[len(tag)] {
+	case '>', '/', ' ', '\t', '\n', '\r', '\f':
+		return true
+	}
+	return false
+}
+
+// tagAttributes parses the attributes of the start tag whose attributes
+// begin at content[i], returning them with lower-case names, the offset
+// just past the tag and whether the tag is self-closing. Attributes
+// without a value (e.g., "setup") map to "".
+func tagAttributes(content []byte, i int) (map[string]string, int, bool) {
+	attrs := make(map[string]string)
+	for i < len(content) {
+		c := content[i]
+		switch {
+		case c == '>':
+			return attrs, i + 1, i > 0 && content[i-1] == '/'
+		case isSpace(c) || c == '/':
+			i++
+			continue
+		}
+
+		start := i
+		for i < len(content) && !isSpace(content[i]) && content[i] != '=' && content[i] != '>' && content[i] != '/' {
+			i++
+		}
+		name := strings.ToLower(string(content[start:i]))
+		value := ""
+		if i < len(content) && content[i] == '=' {
+			i++
+			if i < len(content) && (content[i] == '"' || content[i] == '\'') {
+				quote := content[i]
+				end := bytes.IndexByte(content[i+1:], quote)
+				if end < 0 {
+					return attrs, len(content), false
+				}
+				value = string(content[i+1 : i+1+end])
+				i += end + 2
+			} else {
+				start := i
+				for i < len(content) && !isSpace(content[i]) && content[i] != '>' {
+					i++
+				}
+				value = string(content[start:i])
+			}
+		}
+		attrs[name] = value
+	}
+	return attrs, len(content), false
+}
+
+// isSpace reports whether c is HTML whitespace.
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+// indexFold returns the index of the first instance of the ASCII string
+// sep in b, in any case, or -1.
+func indexFold(b []byte, sep string) int {
+	for i := 0; i+len(sep) <= len(b); i++ {
+		if bytes.EqualFold(b[i:i+len(sep)], []byte(sep)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// frontmatter returns the byte range of the code between the "---" fences
+// opening an Astro file.
+func frontmatter(content []byte) (int, int, bool) {
+	start := len(content) - len(bytes.TrimLeft(content, " \t\r\n"))
+	line, rest, ok := bytes.Cut(content[start:], []byte("\n"))
+	if !ok || string(bytes.TrimSpace(line)) != "---" {
+		return 0, 0, false
+	}
+	start = len(content) - len(rest)
+	for i := start; i < len(content); {
+		line, _, _ := bytes.Cut(content[i:], []byte("\n"))
+		if string(bytes.TrimSpace(line)) == "---" {
+			return start, i, true
+		}
+		i += len(line) + 1
+	}
+	return 0, 0, false
+}
+
+// componentAPI reports the inputs and outputs of the component in a host
+// file among its signatures:
+//   - Vue: defineProps and defineEmits (also in withDefaults), and the
+//     props and emits options of an exported component object
+//   - Svelte: "export let" props, $props() and createEventDispatcher
+//   - Astro: the Props type and Astro.props
+//
+// Declarations already among signatures get kind "props", "emits" or
+// "prop"; the others are added. Their Params are the declared props or
+// events, with types when the source states them.
+func componentAPI(host string, signatures []parser.Signature, root *sitter.Node, content []byte) []parser.Signature {
+	byStart := make(map[int]int, len(signatures))
+	for i, sig := range signatures {
+		byStart[sig.StartByte] = i
+	}
+
+	for i := range signatures {
+		sig := &signatures[i]
+		switch {
+		case host == "svelte" && strings.HasPrefix(sig.Text, "export let "):
+			sig.Kind = "prop"
+		case host == "astro" && sig.Name == "Props" && (sig.Kind == "interface" || sig.Kind == "type"):
+			sig.Kind = "props"
+		}
+	}
+
+	var added bool
+	for i := uint(0); i < root.NamedChildCount(); i++ {
+		stmt := root.NamedChild(i)
+		for _, api := range statementAPI(host, stmt, root, content) {
+			if j, ok := byStart[int(api.node.StartByte())]; ok {
+				signatures[j].Kind = api.kind
+				signatures[j].Params = api.params
+				signatures[j].Visibility, signatures[j].Exported = parser.VisibilityPublic, true
+				continue
+			}
+			signatures = append(signatures, apiSignature(api, content))
+			added = true
+		}
+	}
+	if added {
+		sort.SliceStable(signatures, func(i, j int) bool {
+			return signatures[i].StartByte < signatures[j].StartByte
+		})
+	}
+	return signatures
+}
+
+// componentDecl is a declaration of a component's props or emits.
+type componentDecl struct {
+	// node is the declaration: a statement, or a property of the exported
+	// component object.
+	node *sitter.Node
+
+	// kind is "props" or "emits".
+	kind string
+
+	// params are the declared props or events.
+	params []parser.Parameter
+}
+
+// componentCalls maps the functions declaring a component's props or
+// emits to the kind of declaration, per host.
+var componentCalls = map[string]map[string]string{
+	"vue":    {"defineProps": "props", "defineEmits": "emits"},
+	"svelte": {"$props": "props", "createEventDispatcher": "emits"},
+}
+
+// statementAPI returns the props and emits declared by a top-level statement.
+func statementAPI(host string, stmt, root *sitter.Node, content []byte) []componentDecl {
+	switch stmt.Kind() {
+	case "lexical_declaration", "variable_declaration":
+		for i := uint(0); i < stmt.NamedChildCount(); i++ {
+			decl := stmt.NamedChild(i)
+			if decl.Kind() != "variable_declarator" {
+				continue
+			}
+			value := decl.ChildByFieldName("value")
+			if host == "astro" && value != nil && nodeText(value, content) == "Astro.props" {
+				return []componentDecl{{node: stmt, kind: "props", params: patternParams(decl, root, content)}}
+			}
+			if call, kind := componentCall(host, value, content); call != nil {
+				params := callParams(call, root, content)
+				if host == "svelte" && kind == "props" {
+					params = patternParams(decl, root, content)
+				}
+				return []componentDecl{{node: stmt, kind: kind, params: params}}
+			}
+		}
+	case "expression_statement":
+		if stmt.NamedChildCount() > 0 {
+			if call, kind := componentCall(host, stmt.NamedChild(0), content); call != nil {
+				return []componentDecl{{node: stmt, kind: kind, params: callParams(call, root, content)}}
+			}
+		}
+	case "export_statement":
+		if host == "vue" {
+			return optionsAPI(stmt.ChildByFieldName("value"), root, content)
+		}
+	}
+	return nil
+}
+
+// componentCall returns the call of n declaring props or emits, looking
+// through withDefaults, and the kind of declaration.
+func componentCall(host string, n *sitter.Node, content []byte) (*sitter.Node, string) {
+	for n != nil && n.Kind() == "call_expression" {
+		fn := nodeText(n.ChildByFieldName("function"), content)
+		if kind, ok := componentCalls[host][fn]; ok {
+			return n, kind
+		}
+		if fn != "withDefaults" {
+			break
+		}
+		n = firstArgument(n)
+	}
+	return nil, ""
+}
+
+// optionsAPI returns the props and emits options of a Vue component
+// object, given plainly or to defineComponent.
+func optionsAPI(value, root *sitter.Node, content []byte) []componentDecl {
+	if value != nil && value.Kind() == "call_expression" && nodeText(value.ChildByFieldName("function"), content) == "defineComponent" {
+		value = firstArgument(value)
+	}
+	if value == nil || value.Kind() != "object" {
+		return nil
+	}
+	var decls []componentDecl
+	for i := uint(0); i < value.NamedChildCount(); i++ {
+		pair := value.NamedChild(i)
+		if pair.Kind() != "pair" {
+			continue
+		}
+		switch key := propertyName(pair.ChildByFieldName("key"), content); key {
+		case "props", "emits":
+			decls = append(decls, componentDecl{node: pair, kind: key, params: valueParams(pair.ChildByFieldName("value"), root, content)})
+		}
+	}
+	return decls
+}
+
+// callParams returns the props or events declared by a defineProps-like
+// call: from its type argument, or else its runtime argument.
+func callParams(call, root *sitter.Node, content []byte) []parser.Parameter {
+	if args := call.ChildByFieldName("type_arguments"); args != nil && args.NamedChildCount() > 0 {
+		return typeParams(args.NamedChild(0), root, content)
+	}
+	return valueParams(firstArgument(call), root, content)
+}
+
+// valueParams returns the props or events declared by a runtime value: the
+// keys of an object (typed by a constructor such as String, or by its
+// "type" option), or the strings of an array.
+func valueParams(value, root *sitter.Node, content []byte) []parser.Parameter {
+	if value == nil {
+		return nil
+	}
+	var params []parser.Parameter
+	switch value.Kind() {
+	case "object":
+		for i := uint(0); i < value.NamedChildCount(); i++ {
+			pair := value.NamedChild(i)
+			if pair.Kind() != "pair" {
+				continue
+			}
+			p := parser.Parameter{Name: propertyName(pair.ChildByFieldName("key"), content)}
+			v := pair.ChildByFieldName("value")
+			if v == nil {
+				params = append(params, p)
+				continue
+			}
+			switch v.Kind() {
+			case "identifier", "array":
+				p.Type = nodeText(v, content)
+			case "object":
+				for j := uint(0); j < v.NamedChildCount(); j++ {
+					opt := v.NamedChild(j)
+					if opt.Kind() == "pair" && propertyName(opt.ChildByFieldName("key"), content) == "type" {
+						p.Type = nodeText(opt.ChildByFieldName("value"), content)
+					}
+				}
+			}
+			params = append(params, p)
+		}
+	case "array":
+		for i := uint(0); i < value.NamedChildCount(); i++ {
+			if item := value.NamedChild(i); item.Kind() == "string" {
+				params = append(params, parser.Parameter{Name: strings.Trim(nodeText(item, content), "'\"`")})
+			}
+		}
+	}
+	return params
+}
+
+// typeParams returns the props or events declared by a type: the members
+// of an object type, or of the interface or type alias it names in the
+// same file. Call signatures declare events named by their first
+// parameter's literal type (e.g., "(e: 'change', id: number): void").
+func typeParams(t, root *sitter.Node, content []byte) []parser.Parameter {
+	if t.Kind() == "type_identifier" {
+		t = localType(nodeText(t, content), root, content)
+	}
+	if t == nil || (t.Kind() != "object_type" && t.Kind() != "interface_body") {
+		return nil
+	}
+	var params []parser.Parameter
+	for i := uint(0); i < t.NamedChildCount(); i++ {
+		member := t.NamedChild(i)
+		switch member.Kind() {
+		case "property_signature":
+			p := parser.Parameter{Name: propertyName(member.ChildByFieldName("name"), content)}
+			if typ := member.ChildByFieldName("type"); typ != nil {
+				p.Type = strings.TrimSpace(strings.TrimPrefix(nodeText(typ, content), ":"))
+			}
+			params = append(params, p)
+		case "call_signature":
+			list := member.ChildByFieldName("parameters")
+			if list == nil || list.NamedChildCount() == 0 {
+				continue
+			}
+			event := list.NamedChild(0).ChildByFieldName("type")
+			if event == nil {
+				continue
+			}
+			name := strings.TrimSpace(strings.TrimPrefix(nodeText(event, content), ":"))
+			params = append(params, parser.Parameter{Name: strings.Trim(name, "'\"`")})
+		}
+	}
+	return params
+}
+
+// localType returns the body of the top-level interface, or the value of
+// the type alias, named name, or nil.
+func localType(name string, root *sitter.Node, content []byte) *sitter.Node {
+	for i := uint(0); i < root.NamedChildCount(); i++ {
+		decl := root.NamedChild(i)
+		if decl.Kind() == "export_statement" {
+			if d := decl.ChildByFieldName("declaration"); d != nil {
+				decl = d
+			}
+		}
+		if n := decl.ChildByFieldName("name"); n == nil || nodeText(n, content) != name {
+			continue
+		}
+		switch decl.Kind() {
+		case "interface_declaration":
+			return decl.ChildByFieldName("body")
+		case "type_alias_declaration":
+			return decl.ChildByFieldName("value")
+		}
+	}
+	return nil
+}
+
+// patternParams returns the props destructured by a declarator (e.g.,
+// "let { title, count = 0 }: Props = $props()"), typed by the members of
+// its type annotation, or of the Props type by Astro convention.
+func patternParams(decl, root *sitter.Node, content []byte) []parser.Parameter {
+	pattern := decl.ChildByFieldName("name")
+	if pattern == nil || pattern.Kind() != "object_pattern" {
+		return nil
+	}
+	types := make(map[string]string)
+	typeName := "Props"
+	if t := decl.ChildByFieldName("type"); t != nil {
+		typeName = strings.TrimSpace(strings.TrimPrefix(nodeText(t, content), ":"))
+	}
+	if body := localType(typeName, root, content); body != nil {
+		for _, p := range typeParams(body, root, content) {
+			types[p.Name] = p.Type
+		}
+	}
+
+	var params []parser.Parameter
+	for i := uint(0); i < pattern.NamedChildCount(); i++ {
+		var name string
+		switch prop := pattern.NamedChild(i); prop.Kind() {
+		case "shorthand_property_identifier_pattern":
+			name = nodeText(prop, content)
+		case "object_assignment_pattern":
+			name = nodeText(prop.ChildByFieldName("left"), content)
+		case "pair_pattern":
+			name = propertyName(prop.ChildByFieldName("key"), content)
+		case "rest_pattern":
+			if prop.NamedChildCount() > 0 {
+				params = append(params, parser.Parameter{Name: "..." + nodeText(prop.NamedChild(0), content)})
+			}
+			continue
+		default:
+			continue
+		}
+		params = append(params, parser.Parameter{Name: name, Type: types[name]})
+	}
+	return params
+}
+
+// propertyName returns the name of an object key or property, unquoted.
+func propertyName(n *sitter.Node, content []byte) string {
+	return strings.Trim(nodeText(n, content), "'\"`")
+}
+
+// apiSignature returns the signature of a props or emits declaration that
+// the query did not capture.
+func apiSignature(api componentDecl, content []byte) parser.Signature {
+	n := api.node
+	return parser.Signature{
+		Name:       api.kind,
+		Kind:       api.kind,
+		Text:       nodeText(n, content),
+		Line:       int(n.StartPosition().Row) + 1,
+		EndLine:    int(n.EndPosition().Row) + 1,
+		Column:     int(n.StartPosition().Column) + 1,
+		EndColumn:  int(n.EndPosition().Column) + 1,
+		StartByte:  int(n.StartByte()),
+		EndByte:    int(n.EndByte()),
+		Visibility: parser.VisibilityPublic,
+		Exported:   true,
+		Params:     api.params,
+	}
+}
@@ -0,0 +1,185 @@
+package treesitter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/indigo-net/Brf.it/pkg/parser"
+)
+
+func TestScriptBlocks(t *testing.T) {
+	tests := []struct {
+		name     string
+		host     string
+		content  string
+		wantCode []string
+		wantLang string
+	}{
+		{
+			name:     "vue script and script setup",
+			host:     "vue",
+			content:  "<template><p/></template>\n<script>\nexport default {}\n</script>\n<script setup lang=\"ts\">\nconst a = 1\n</script>\n",
+			wantCode: []string{"\nexport default {}\n", "\nconst a = 1\n"},
+			wantLang: "typescript",
+		},
+		{
+			name:     "html skips data, external and commented scripts",
+			host:     "html",
+			content:  "<!-- <script>old()</script> -->\n<script type=\"application/json\">{}</script>\n<script src=\"a.js\"></script>\n<SCRIPT type=module>boot()</SCRIPT>",
+			wantCode: []string{"boot()"},
+			wantLang: "javascript",
+		},
+		{
+			name:     "svelte jsx and ts make tsx",
+			host:     "svelte",
+			content:  "<script context='module' lang='ts'>a</script><script lang=\"jsx\">b</script>",
+			wantCode: []string{"a", "b"},
+			wantLang: "tsx",
+		},
+		{
+			name:     "astro frontmatter and scripts are typescript",
+			host:     "astro",
+			content:  "---\nconst a = 1\n---\n<h1>{a}</h1>\n<script>track()</script>\n",
+			wantCode: []string{"const a = 1\n", "track()"},
+			wantLang: "typescript",
+		},
+		{
+			name:     "unclosed script runs to the end",
+			host:     "html",
+			content:  "<script>\nfunction f() {}",
+			wantCode: []string{"\nfunction f() {}"},
+			wantLang: "javascript",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blocks := scriptBlocks(tt.host, []byte(tt.content))
+			var code []string
+			for _, b := range blocks {
+				code = append(code, tt.content[b.start:b.end])
+			}
+			if strings.Join(code, "|") != strings.Join(tt.wantCode, "|") {
+				t.Errorf("expected blocks %q, got %q", tt.wantCode, code)
+			}
+			if got := scriptLanguage(blocks); got != tt.wantLang {
+				t.Errorf("expected language %q, got %q", tt.wantLang, got)
+			}
+		})
+	}
+}
+
+func TestScriptHostPositions(t *testing.T) {
+	src := []byte("<template>\n  <p>héllo</p>\n</template>\n\n<script>\nexport function greet(name) {}\n</script>\n")
+	p := NewTreeSitterParser()
+	defer p.Close()
+
+	result, err := p.Parse(src, &parser.Options{Language: "vue"})
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if result.Language != "vue" || len(result.Signatures) == 0 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	sig := result.Signatures[0]
+	if sig.Language != "vue" || sig.Line != 6 || sig.Column != 1 {
+		t.Errorf("expected vue signature at 6:1, got %s at %d:%d", sig.Language, sig.Line, sig.Column)
+	}
+	if got := string(src[sig.StartByte:sig.EndByte]); !strings.HasPrefix(got, "export function greet") {
+		t.Errorf("expected byte range of greet, got %q", got)
+	}
+}
+
+func TestComponentAPI(t *testing.T) {
+	tests := []struct {
+		name    string
+		lang    string
+		src     string
+		want    []string // kind name(params)
+		notWant string
+	}{
+		{
+			name: "vue script setup",
+			lang: "vue",
+			src: `<script setup lang="ts">
+interface Props { title: string; count?: number }
+const props = withDefaults(defineProps<Props>(), { count: 0 })
+defineEmits<{ (e: 'change', id: number): void; close: [] }>()
+</script>`,
+			want: []string{"props props(title string, count number)", "emits emits(change, close [])"},
+		},
+		{
+			name: "vue options api",
+			lang: "vue",
+			src: `<script>
+export default defineComponent({
+  props: { title: String, size: { type: Number, default: 1 } },
+  emits: ['close'],
+})
+</script>`,
+			want: []string{"props props(title String, size Number)", "emits emits(close)"},
+		},
+		{
+			name: "svelte",
+			lang: "svelte",
+			src: `<script lang="ts">
+  export let label: string;
+  const dispatch = createEventDispatcher<{ press: MouseEvent }>();
+</script>`,
+			want: []string{"prop label()", "emits dispatch(press MouseEvent)"},
+		},
+		{
+			name: "svelte runes",
+			lang: "svelte",
+			src: `<script lang="ts">
+  type Props = { title: string; body?: string };
+  let { title, body = '', ...rest }: Props = $props();
+</script>`,
+			want: []string{"props props(title string, body string, ...rest)"},
+		},
+		{
+			name: "astro",
+			lang: "astro",
+			src: `---
+interface Props { title: string }
+const { title } = Astro.props
+---
+<h1>{title}</h1>`,
+			want: []string{"props Props()", "props props(title string)"},
+		},
+		{
+			name:    "plain javascript is not a component api",
+			lang:    "html",
+			src:     "<script>defineProps(['a'])</script>",
+			notWant: "props",
+		},
+	}
+
+	p := NewTreeSitterParser()
+	defer p.Close()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := p.Parse([]byte(tt.src), &parser.Options{Language: tt.lang})
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			var got []string
+			for _, sig := range result.Signatures {
+				var params []string
+				for _, param := range sig.Params {
+					params = append(params, strings.TrimSpace(param.Name+" "+param.Type))
+				}
+				got = append(got, sig.Kind+" "+sig.Name+"("+strings.Join(params, ", ")+")")
+			}
+			joined := strings.Join(got, "\n")
+			for _, want := range tt.want {
+				if !strings.Contains(joined, want) {
+					t.Errorf("expected signature %q, got:\n%s", want, joined)
+				}
+			}
+			if tt.notWant != "" && strings.Contains(joined, tt.notWant) {
+				t.Errorf("unexpected %q signature, got:\n%s", tt.notWant, joined)
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package treesitter
+
+import (
+	"strings"
+
+	sitter "github.com/tree-sitter/go-tree-sitter"
+
+	"github.com/indigo-net/Brf.it/pkg/parser"
+)
+
+// headerFollowerKinds are nodes that may directly follow a file header
+// comment without taking it as their doc, beyond the kinds naming an
+// import, package or include.
+var headerFollowerKinds = map[string]bool{
+	"use_declaration":                   true, // Rust
+	"extern_crate_declaration":          true, // Rust
+	"using_directive":                   true, // C#
+	"namespace_use_declaration":         true, // PHP
+	"preproc_ifdef":                     true, // C/C++ include guards
+	"preproc_call":                      true, // C/C++ "#pragma once"
+	"file_scoped_namespace_declaration": true, // C#
+}
+
+// fileTags are the doc tags that mark a comment as the doc of the whole
+// file (JSDoc "@file", "@fileoverview", "@module", TSDoc
+// "@packageDocumentation").
+var fileTags = []string{"file", "fileoverview", "overview", "module", "packageDocumentation"}
+
+// fileSummary returns the summary of a file (see parser.ParseResult.Summary):
+// the Go package comment, the Python module docstring, the Rust inner doc
+// of the crate or module, the Elixir "@moduledoc" of its first module, or
+// else the comment heading the file. Go files only have package comments.
+func fileSummary(lang string, root *sitter.Node, content []byte) string {
+	var doc string
+	switch lang {
+	case "go":
+		doc = goPackageDoc(root, content)
+	case "python":
+		doc = pythonModuleDocstring(root, content)
+	case "rust":
+		doc = rustInnerDocComments(root, content)
+	case "elixir":
+		if first := firstNonComment(root); first != nil {
+			doc = elixirDoc(first, content)
+		}
+	}
+	if doc == "" && lang != "go" {
+		doc = headerComment(lang, root, content)
+	}
+	return summaryParagraph(doc)
+}
+
+// goPackageDoc returns the package comment of a Go file: the comments right
+// before its package clause, unless they are a license header.
+func goPackageDoc(root *sitter.Node, content []byte) string {
+	for i := uint(0); i < root.NamedChildCount(); i++ {
+		if c := root.NamedChild(i); c.Kind() == "package_clause" {
+			if doc := precedingComments("go", c, content); !parser.IsLicenseHeader(doc) {
+				return doc
+			}
+			return ""
+		}
+	}
+	return ""
+}
+
+// pythonModuleDocstring returns the docstring of a Python module: a string
+// literal as its first statement.
+func pythonModuleDocstring(root *sitter.Node, content []byte) string {
+	stmt := firstNonComment(root)
+	if stmt == nil || stmt.Kind() != "expression_statement" || stmt.NamedChildCount() != 1 {
+		return ""
+	}
+	if str := stmt.NamedChild(0); str.Kind() == "string" {
+		return cleanDocString(sourceText(str, content))
+	}
+	return ""
+}
+
+// firstNonComment returns the first named child of n that is not a comment.
+func firstNonComment(n *sitter.Node) *sitter.Node {
+	for i := uint(0); i < n.NamedChildCount(); i++ {
+		if c := n.NamedChild(i); !isCommentNode(c) {
+			return c
+		}
+	}
+	return nil
+}
+
+// headerComment returns the comment heading a file. The leading comments
+// are split into blocks at blank lines; shebangs and license headers are
+// skipped. The first remaining block is the header unless it documents the
+// declaration right after it (see precedingComments), which a file tag such
+// as "@file" overrides.
+func headerComment(lang string, root *sitter.Node, content []byte) string {
+	var block []string
+	var last *sitter.Node
+	for i := uint(0); i < root.ChildCount(); i++ {
+		c := root.Child(i)
+		if c.Kind() == "php_tag" {
+			continue
+		}
+		if isCommentNode(c) {
+			text := sourceText(c, content)
+			if strings.HasPrefix(text, "#!") {
+				continue
+			}
+			if last != nil && int(c.StartPosition().Row)-lastRow(last) > 1 {
+				if doc := headerBlock(block); doc != "" {
+					return doc
+				}
+				block = nil
+			}
+			block = append(block, text)
+			last = c
+			continue
+		}
+		if len(block) == 0 {
+			return ""
+		}
+		doc := headerBlock(block)
+		if doc == "" || hasFileTag(doc) || isHeaderFollower(c) {
+			return doc
+		}
+		gap := int(c.StartPosition().Row) - lastRow(last)
+		if gap <= 1 || isDocMarkerComment(block[len(block)-1]) {
+			// The block is the doc of the declaration below it
+			return ""
+		}
+		return doc
+	}
+	return headerBlock(block)
+}
+
+// headerBlock joins a block of comments into a doc, or returns "" when the
+// block is a license header.
+func headerBlock(block []string) string {
+	lines := make([]string, len(block))
+	for i, text := range block {
+		lines[i] = cleanComment(text)
+	}
+	doc := strings.TrimSpace(strings.Join(lines, "\n"))
+	if parser.IsLicenseHeader(doc) {
+		return ""
+	}
+	return doc
+}
+
+// isHeaderFollower reports whether n is an import, package, include or
+// similar statement, which never takes the comment before it as its doc.
+func isHeaderFollower(n *sitter.Node) bool {
+	kind := n.Kind()
+	return strings.Contains(kind, "import") || strings.Contains(kind, "package") ||
+		strings.Contains(kind, "include") || headerFollowerKinds[kind]
+}
+
+// hasFileTag reports whether doc has a tag marking it as the doc of the
+// whole file.
+func hasFileTag(doc string) bool {
+	for _, s := range parser.ParseDoc(doc).Sections {
+		if hasAnyFold(s.Title, fileTags) {
+			return true
+		}
+	}
+	return false
+}
+
+// summaryParagraph returns the first paragraph of the description of doc
+// on one line. A doc made only of file tags ("@file Helpers for X.")
+// yields the text of the first of them.
+func summaryParagraph(doc string) string {
+	if doc == "" {
+		return ""
+	}
+	d := parser.ParseDoc(parser.DocPlainText(doc))
+	text := strings.TrimSpace(d.Description)
+	if text == "" {
+		for _, s := range d.Sections {
+			if hasAnyFold(s.Title, fileTags) && strings.TrimSpace(s.Body) != "" {
+				text = strings.TrimSpace(s.Body)
+				break
+			}
+		}
+	}
+	para, _, _ := strings.Cut(text, "\n\n")
+	return strings.Join(strings.Fields(para), " ")
+}
+
+// hasAnyFold reports whether s equals any of values, ignoring case.
+func hasAnyFold(s string, values []string) bool {
+	for _, v := range values {
+		if strings.EqualFold(s, v) {
+			return true
+		}
+	}
+	return false
+}
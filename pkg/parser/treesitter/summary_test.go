@@ -0,0 +1,145 @@
+package treesitter
+
+import (
+	"testing"
+
+	"github.com/indigo-net/Brf.it/pkg/parser"
+)
+
+func TestFileSummary(t *testing.T) {
+	tests := []struct {
+		name string
+		lang string
+		src  string
+		want string
+	}{
+		{
+			name: "go package comment",
+			lang: "go",
+			src: `// Copyright 2024 The Authors. All rights reserved.
+
+//go:build linux
+
+// Package foo provides fooing
+// utilities.
+//
+// More details.
+package foo
+`,
+			want: "Package foo provides fooing utilities.",
+		},
+		{
+			name: "go header is not a package comment",
+			lang: "go",
+			src: `// Helpers.
+
+package foo
+`,
+			want: "",
+		},
+		{
+			name: "python module docstring",
+			lang: "python",
+			src: `# SPDX-License-Identifier: MIT
+"""Module helpers.
+
+Extra paragraph.
+"""
+import os
+`,
+			want: "Module helpers.",
+		},
+		{
+			name: "python header comment",
+			lang: "python",
+			src: `#!/usr/bin/env python
+# Utility functions for
+# string handling.
+
+import os
+`,
+			want: "Utility functions for string handling.",
+		},
+		{
+			name: "rust crate doc",
+			lang: "rust",
+			src: `// Licensed under the Apache License, Version 2.0
+//! The crate does
+//! rusty things.
+
+/// Doc of f.
+pub fn f() {}
+`,
+			want: "The crate does rusty things.",
+		},
+		{
+			name: "jsdoc file tag",
+			lang: "javascript",
+			src: `/**
+ * @fileoverview Entry point.
+ */
+export function run() {}
+`,
+			want: "Entry point.",
+		},
+		{
+			name: "header before import",
+			lang: "typescript",
+			src: `// Routing helpers.
+import { a } from "./a";
+`,
+			want: "Routing helpers.",
+		},
+		{
+			name: "declaration doc is not a header",
+			lang: "typescript",
+			src: `// Documents x.
+export function x() {}
+`,
+			want: "",
+		},
+		{
+			name: "doc comment after blank line is not a header",
+			lang: "typescript",
+			src: `/** Documents x. */
+
+export function x() {}
+`,
+			want: "",
+		},
+		{
+			name: "header separated by blank line",
+			lang: "typescript",
+			src: `/*
+ * Helpers for the C module.
+ */
+
+export function c() {}
+`,
+			want: "Helpers for the C module.",
+		},
+		{
+			name: "license only",
+			lang: "java",
+			src: `/* Copyright (c) Acme. All rights reserved. */
+
+package com.acme;
+`,
+			want: "",
+		},
+	}
+
+	p := NewTreeSitterParser()
+	defer p.Close()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := p.Parse([]byte(tt.src), &parser.Options{Language: tt.lang})
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if result.Summary != tt.want {
+				t.Errorf("Summary = %q, want %q", result.Summary, tt.want)
+			}
+		})
+	}
+}
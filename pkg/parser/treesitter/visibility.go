@@ -0,0 +1,309 @@
+package treesitter
+
+import (
+	"strings"
+
+	sitter "github.com/tree-sitter/go-tree-sitter"
+
+	"github.com/indigo-net/Brf.it/pkg/parser"
+)
+
+// rubyVisibilityMethods are the Ruby methods that set the visibility of the
+// methods defined after them (or of the method passed to them).
+var rubyVisibilityMethods = map[string]parser.Visibility{
+	"public":    parser.VisibilityPublic,
+	"protected": parser.VisibilityProtected,
+	"private":   parser.VisibilityPrivate,
+}
+
+// visibility returns the visibility of a declaration: the one it states
+// itself (see LanguageQuery.Visibility), or else the default for its
+// language and position in the syntax tree. head is the signature text
+// ahead of the name. module describes the module system of a
+// JavaScript/TypeScript file.
+func visibility(lang string, q LanguageQuery, sig *parser.Signature, sigNode *sitter.Node, head string, module esModule, content []byte) parser.Visibility {
+	if v := q.Visibility(sig.Name, head); v != "" {
+		return v
+	}
+	switch lang {
+	case "typescript", "tsx", "javascript", "jsx":
+		return typeScriptVisibility(sig.Name, sigNode, module)
+	case "rust":
+		return rustVisibility(sig, sigNode)
+	case "java":
+		return javaVisibility(sigNode)
+	case "csharp":
+		return csharpVisibility(sigNode)
+	case "cpp":
+		return cppVisibility(sigNode, head, content)
+	case "swift":
+		return swiftVisibility(sigNode)
+	case "ruby":
+		return rubyVisibility(sigNode, content)
+	}
+	return parser.VisibilityPublic
+}
+
+// declarationHead returns the signature text ahead of the declared name,
+// where modifiers appear. start is where the signature text begins in
+// content. Without a usable name node, the first line of text is used.
+func declarationHead(text string, nameNode *sitter.Node, start uint, content []byte) string {
+	if nameNode != nil && nameNode.StartByte() >= start && nameNode.StartByte() <= uint(len(content)) {
+		return string(content[start:nameNode.StartByte()])
+	}
+	if i := strings.IndexByte(text, '\n'); i >= 0 {
+		return text[:i]
+	}
+	return text
+}
+
+// esModule describes the module system of a JavaScript/TypeScript file.
+type esModule struct {
+	// module reports whether the file is an ES module, i.e. has top-level
+	// import or export statements. Top-level declarations of scripts are
+	// global.
+	module bool
+
+	// exports are the local names the module exports apart from their
+	// declarations: in export lists ("export { a, b as c }"), "export
+	// default a" and "export = a".
+	exports map[string]bool
+}
+
+// moduleExports returns the module system of a JavaScript/TypeScript file.
+// Re-exports from other modules ("export { a } from './a'") name no local
+// declarations and are ignored.
+func moduleExports(root *sitter.Node, content []byte) esModule {
+	var m esModule
+	for i := uint(0); i < root.NamedChildCount(); i++ {
+		stmt := root.NamedChild(i)
+		switch stmt.Kind() {
+		case "import_statement":
+			m.module = true
+		case "export_statement":
+			m.module = true
+			if stmt.ChildByFieldName("source") != nil || stmt.ChildByFieldName("declaration") != nil {
+				continue
+			}
+			for j := uint(0); j < stmt.NamedChildCount(); j++ {
+				switch child := stmt.NamedChild(j); child.Kind() {
+				case "export_clause":
+					for k := uint(0); k < child.NamedChildCount(); k++ {
+						if name := child.NamedChild(k).ChildByFieldName("name"); name != nil {
+							m.export(nodeText(name, content))
+						}
+					}
+				case "identifier":
+					// export default a; export = a
+					m.export(nodeText(child, content))
+				}
+			}
+		}
+	}
+	return m
+}
+
+// export records name as exported by the module.
+func (m *esModule) export(name string) {
+	if m.exports == nil {
+		m.exports = make(map[string]bool)
+	}
+	m.exports[name] = true
+}
+
+// typeScriptVisibility returns public for exported declarations, top-level
+// declarations named in an export list or default export, and class or
+// interface members, and file for other declarations of a module.
+func typeScriptVisibility(name string, sigNode *sitter.Node, m esModule) parser.Visibility {
+	for n := sigNode; n != nil; n = n.Parent() {
+		switch n.Kind() {
+		case "export_statement", "class_body", "interface_body", "object_type", "enum_body":
+			return parser.VisibilityPublic
+		case "statement_block":
+			return parser.VisibilityFile
+		}
+	}
+	if m.module && !m.exports[name] {
+		return parser.VisibilityFile
+	}
+	return parser.VisibilityPublic
+}
+
+// rustVisibility returns the visibility of a Rust item without "pub":
+// trait members, trait implementations and enum variants take the
+// visibility of their container, impl and extern blocks restrict nothing,
+// "#[macro_export]" macros are public, and all other items are private.
+func rustVisibility(sig *parser.Signature, sigNode *sitter.Node) parser.Visibility {
+	switch sigNode.Kind() {
+	case "impl_item", "foreign_mod_item", "enum_variant":
+		return parser.VisibilityPublic
+	case "macro_definition":
+		for _, dec := range sig.Decorators {
+			if dec.Name == "macro_export" {
+				return parser.VisibilityPublic
+			}
+		}
+	}
+	if list := sigNode.Parent(); list != nil && list.Kind() == "declaration_list" {
+		if owner := list.Parent(); owner != nil {
+			if owner.Kind() == "trait_item" || (owner.Kind() == "impl_item" && owner.ChildByFieldName("trait") != nil) {
+				return parser.VisibilityPublic
+			}
+		}
+	}
+	return parser.VisibilityPrivate
+}
+
+// javaVisibility returns public for interface and annotation members and
+// enum constants, and package for other declarations without a modifier.
+func javaVisibility(sigNode *sitter.Node) parser.Visibility {
+	if sigNode.Kind() == "enum_constant" {
+		return parser.VisibilityPublic
+	}
+	for n := sigNode.Parent(); n != nil; n = n.Parent() {
+		switch n.Kind() {
+		case "interface_body", "annotation_type_body":
+			return parser.VisibilityPublic
+		case "class_body", "enum_body", "enum_body_declarations", "record_body", "block":
+			return parser.VisibilityPackage
+		}
+	}
+	return parser.VisibilityPackage
+}
+
+// csharpVisibility returns the C# default visibility: public for
+// namespaces, interface members and enum members, private for other type
+// members, and internal for top-level types.
+func csharpVisibility(sigNode *sitter.Node) parser.Visibility {
+	switch sigNode.Kind() {
+	case "namespace_declaration", "file_scoped_namespace_declaration":
+		return parser.VisibilityPublic
+	}
+	for n := sigNode.Parent(); n != nil; n = n.Parent() {
+		switch n.Kind() {
+		case "enum_member_declaration_list":
+			return parser.VisibilityPublic
+		case "declaration_list":
+			owner := n.Parent()
+			if owner == nil {
+				return parser.VisibilityInternal
+			}
+			switch owner.Kind() {
+			case "interface_declaration":
+				return parser.VisibilityPublic
+			case "namespace_declaration":
+				return parser.VisibilityInternal
+			}
+			return parser.VisibilityPrivate
+		}
+	}
+	return parser.VisibilityInternal
+}
+
+// cppVisibility returns the visibility of a C++ declaration: the access
+// section of a class member (private by default in classes, public in
+// structs and unions), file for "static" and anonymous-namespace
+// declarations at namespace scope, and public otherwise.
+func cppVisibility(sigNode *sitter.Node, head string, content []byte) parser.Visibility {
+	for n, p := sigNode, sigNode.Parent(); p != nil; n, p = p, p.Parent() {
+		switch p.Kind() {
+		case "field_declaration_list":
+			return cppAccess(n, p, content)
+		case "namespace_definition":
+			if p.ChildByFieldName("name") == nil {
+				return parser.VisibilityFile
+			}
+		case "compound_statement":
+			return parser.VisibilityFile
+		}
+	}
+	if strings.Contains(" "+head+" ", " static ") {
+		return parser.VisibilityFile
+	}
+	return parser.VisibilityPublic
+}
+
+// cppAccess returns the access of member, a child of the class body list:
+// the nearest preceding access specifier, or the default of the class key.
+func cppAccess(member, list *sitter.Node, content []byte) parser.Visibility {
+	for s := member.PrevSibling(); s != nil; s = s.PrevSibling() {
+		if s.Kind() == "access_specifier" {
+			switch nodeText(s, content) {
+			case "public":
+				return parser.VisibilityPublic
+			case "protected":
+				return parser.VisibilityProtected
+			}
+			return parser.VisibilityPrivate
+		}
+	}
+	if owner := list.Parent(); owner != nil && owner.Kind() == "class_specifier" {
+		return parser.VisibilityPrivate
+	}
+	return parser.VisibilityPublic
+}
+
+// swiftVisibility returns public for protocol requirements and enum cases,
+// which take the visibility of their container, and internal (Swift's
+// default) otherwise.
+func swiftVisibility(sigNode *sitter.Node) parser.Visibility {
+	if sigNode.Kind() == "enum_entry" {
+		return parser.VisibilityPublic
+	}
+	for n := sigNode.Parent(); n != nil; n = n.Parent() {
+		if n.Kind() == "protocol_declaration" {
+			return parser.VisibilityPublic
+		}
+	}
+	return parser.VisibilityInternal
+}
+
+// rubyVisibility returns the visibility of a Ruby method: that of an
+// enclosing "private def ..." call, or else that of the nearest preceding
+// bare "private", "protected" or "public" in the class body. Singleton
+// methods (def self.x) are not affected and are public.
+func rubyVisibility(sigNode *sitter.Node, content []byte) parser.Visibility {
+	if sigNode.Kind() != "method" {
+		return parser.VisibilityPublic
+	}
+	if args := sigNode.Parent(); args != nil && args.Kind() == "argument_list" {
+		if call := args.Parent(); call != nil && call.Kind() == "call" {
+			if m := call.ChildByFieldName("method"); m != nil {
+				if v, ok := rubyVisibilityMethods[nodeText(m, content)]; ok {
+					return v
+				}
+			}
+		}
+	}
+	for s := sigNode.PrevNamedSibling(); s != nil; s = s.PrevNamedSibling() {
+		if s.Kind() != "identifier" {
+			continue
+		}
+		if v, ok := rubyVisibilityMethods[nodeText(s, content)]; ok {
+			return v
+		}
+	}
+	return parser.VisibilityPublic
+}
+
+// clampVisibility makes members no more visible than their containers
+// (e.g., a public method of a non-exported class) and sets Exported from
+// the resulting visibility. parents holds the container index of each
+// signature, -1 for top-level ones.
+func clampVisibility(signatures []parser.Signature, parents []int) {
+	done := make([]bool, len(signatures))
+	var resolve func(i int) parser.Visibility
+	resolve = func(i int) parser.Visibility {
+		if !done[i] {
+			done[i] = true
+			if p := parents[i]; p >= 0 {
+				signatures[i].Visibility = signatures[i].Visibility.Narrower(resolve(p))
+			}
+			signatures[i].Exported = signatures[i].Visibility.IsExported()
+		}
+		return signatures[i].Visibility
+	}
+	for i := range signatures {
+		resolve(i)
+	}
+}
@@ -0,0 +1,360 @@
+package treesitter
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/indigo-net/Brf.it/pkg/parser"
+)
+
+func TestSignatureVisibility(t *testing.T) {
+	tests := []struct {
+		name string
+		lang string
+		src  string
+		want map[string]parser.Visibility
+	}{
+		{
+			name: "go",
+			lang: "go",
+			src: `package a
+
+func Exported() {}
+
+func hidden() {}
+`,
+			want: map[string]parser.Visibility{
+				"Exported": parser.VisibilityPublic,
+				"hidden":   parser.VisibilityPackage,
+			},
+		},
+		{
+			name: "typescript module",
+			lang: "typescript",
+			src: `import { x } from "./x";
+
+export function api(): void {}
+
+function helper(): void {}
+
+export class Service {
+  run(): void {}
+  private cache(): void {}
+  protected hook(): void {}
+}
+`,
+			want: map[string]parser.Visibility{
+				"api":     parser.VisibilityPublic,
+				"helper":  parser.VisibilityFile,
+				"Service": parser.VisibilityPublic,
+				"run":     parser.VisibilityPublic,
+				"cache":   parser.VisibilityPrivate,
+				"hook":    parser.VisibilityProtected,
+			},
+		},
+		{
+			name: "javascript script",
+			lang: "javascript",
+			src: `function global() {}
+`,
+			want: map[string]parser.Visibility{
+				"global": parser.VisibilityPublic,
+			},
+		},
+		{
+			name: "typescript export list",
+			lang: "typescript",
+			src: `function publicApi(): void {}
+
+const K = 1;
+
+function renamed(): void {}
+
+function helper(): void {}
+
+function unexported(): void {}
+
+export { publicApi, K, renamed as alias };
+export { other } from "./other";
+export default helper;
+`,
+			want: map[string]parser.Visibility{
+				"publicApi":  parser.VisibilityPublic,
+				"K":          parser.VisibilityPublic,
+				"renamed":    parser.VisibilityPublic,
+				"helper":     parser.VisibilityPublic,
+				"unexported": parser.VisibilityFile,
+			},
+		},
+		{
+			name: "typescript export assignment",
+			lang: "typescript",
+			src: `function main(): void {}
+
+function helper(): void {}
+
+export = main;
+`,
+			want: map[string]parser.Visibility{
+				"main":   parser.VisibilityPublic,
+				"helper": parser.VisibilityFile,
+			},
+		},
+		{
+			name: "javascript export list",
+			lang: "javascript",
+			src: `function handler() {
+  function inner() {}
+}
+
+export { handler, inner };
+`,
+			want: map[string]parser.Visibility{
+				"handler": parser.VisibilityPublic,
+				"inner":   parser.VisibilityFile,
+			},
+		},
+		{
+			name: "rust",
+			lang: "rust",
+			src: `pub fn api() {}
+pub(crate) fn shared() {}
+pub(super) fn parent() {}
+fn helper() {}
+
+struct Inner {}
+
+impl Inner {
+    pub fn method(&self) {}
+}
+
+pub trait Greet {
+    fn greet(&self);
+}
+`,
+			want: map[string]parser.Visibility{
+				"api":    parser.VisibilityPublic,
+				"shared": parser.VisibilityInternal,
+				"parent": parser.VisibilityPackage,
+				"helper": parser.VisibilityPrivate,
+				"method": parser.VisibilityPublic,
+				"greet":  parser.VisibilityPublic,
+			},
+		},
+		{
+			name: "java",
+			lang: "java",
+			src: `public class A {
+    public void a() {}
+    protected void b() {}
+    void c() {}
+    private void d() {}
+}
+
+interface I {
+    void e();
+}
+`,
+			want: map[string]parser.Visibility{
+				"a": parser.VisibilityPublic,
+				"b": parser.VisibilityProtected,
+				"c": parser.VisibilityPackage,
+				"d": parser.VisibilityPrivate,
+				"e": parser.VisibilityPackage,
+			},
+		},
+		{
+			name: "cpp",
+			lang: "cpp",
+			src: `class A {
+    void hidden();
+public:
+    void shown();
+protected:
+    void hook();
+};
+
+struct B {
+    void open();
+};
+
+static void local() {}
+
+void global() {}
+`,
+			want: map[string]parser.Visibility{
+				"hidden": parser.VisibilityPrivate,
+				"shown":  parser.VisibilityPublic,
+				"hook":   parser.VisibilityProtected,
+				"open":   parser.VisibilityPublic,
+				"local":  parser.VisibilityFile,
+				"global": parser.VisibilityPublic,
+			},
+		},
+		{
+			name: "python",
+			lang: "python",
+			src: `def api():
+    pass
+
+def _helper():
+    pass
+
+class A:
+    def __init__(self):
+        pass
+
+    def __secret(self):
+        pass
+`,
+			want: map[string]parser.Visibility{
+				"api":      parser.VisibilityPublic,
+				"_helper":  parser.VisibilityPrivate,
+				"__init__": parser.VisibilityPublic,
+				"__secret": parser.VisibilityPrivate,
+			},
+		},
+		{
+			name: "ruby",
+			lang: "ruby",
+			src: `class A
+  def shown
+  end
+
+  private
+
+  def hidden
+  end
+
+  protected
+
+  def hook
+  end
+end
+`,
+			want: map[string]parser.Visibility{
+				"shown":  parser.VisibilityPublic,
+				"hidden": parser.VisibilityPrivate,
+				"hook":   parser.VisibilityProtected,
+			},
+		},
+		{
+			name: "c",
+			lang: "c",
+			src: `static int local(void) { return 0; }
+int global(void) { return 0; }
+`,
+			want: map[string]parser.Visibility{
+				"local":  parser.VisibilityFile,
+				"global": parser.VisibilityPublic,
+			},
+		},
+	}
+
+	p := NewTreeSitterParser()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := p.Parse([]byte(tt.src), &parser.Options{Language: tt.lang, IncludePrivate: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make(map[string]parser.Signature)
+			for _, sig := range result.Signatures {
+				if _, ok := got[sig.Name]; !ok {
+					got[sig.Name] = sig
+				}
+			}
+			for name, want := range tt.want {
+				sig, ok := got[name]
+				if !ok {
+					t.Errorf("%s: not found", name)
+					continue
+				}
+				if sig.Visibility != want {
+					t.Errorf("%s: visibility = %q, want %q", name, sig.Visibility, want)
+				}
+				if sig.Exported != want.IsExported() {
+					t.Errorf("%s: exported = %v, want %v", name, sig.Exported, want.IsExported())
+				}
+			}
+		})
+	}
+}
+
+func TestVisibilityFilter(t *testing.T) {
+	src := []byte(`export function api(): void {}
+function helper(): void {}
+`)
+	tests := []struct {
+		name string
+		opts parser.Options
+		want []string
+	}{
+		{name: "default", opts: parser.Options{}, want: []string{"api"}},
+		{name: "file only", opts: parser.Options{Visibility: []parser.Visibility{parser.VisibilityFile}}, want: []string{"helper"}},
+		{name: "include private", opts: parser.Options{IncludePrivate: true, Visibility: []parser.Visibility{parser.VisibilityFile}}, want: []string{"api", "helper"}},
+	}
+
+	p := NewTreeSitterParser()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.Language = "typescript"
+			result, err := p.Parse(src, &tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Exported declarations are also captured as "export" signatures
+			var got []string
+			for _, sig := range result.Signatures {
+				if !slices.Contains(got, sig.Name) {
+					got = append(got, sig.Name)
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestDefaultVisibilityJava checks that Java declarations without an access
+// modifier (package-private) stay in the default output.
+func TestDefaultVisibilityJava(t *testing.T) {
+	checkDefaultSignatures(t, "java", `class Service {
+    void start() {}
+    private void stop() {}
+}
+`, []string{"Service", "start"})
+}
+
+// TestDefaultVisibilitySwift checks that Swift declarations without an
+// access modifier (internal) stay in the default output.
+func TestDefaultVisibilitySwift(t *testing.T) {
+	checkDefaultSignatures(t, "swift", `class Service {
+    func start() {}
+    private func stop() {}
+}
+
+func helper() {}
+`, []string{"Service", "start", "helper"})
+}
+
+// checkDefaultSignatures parses src with the default options and checks
+// the names of the signatures kept.
+func checkDefaultSignatures(t *testing.T, lang, src string, want []string) {
+	t.Helper()
+	p := NewTreeSitterParser()
+	result, err := p.Parse([]byte(src), &parser.Options{Language: lang})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, sig := range result.Signatures {
+		if !slices.Contains(got, sig.Name) {
+			got = append(got, sig.Name)
+		}
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,122 @@
+package parser
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Visibility is the access level of a declaration.
+type Visibility string
+
+// Visibility levels, from most to least visible.
+const (
+	// VisibilityPublic is visible everywhere (e.g., Go exported names, Rust
+	// "pub", TypeScript exports, Java "public").
+	VisibilityPublic Visibility = "public"
+
+	// VisibilityProtected is visible to subclasses.
+	VisibilityProtected Visibility = "protected"
+
+	// VisibilityInternal is visible within the crate, assembly or module
+	// (e.g., Rust "pub(crate)", C# and Kotlin "internal", Swift's default).
+	VisibilityInternal Visibility = "internal"
+
+	// VisibilityPackage is visible within the package or parent module
+	// (e.g., Go unexported names, Java's default, Rust "pub(super)").
+	VisibilityPackage Visibility = "package"
+
+	// VisibilityFile is visible within the file (e.g., C "static",
+	// non-exported TypeScript declarations, Swift "fileprivate").
+	VisibilityFile Visibility = "file"
+
+	// VisibilityPrivate is visible within the enclosing type or module only.
+	VisibilityPrivate Visibility = "private"
+)
+
+// AllVisibility lists every visibility level, from most to least visible.
+var AllVisibility = []Visibility{
+	VisibilityPublic,
+	VisibilityProtected,
+	VisibilityInternal,
+	VisibilityPackage,
+	VisibilityFile,
+	VisibilityPrivate,
+}
+
+// DefaultVisibility lists the visibility levels included by default: the
+// API that code outside the package or module can use.
+var DefaultVisibility = []Visibility{VisibilityPublic, VisibilityProtected}
+
+// implicitVisibility maps languages whose declarations without an access
+// modifier are not public to that implicit level. LanguageDefaultVisibility
+// adds it so that unannotated code, which is most Java and Swift code, stays
+// in the default output.
+var implicitVisibility = map[string]Visibility{
+	"java":   VisibilityPackage,
+	"csharp": VisibilityInternal,
+	"swift":  VisibilityInternal,
+}
+
+// LanguageDefaultVisibility returns the visibility levels included by
+// default in files of language: DefaultVisibility, plus the implicit access
+// level of the language (package in Java, internal in C# and Swift).
+func LanguageDefaultVisibility(language string) []Visibility {
+	if v, ok := implicitVisibility[language]; ok {
+		return append(slices.Clone(DefaultVisibility), v)
+	}
+	return DefaultVisibility
+}
+
+// ParseVisibility parses visibility levels given as list entries and/or
+// comma-separated values (e.g., "public,protected"). "all" selects every
+// level. Duplicates are dropped.
+func ParseVisibility(values []string) ([]Visibility, error) {
+	var levels []Visibility
+	all := false
+	for _, value := range values {
+		for _, field := range strings.Split(value, ",") {
+			field = strings.ToLower(strings.TrimSpace(field))
+			if field == "" {
+				continue
+			}
+			if field == "all" {
+				all = true
+				continue
+			}
+			v := Visibility(field)
+			if !slices.Contains(AllVisibility, v) {
+				return nil, fmt.Errorf("invalid visibility %q: must be one of public, protected, internal, package, file, private or all", field)
+			}
+			if !slices.Contains(levels, v) {
+				levels = append(levels, v)
+			}
+		}
+	}
+	if all {
+		return slices.Clone(AllVisibility), nil
+	}
+	return levels, nil
+}
+
+// IncludesVisibility reports whether levels selects v in a file of the
+// given language. Empty levels select LanguageDefaultVisibility(language).
+func IncludesVisibility(language string, levels []Visibility, v Visibility) bool {
+	if len(levels) == 0 {
+		levels = LanguageDefaultVisibility(language)
+	}
+	return slices.Contains(levels, v)
+}
+
+// IsExported reports whether v is part of the public API (public or protected).
+func (v Visibility) IsExported() bool {
+	return v == VisibilityPublic || v == VisibilityProtected
+}
+
+// Narrower returns the less visible of v and other.
+func (v Visibility) Narrower(other Visibility) Visibility {
+	if slices.Index(AllVisibility, other) > slices.Index(AllVisibility, v) {
+		return other
+	}
+	return v
+}
@@ -0,0 +1,88 @@
+package parser
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseVisibility(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  []string
+		want    []Visibility
+		wantErr bool
+	}{
+		{name: "empty", values: nil, want: nil},
+		{name: "comma-separated", values: []string{"public,protected"}, want: []Visibility{VisibilityPublic, VisibilityProtected}},
+		{name: "entries and spaces", values: []string{"Internal", " file , private"}, want: []Visibility{VisibilityInternal, VisibilityFile, VisibilityPrivate}},
+		{name: "duplicates", values: []string{"public", "public,package"}, want: []Visibility{VisibilityPublic, VisibilityPackage}},
+		{name: "all", values: []string{"public,all"}, want: AllVisibility},
+		{name: "invalid", values: []string{"public,secret"}, wantErr: true},
+		{name: "invalid after all", values: []string{"all,bogus"}, wantErr: true},
+		{name: "invalid entry after all", values: []string{"all", "bogus"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseVisibility(tt.values)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIncludesVisibility(t *testing.T) {
+	tests := []struct {
+		language string
+		levels   []Visibility
+		v        Visibility
+		want     bool
+	}{
+		{language: "go", levels: nil, v: VisibilityPublic, want: true},
+		{language: "go", levels: nil, v: VisibilityProtected, want: true},
+		{language: "go", levels: nil, v: VisibilityPackage, want: false},
+		{language: "java", levels: nil, v: VisibilityPackage, want: true},
+		{language: "java", levels: nil, v: VisibilityPrivate, want: false},
+		{language: "swift", levels: nil, v: VisibilityInternal, want: true},
+		{language: "swift", levels: nil, v: VisibilityFile, want: false},
+		{language: "csharp", levels: nil, v: VisibilityInternal, want: true},
+		{language: "kotlin", levels: nil, v: VisibilityInternal, want: false},
+		{language: "java", levels: []Visibility{VisibilityPublic}, v: VisibilityPackage, want: false},
+		{language: "go", levels: []Visibility{VisibilityPrivate}, v: VisibilityPrivate, want: true},
+		{language: "go", levels: []Visibility{VisibilityPrivate}, v: VisibilityPublic, want: false},
+	}
+
+	for _, tt := range tests {
+		if got := IncludesVisibility(tt.language, tt.levels, tt.v); got != tt.want {
+			t.Errorf("IncludesVisibility(%q, %v, %q) = %v, want %v", tt.language, tt.levels, tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestVisibilityNarrower(t *testing.T) {
+	tests := []struct {
+		a, b Visibility
+		want Visibility
+	}{
+		{a: VisibilityPublic, b: VisibilityFile, want: VisibilityFile},
+		{a: VisibilityPrivate, b: VisibilityPublic, want: VisibilityPrivate},
+		{a: VisibilityProtected, b: VisibilityInternal, want: VisibilityInternal},
+		{a: VisibilityPackage, b: VisibilityPackage, want: VisibilityPackage},
+	}
+
+	for _, tt := range tests {
+		if got := tt.a.Narrower(tt.b); got != tt.want {
+			t.Errorf("%q.Narrower(%q) = %q, want %q", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package scanner
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	ignore "github.com/sabhiram/go-gitignore"
+)
+
+// GitIgnoreFile is the IgnoreFiles entry that enables git-style ignore
+// handling: .gitignore files are loaded per directory while walking, plus
+// .git/info/exclude and core.excludesFile of the enclosing repository.
+const GitIgnoreFile = ".gitignore"
+
+// BrfitIgnoreFile is a brfit-only ignore file, loaded per directory like
+// .gitignore. Its patterns take precedence over .gitignore in the same directory.
+const BrfitIgnoreFile = ".brfitignore"
+
+// ignoreRule is a single pattern line of an ignore file.
+type ignoreRule struct {
+	// matcher is compiled from the pattern without its "!" prefix.
+	matcher *ignore.GitIgnore
+
+	// negate indicates a "!" pattern that re-includes matching paths.
+	negate bool
+}
+
+// ignoreList holds the rules of one ignore file. Patterns are matched
+// against paths relative to base, as git does for .gitignore files.
+type ignoreList struct {
+	base  string
+	rules []ignoreRule
+}
+
+// parseIgnoreLines compiles ignore file lines whose patterns are relative to base.
+func parseIgnoreLines(base string, lines []string) *ignoreList {
+	l := &ignoreList{base: base}
+	for _, line := range lines {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		negate := strings.HasPrefix(line, "!")
+		if negate {
+			line = line[1:]
+		}
+		l.rules = append(l.rules, ignoreRule{matcher: ignore.CompileIgnoreLines(line), negate: negate})
+	}
+	return l
+}
+
+// loadIgnoreFile reads the ignore file at path with patterns relative to base.
+func loadIgnoreFile(path, base string) (*ignoreList, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseIgnoreLines(base, strings.Split(string(content), "\n")), nil
+}
+
+// match reports whether the list decides on path: ok is false when no rule
+// matches, otherwise ignored tells whether the last matching rule ignores it.
+func (l *ignoreList) match(path string, isDir bool) (ignored, ok bool) {
+	rel, err := filepath.Rel(l.base, path)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return false, false
+	}
+	rel = filepath.ToSlash(rel)
+	// Directory-only patterns ("build/") need the trailing slash to match
+	if isDir {
+		rel += "/"
+	}
+	for i := len(l.rules) - 1; i >= 0; i-- {
+		if l.rules[i].matcher.MatchesPath(rel) {
+			return !l.rules[i].negate, true
+		}
+	}
+	return false, false
+}
+
+// findGitDir walks up from dir to the enclosing repository. It returns the
+// work tree root and the git directory, or empty strings outside a repository.
+func findGitDir(dir string) (top, gitDir string) {
+	for {
+		dotGit := filepath.Join(dir, ".git")
+		if info, err := os.Stat(dotGit); err == nil {
+			if info.IsDir() {
+				return dir, dotGit
+			}
+			// Worktrees and submodules use a ".git" file pointing at the git directory
+			if content, err := os.ReadFile(dotGit); err == nil {
+				if target, ok := strings.CutPrefix(strings.TrimSpace(string(content)), "gitdir:"); ok {
+					target = strings.TrimSpace(target)
+					if !filepath.IsAbs(target) {
+						target = filepath.Join(dir, target)
+					}
+					return dir, target
+				}
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", ""
+		}
+		dir = parent
+	}
+}
+
+// gitCommonDir resolves the directory holding info/exclude. Linked worktrees
+// share it with the main repository via a "commondir" file.
+func gitCommonDir(gitDir string) string {
+	content, err := os.ReadFile(filepath.Join(gitDir, "commondir"))
+	if err != nil {
+		return gitDir
+	}
+	common := strings.TrimSpace(string(content))
+	if !filepath.IsAbs(common) {
+		common = filepath.Join(gitDir, common)
+	}
+	return common
+}
+
+// globalExcludesFile returns the path of git's core.excludesFile, falling
+// back to git's default of $XDG_CONFIG_HOME/git/ignore (~/.config/git/ignore).
+func globalExcludesFile(repoTop string) string {
+	cmd := exec.Command("git", "config", "--path", "--get", "core.excludesFile")
+	cmd.Dir = repoTop
+	if out, err := cmd.Output(); err == nil {
+		if path := string(bytes.TrimSpace(out)); path != "" {
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(repoTop, path)
+			}
+			return path
+		}
+	}
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		return filepath.Join(xdg, "git", "ignore")
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		return filepath.Join(home, ".config", "git", "ignore")
+	}
+	return ""
+}
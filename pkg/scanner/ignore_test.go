@@ -0,0 +1,237 @@
+package scanner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// writeTree creates files (relative path -> content) below root.
+func writeTree(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// scanRel scans root with the given ignore files and returns the matched
+// files relative to base, sorted.
+func scanRel(t *testing.T, root, base string, ignoreFiles []string) []string {
+	t.Helper()
+	opts := DefaultScanOptions()
+	opts.RootPath = root
+	opts.IgnoreFiles = ignoreFiles
+	s, err := NewFileScanner(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := s.Scan(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, f := range result.Files {
+		rel, err := filepath.Rel(base, f.Path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, filepath.ToSlash(rel))
+	}
+	sort.Strings(got)
+	return got
+}
+
+// isolateGitConfig keeps the user's global git configuration out of the test
+// and points the default core.excludesFile location at a temp directory.
+func isolateGitConfig(t *testing.T) string {
+	t.Helper()
+	xdg := t.TempDir()
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+	return xdg
+}
+
+func TestScanHierarchicalGitignore(t *testing.T) {
+	isolateGitConfig(t)
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		".gitignore":           "*.gen.go\n/build/\n",
+		"main.go":              "package main\n",
+		"api.gen.go":           "package main\n",
+		"build/out.go":         "package build\n",
+		"pkg/.gitignore":       "/local.go\n!keep.gen.go\n",
+		"pkg/lib.go":           "package pkg\n",
+		"pkg/local.go":         "package pkg\n",
+		"pkg/keep.gen.go":      "package pkg\n",
+		"pkg/drop.gen.go":      "package pkg\n",
+		"pkg/build/ok.go":      "package build\n",
+		"pkg/sub/local.go":     "package sub\n",
+		"pkg/sub/.gitignore":   "lib.go\n",
+		"pkg/sub/lib.go":       "package sub\n",
+		"other/local.go":       "package other\n",
+		"other/nested/main.go": "package nested\n",
+	})
+
+	got := scanRel(t, root, root, []string{GitIgnoreFile})
+	want := []string{
+		"main.go",
+		"other/local.go", // "/local.go" is anchored to pkg/
+		"other/nested/main.go",
+		"pkg/build/ok.go",  // "/build/" is anchored to the root
+		"pkg/keep.gen.go",  // re-included by the deeper .gitignore
+		"pkg/lib.go",       // "lib.go" only applies below pkg/sub
+		"pkg/sub/local.go", // "/local.go" only matches pkg/local.go
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got files:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestScanSubdirectoryUsesRepositoryIgnores(t *testing.T) {
+	xdg := isolateGitConfig(t)
+	repo := t.TempDir()
+	writeTree(t, repo, map[string]string{
+		".git/info/exclude":     "*.local.go\n",
+		".gitignore":            "/svc/api/internal/\nmocks/\n",
+		"svc/api/main.go":       "package api\n",
+		"svc/api/dev.local.go":  "package api\n",
+		"svc/api/secret.go":     "package api\n",
+		"svc/api/internal/x.go": "package internal\n",
+		"svc/api/mocks/m.go":    "package mocks\n",
+	})
+	writeTree(t, xdg, map[string]string{"git/ignore": "secret.go\n"})
+
+	// Run from an unrelated working directory: the repository's ignore files
+	// must apply, not the ones in the process working directory.
+	t.Chdir(t.TempDir())
+	writeTree(t, ".", map[string]string{".gitignore": "main.go\n"})
+
+	dir := filepath.Join(repo, "svc", "api")
+	got := scanRel(t, dir, dir, []string{GitIgnoreFile})
+	if strings.Join(got, ",") != "main.go" {
+		t.Errorf("got %v, want [main.go]", got)
+	}
+}
+
+func TestScanBrfitignore(t *testing.T) {
+	isolateGitConfig(t)
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		".gitignore":         "gen/\n",
+		"gen/.brfitignore":   "",
+		".brfitignore":       "testdata/\n!gen/\n",
+		"main.go":            "package main\n",
+		"gen/api.go":         "package gen\n",
+		"testdata/sample.go": "package testdata\n",
+		"pkg/.brfitignore":   "*_test.go\n",
+		"pkg/a.go":           "package pkg\n",
+		"pkg/a_test.go":      "package pkg\n",
+		"a_test.go":          "package main\n",
+	})
+
+	t.Run("with gitignore", func(t *testing.T) {
+		got := scanRel(t, root, root, []string{GitIgnoreFile})
+		want := "a_test.go,gen/api.go,main.go,pkg/a.go"
+		if strings.Join(got, ",") != want {
+			t.Errorf("got %v, want %s", got, want)
+		}
+	})
+
+	t.Run("without gitignore", func(t *testing.T) {
+		// .brfitignore applies even when .gitignore handling is disabled
+		got := scanRel(t, root, root, nil)
+		want := "a_test.go,gen/api.go,main.go,pkg/a.go"
+		if strings.Join(got, ",") != want {
+			t.Errorf("got %v, want %s", got, want)
+		}
+	})
+}
+
+func TestScanWithoutGitignoreEntry(t *testing.T) {
+	isolateGitConfig(t)
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		".gitignore":    "ignored.go\n",
+		"main.go":       "package main\n",
+		"ignored.go":    "package main\n",
+		"custom.ignore": "/main.go\n",
+	})
+
+	got := scanRel(t, root, root, []string{filepath.Join(root, "custom.ignore")})
+	if strings.Join(got, ",") != "ignored.go" {
+		t.Errorf("got %v, want [ignored.go]", got)
+	}
+}
+
+func TestScanSingleFileHonorsGitignore(t *testing.T) {
+	isolateGitConfig(t)
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		".gitignore": "gen.go\n",
+		"gen.go":     "package main\n",
+	})
+	file := filepath.Join(root, "gen.go")
+	if got := scanRel(t, file, root, []string{GitIgnoreFile}); len(got) != 0 {
+		t.Errorf("expected ignored file to be skipped, got %v", got)
+	}
+}
+
+func TestIgnoreListMatch(t *testing.T) {
+	base := filepath.FromSlash("/repo")
+	l := parseIgnoreLines(base, []string{"# comment", "", "build/", "*.log", "!keep.log", "/root.go\r"})
+
+	tests := []struct {
+		path        string
+		isDir       bool
+		wantIgnored bool
+		wantOK      bool
+	}{
+		{"/repo/build", true, true, true},
+		{"/repo/build", false, false, false}, // directory-only pattern
+		{"/repo/a/build", true, true, true},
+		{"/repo/x.log", false, true, true},
+		{"/repo/keep.log", false, false, true},
+		{"/repo/root.go", false, true, true},
+		{"/repo/sub/root.go", false, false, false},
+		{"/other/x.log", false, false, false}, // outside base
+	}
+	for _, tt := range tests {
+		ignored, ok := l.match(filepath.FromSlash(tt.path), tt.isDir)
+		if ignored != tt.wantIgnored || ok != tt.wantOK {
+			t.Errorf("match(%q, dir=%v) = (%v, %v), want (%v, %v)",
+				tt.path, tt.isDir, ignored, ok, tt.wantIgnored, tt.wantOK)
+		}
+	}
+}
+
+func TestFindGitDir(t *testing.T) {
+	repo := t.TempDir()
+	writeTree(t, repo, map[string]string{".git/HEAD": "ref: refs/heads/main\n", "a/b/c.go": ""})
+
+	top, gitDir := findGitDir(filepath.Join(repo, "a", "b"))
+	if top != repo || gitDir != filepath.Join(repo, ".git") {
+		t.Errorf("findGitDir = (%q, %q), want (%q, %q)", top, gitDir, repo, filepath.Join(repo, ".git"))
+	}
+
+	// Linked worktree: .git file pointing at a git dir with a commondir
+	wt := t.TempDir()
+	writeTree(t, wt, map[string]string{".git": "gitdir: " + filepath.Join(repo, ".git", "worktrees", "wt") + "\n"})
+	writeTree(t, repo, map[string]string{".git/worktrees/wt/commondir": "../..\n"})
+	top, gitDir = findGitDir(wt)
+	if top != wt || gitDir != filepath.Join(repo, ".git", "worktrees", "wt") {
+		t.Errorf("findGitDir(worktree) = (%q, %q)", top, gitDir)
+	}
+	if got := gitCommonDir(gitDir); got != filepath.Join(repo, ".git") {
+		t.Errorf("gitCommonDir = %q, want %q", got, filepath.Join(repo, ".git"))
+	}
+}
@@ -12,7 +12,6 @@ import (
 	"strings"
 
 	"github.com/bmatcuk/doublestar/v4"
-	ignore "github.com/sabhiram/go-gitignore"
 	"github.com/indigo-net/Brf.it/pkg/parser"
 )
 
@@ -56,6 +55,11 @@ type ScanOptions struct {
 	SupportedExtensions map[string]string
 
 	// IgnoreFiles is the list of ignore file paths (default: [".gitignore"]).
+	// The bare name ".gitignore" (GitIgnoreFile) enables git-style handling:
+	// .gitignore files are loaded per directory, along with .git/info/exclude
+	// and core.excludesFile. Other entries are read as given and their
+	// patterns are matched relative to RootPath. .brfitignore files
+	// (BrfitIgnoreFile) are always loaded per directory.
 	IgnoreFiles []string
 
 	// IncludePatterns is a list of glob patterns to include.
@@ -139,8 +143,18 @@ type Scanner interface {
 
 // FileScanner implements Scanner for file system traversal.
 type FileScanner struct {
-	opts              *ScanOptions
-	ignorers          []*ignore.GitIgnore
+	opts *ScanOptions
+
+	// Ignore rules, in increasing precedence: baseIgnores (core.excludesFile,
+	// .git/info/exclude), dirIgnores for each directory from ignoreTop down
+	// to the file, then explicitIgnores (IgnoreFiles entries).
+	baseIgnores     []*ignoreList
+	dirIgnores      map[string][]*ignoreList
+	explicitIgnores []*ignoreList
+	ignoreTop       string
+	gitIgnore       bool
+	cwd             string // resolves relative paths for ignore matching
+
 	ignorerErrs       []error
 	ignorerErrsWarned bool
 	logger            *log.Logger
@@ -179,25 +193,94 @@ func NewFileScanner(opts *ScanOptions) (*FileScanner, error) {
 		logger:     log.New(os.Stderr, "[brfit] ", 0),
 		rootIsFile: rootIsFile,
 	}
+	s.cwd, _ = os.Getwd()
+	s.loadIgnoreFiles()
+
+	return s, nil
+}
 
-	// Try to load each ignore file
-	for _, ignoreFile := range opts.IgnoreFiles {
+// loadIgnoreFiles loads the ignore files that apply above and at the walk
+// root. Ignore files in subdirectories are loaded while walking.
+func (s *FileScanner) loadIgnoreFiles() {
+	walkRoot := s.opts.RootPath
+	if s.rootIsFile {
+		walkRoot = filepath.Dir(walkRoot)
+	}
+	walkRoot = s.absPath(walkRoot)
+
+	// Explicit ignore files are matched relative to the scanned directory
+	for _, ignoreFile := range s.opts.IgnoreFiles {
 		if ignoreFile == "" {
 			continue
 		}
-		ignorer, err := ignore.CompileIgnoreFile(ignoreFile)
+		if ignoreFile == GitIgnoreFile {
+			s.gitIgnore = true
+			continue
+		}
+		l, err := loadIgnoreFile(ignoreFile, walkRoot)
 		if err != nil {
-			// Default .gitignore not found is normal; only store error for
-			// user-specified ignore files or non-file-not-found errors.
-			if !(errors.Is(err, os.ErrNotExist) && ignoreFile == ".gitignore") {
-				s.ignorerErrs = append(s.ignorerErrs, fmt.Errorf("%s: %w", ignoreFile, err))
+			s.ignorerErrs = append(s.ignorerErrs, fmt.Errorf("%s: %w", ignoreFile, err))
+			continue
+		}
+		s.explicitIgnores = append(s.explicitIgnores, l)
+	}
+
+	// Per-directory files apply from the repository root down, so scanning a
+	// subdirectory still honors the .gitignore files above it.
+	s.dirIgnores = make(map[string][]*ignoreList)
+	s.ignoreTop = walkRoot
+	if s.gitIgnore {
+		if top, gitDir := findGitDir(walkRoot); top != "" {
+			s.ignoreTop = top
+			if path := globalExcludesFile(top); path != "" {
+				s.loadBaseIgnore(path, top)
 			}
-		} else {
-			s.ignorers = append(s.ignorers, ignorer)
+			s.loadBaseIgnore(filepath.Join(gitCommonDir(gitDir), "info", "exclude"), top)
+		}
+	}
+	for dir := walkRoot; ; dir = filepath.Dir(dir) {
+		s.loadDirIgnores(dir)
+		if dir == s.ignoreTop || filepath.Dir(dir) == dir {
+			break
 		}
 	}
+}
 
-	return s, nil
+// loadBaseIgnore appends an optional repository-wide ignore file to baseIgnores.
+func (s *FileScanner) loadBaseIgnore(path, base string) {
+	l, err := loadIgnoreFile(path, base)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			s.ignorerErrs = append(s.ignorerErrs, fmt.Errorf("%s: %w", path, err))
+		}
+		return
+	}
+	s.baseIgnores = append(s.baseIgnores, l)
+}
+
+// loadDirIgnores loads the .gitignore and .brfitignore files of dir, if present.
+func (s *FileScanner) loadDirIgnores(dir string) {
+	names := []string{BrfitIgnoreFile}
+	if s.gitIgnore {
+		names = []string{GitIgnoreFile, BrfitIgnoreFile}
+	}
+	var lists []*ignoreList
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		l, err := loadIgnoreFile(path, dir)
+		if err != nil {
+			if !errors.Is(err, os.ErrNotExist) {
+				s.logger.Printf("WARN: failed to load ignore file: %s: %v", path, err)
+			}
+			continue
+		}
+		lists = append(lists, l)
+	}
+	if len(lists) > 0 {
+		s.dirIgnores[dir] = lists
+	} else {
+		delete(s.dirIgnores, dir)
+	}
 }
 
 // Scan implements the Scanner interface.
@@ -276,14 +359,16 @@ func (s *FileScanner) Scan(ctx context.Context) (*ScanResult, error) {
 				if !s.opts.IncludeHidden && IsHidden(name) {
 					return filepath.SkipDir
 				}
-				// Check gitignore for directory (skip if ANY ignorer matches)
-				if s.matchesIgnore(path) {
+				// Check ignore files for directory
+				if s.matchesIgnore(path, true) {
 					return filepath.SkipDir
 				}
 				// Check exclude patterns for directory
 				if s.matchesExcludeDir(s.relPath(path)) {
 					return filepath.SkipDir
 				}
+				// Rules in this directory apply to everything below it
+				s.loadDirIgnores(s.absPath(path))
 			}
 			return nil
 		}
@@ -381,14 +466,43 @@ func (s *FileScanner) matchesExcludeDir(rel string) bool {
 	return false
 }
 
-// matchesIgnore returns true if the path matches any of the loaded ignore patterns.
-func (s *FileScanner) matchesIgnore(path string) bool {
-	for _, ig := range s.ignorers {
-		if ig.MatchesPath(path) {
-			return true
+// matchesIgnore reports whether path is ignored. As in git, the last
+// matching pattern wins, and files in deeper directories take precedence
+// over those above them.
+func (s *FileScanner) matchesIgnore(path string, isDir bool) bool {
+	path = s.absPath(path)
+	ignored := false
+	apply := func(lists []*ignoreList) {
+		for _, l := range lists {
+			if ig, ok := l.match(path, isDir); ok {
+				ignored = ig
+			}
 		}
 	}
-	return false
+
+	apply(s.baseIgnores)
+	if len(s.dirIgnores) > 0 {
+		var dirs []string
+		for dir := filepath.Dir(path); ; dir = filepath.Dir(dir) {
+			dirs = append(dirs, dir)
+			if dir == s.ignoreTop || filepath.Dir(dir) == dir {
+				break
+			}
+		}
+		for i := len(dirs) - 1; i >= 0; i-- {
+			apply(s.dirIgnores[dirs[i]])
+		}
+	}
+	apply(s.explicitIgnores)
+	return ignored
+}
+
+// absPath returns path as an absolute path.
+func (s *FileScanner) absPath(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(s.cwd, path)
 }
 
 // checkFile checks if a file should be included in the scan results.
@@ -402,8 +516,8 @@ func (s *FileScanner) checkFile(path string, info os.FileInfo) (FileEntry, bool)
 		return FileEntry{}, false
 	}
 
-	// Check gitignore (skip if ANY ignorer matches)
-	if s.matchesIgnore(path) {
+	// Check ignore files
+	if s.matchesIgnore(path, false) {
 		return FileEntry{}, false
 	}
 
@@ -83,9 +83,9 @@ func TestScanOptionsDefaults(t *testing.T) {
 	expectedExts := map[string]string{
 		".go":  "go",
 		".ts":  "typescript",
-		".tsx": "typescript",
+		".tsx": "tsx",
 		".js":  "javascript",
-		".jsx": "javascript",
+		".jsx": "jsx",
 	}
 
 	for ext, lang := range expectedExts {
@@ -117,9 +117,9 @@ func TestScanOptionsWithExtensions(t *testing.T) {
 	}{
 		{"main.go", "go", true},
 		{"app.ts", "typescript", true},
-		{"component.tsx", "typescript", true},
+		{"component.tsx", "tsx", true},
 		{"index.js", "javascript", true},
-		{"App.jsx", "javascript", true},
+		{"App.jsx", "jsx", true},
 		{"README.md", "", false},
 		{"config.json", "", false},
 		{"style.css", "", false},
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"regexp"
+	"slices"
 
 	"github.com/indigo-net/Brf.it/pkg/extractor"
 	"github.com/indigo-net/Brf.it/pkg/parser"
@@ -112,11 +113,14 @@ func (s *Scanner) scanFile(file extractor.ExtractedFile, sr *ScanResult) extract
 
 	// Secrets found — create a redacted copy
 	redacted := extractor.ExtractedFile{
-		Path:     file.Path,
-		Language: file.Language,
-		Size:     file.Size,
-		Error:    file.Error,
+		Path:        file.Path,
+		Language:    file.Language,
+		Size:        file.Size,
+		Calls:       file.Calls,
+		Diagnostics: file.Diagnostics,
+		Error:       file.Error,
 	}
+	redacted.Summary = s.redactString(file.Path, file.Summary, sr)
 
 	// Scan and redact signatures
 	redacted.Signatures = make([]parser.Signature, len(file.Signatures))
@@ -125,6 +129,22 @@ func (s *Scanner) scanFile(file extractor.ExtractedFile, sr *ScanResult) extract
 		sig := &redacted.Signatures[j]
 		sig.Text = s.redactString(file.Path, sig.Text, sr)
 		sig.Doc = s.redactString(file.Path, sig.Doc, sr)
+		sig.DeprecationNote = s.redactString(file.Path, sig.DeprecationNote, sr)
+		if len(sig.Params) > 0 {
+			// Defaults are part of Text, where their findings were already recorded
+			sig.Params = slices.Clone(sig.Params)
+			for k := range sig.Params {
+				sig.Params[k].Default = s.redactString(file.Path, sig.Params[k].Default, nil)
+			}
+		}
+		if len(sig.Decorators) > 0 {
+			// Args repeat part of the decorator text, where findings are recorded
+			sig.Decorators = slices.Clone(sig.Decorators)
+			for k := range sig.Decorators {
+				sig.Decorators[k].Text = s.redactString(file.Path, sig.Decorators[k].Text, sr)
+				sig.Decorators[k].Args = s.redactString(file.Path, sig.Decorators[k].Args, nil)
+			}
+		}
 	}
 
 	// Scan and redact raw imports
@@ -133,6 +153,9 @@ func (s *Scanner) scanFile(file extractor.ExtractedFile, sr *ScanResult) extract
 		redacted.RawImports[j] = s.redactString(file.Path, imp, sr)
 	}
 
+	// Scan and redact raw source (full mode)
+	redacted.Content = s.redactString(file.Path, file.Content, sr)
+
 	return redacted
 }
 
@@ -140,9 +163,14 @@ func (s *Scanner) scanFile(file extractor.ExtractedFile, sr *ScanResult) extract
 func (s *Scanner) containsSecret(file extractor.ExtractedFile) bool {
 	for _, sig := range file.Signatures {
 		for _, p := range s.patterns {
-			if p.Regex.MatchString(sig.Text) || p.Regex.MatchString(sig.Doc) {
+			if p.Regex.MatchString(sig.Text) || p.Regex.MatchString(sig.Doc) || p.Regex.MatchString(sig.DeprecationNote) {
 				return true
 			}
+			for _, dec := range sig.Decorators {
+				if p.Regex.MatchString(dec.Text) {
+					return true
+				}
+			}
 		}
 	}
 	for _, imp := range file.RawImports {
@@ -152,10 +180,23 @@ func (s *Scanner) containsSecret(file extractor.ExtractedFile) bool {
 			}
 		}
 	}
+	for _, p := range s.patterns {
+		if p.Regex.MatchString(file.Summary) {
+			return true
+		}
+	}
+	if file.Content != "" {
+		for _, p := range s.patterns {
+			if p.Regex.MatchString(file.Content) {
+				return true
+			}
+		}
+	}
 	return false
 }
 
 // redactString replaces any matched secrets in the string with [REDACTED].
+// Each match is recorded as a finding in sr unless sr is nil.
 func (s *Scanner) redactString(filePath, text string, sr *ScanResult) string {
 	if text == "" {
 		return text
@@ -166,10 +207,12 @@ func (s *Scanner) redactString(filePath, text string, sr *ScanResult) string {
 		name := p.Name
 		matched := false
 		result = p.Regex.ReplaceAllStringFunc(result, func(match string) string {
-			sr.Findings = append(sr.Findings, Finding{
-				FilePath:    filePath,
-				PatternName: name,
-			})
+			if sr != nil {
+				sr.Findings = append(sr.Findings, Finding{
+					FilePath:    filePath,
+					PatternName: name,
+				})
+			}
 			matched = true
 			return "[REDACTED]"
 		})